@@ -0,0 +1,50 @@
+// Command exchange-rates maintains the local exchange-rate table used to
+// convert bid amounts between currencies.
+//
+//	exchange-rates list
+//	exchange-rates set EUR USD 1.0856
+//
+// Like the server it reads config.yaml from the working directory.
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"tender-managment/internal/config"
+	"tender-managment/internal/db"
+	repository "tender-managment/internal/db/repo"
+	"tender-managment/internal/service"
+)
+
+func main() {
+	log.SetFlags(0)
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("error while loading config %v", err)
+	}
+	database := db.NewDatabase(&cfg.Database)
+	defer database.Close()
+	exchangeRateService := service.NewExchangeRateService(repository.NewExchangeRateRepository(database))
+
+	args := os.Args[1:]
+	switch {
+	case len(args) == 1 && args[0] == "list":
+		rates, err := exchangeRateService.ListRates()
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, rate := range rates {
+			fmt.Printf("%s/%s\t%s\t%s\n", rate.BaseCurrency, rate.QuoteCurrency, rate.Rate, rate.UpdatedAt.Format("2006-01-02 15:04"))
+		}
+	case len(args) == 4 && args[0] == "set":
+		rate, err := exchangeRateService.SetRate(args[1], args[2], args[3])
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("%s/%s\t%s\n", rate.BaseCurrency, rate.QuoteCurrency, rate.Rate)
+	default:
+		log.Fatal("usage: exchange-rates list | exchange-rates set BASE QUOTE RATE")
+	}
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"github.com/gin-gonic/gin"
 	"log"
 	_ "tender-managment/docs"
@@ -8,8 +9,12 @@ import (
 	"tender-managment/internal/controller"
 	"tender-managment/internal/db"
 	repository "tender-managment/internal/db/repo"
+	"tender-managment/internal/mailer"
 	"tender-managment/internal/routes"
 	"tender-managment/internal/service"
+	"tender-managment/internal/storage"
+	"tender-managment/internal/utils"
+	"time"
 )
 
 // @title Tender Managment Swagger
@@ -26,19 +31,101 @@ func main() {
 	if err != nil {
 		log.Fatalf("error while loading config %v", err)
 	}
+	if err := r.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		log.Fatalf("error while setting trusted proxies %v", err)
+	}
 	database := db.NewDatabase(&cfg.Database)
 	redis := db.NewRedisClient(&cfg.Database)
 	userRepo := repository.NewUserRepository(database)
-	authService := service.NewAuthService(userRepo)
+	keys, err := utils.LoadKeySet(cfg.Auth)
+	if err != nil {
+		log.Fatalf("error while loading auth keys %v", err)
+	}
+	utils.SetKeySet(keys)
+	utils.SetPasswordPolicy(utils.PasswordPolicy{
+		MinLength:     cfg.Auth.Password.MinLength,
+		RequireUpper:  cfg.Auth.Password.RequireUpper,
+		RequireLower:  cfg.Auth.Password.RequireLower,
+		RequireDigit:  cfg.Auth.Password.RequireDigit,
+		RequireSymbol: cfg.Auth.Password.RequireSymbol,
+	})
+	refreshTokenRepo := repository.NewRefreshTokenRepository(database)
+	if cfg.Auth.AccessTokenMinutes > 0 {
+		utils.AccessTokenTTL = time.Duration(cfg.Auth.AccessTokenMinutes) * time.Minute
+	}
+	refreshTTL := 30 * 24 * time.Hour
+	if cfg.Auth.RefreshTokenDays > 0 {
+		refreshTTL = time.Duration(cfg.Auth.RefreshTokenDays) * 24 * time.Hour
+	}
+	emailTokenRepo := repository.NewEmailTokenRepository(database)
+	mail, err := mailer.NewFileMailer(cfg.Mail.File.Dir, cfg.Mail.From)
+	if err != nil {
+		log.Fatalf("error while initializing mailer %v", err)
+	}
+	twoFactorRepo := repository.NewTwoFactorRepository(database)
+	twoFactorService := service.NewTwoFactorService(twoFactorRepo, userRepo, cfg.Auth.TOTPIssuer)
+	throttle := cfg.Auth.Throttle
+	loginThrottle := service.NewLoginThrottle(redis,
+		time.Duration(throttle.WindowMinutes)*time.Minute, time.Duration(throttle.LockoutMinutes)*time.Minute,
+		throttle.AccountLimit, throttle.IPLimit)
+	authService := service.NewAuthService(userRepo, refreshTokenRepo, emailTokenRepo, mail, twoFactorService, loginThrottle, cfg.Mail.AppURL, refreshTTL)
 	tenderRepo := repository.NewTenderRepository(database)
-	tenderService := service.NewTenderService(tenderRepo)
 	bidRepo := repository.NewBidRepository(database)
-	bidService := service.NewBidService(*bidRepo, *tenderRepo, *userRepo)
+	qualificationRepo := repository.NewQualificationRepository(database)
+	tenderService := service.NewTenderService(tenderRepo, bidRepo, userRepo, qualificationRepo)
+	exchangeRateRepo := repository.NewExchangeRateRepository(database)
+	exchangeRateService := service.NewExchangeRateService(exchangeRateRepo)
+	fileStorage, err := storage.NewLocalStorage(cfg.Storage.Local.RootDir)
+	if err != nil {
+		log.Fatalf("error while initializing storage %v", err)
+	}
+	qualificationService := service.NewQualificationService(qualificationRepo, tenderRepo, bidRepo, exchangeRateService, fileStorage, cfg.Storage.MaxFileSize, cfg.Storage.AllowedTypes)
+	evaluationRepo := repository.NewEvaluationRepository(database)
+	declarationRepo := repository.NewDeclarationRepository(database)
+	evaluationService := service.NewEvaluationService(evaluationRepo, declarationRepo, tenderRepo, bidRepo, userRepo)
+	protestRepo := repository.NewProtestRepository(database)
+	standstill := time.Duration(cfg.Procurement.StandstillDays) * 24 * time.Hour
+	protestService := service.NewProtestService(protestRepo, tenderRepo, bidRepo, fileStorage, standstill, cfg.Storage.MaxFileSize, cfg.Storage.AllowedTypes)
+	bidService := service.NewBidService(*bidRepo, *tenderRepo, *userRepo, exchangeRateService, qualificationService, evaluationService, protestService)
+	contractRepo := repository.NewContractRepository(database)
+	milestoneRepo := repository.NewMilestoneRepository(database)
+	milestoneService := service.NewMilestoneService(milestoneRepo, contractRepo, bidRepo, fileStorage, cfg.Storage.MaxFileSize, cfg.Storage.AllowedTypes)
+	contractService := service.NewContractService(contractRepo, tenderRepo, bidRepo, protestService, milestoneService)
+	invoiceRepo := repository.NewInvoiceRepository(database)
+	invoiceService := service.NewInvoiceService(invoiceRepo, contractRepo, milestoneRepo, userRepo, bidRepo)
+	reviewRepo := repository.NewReviewRepository(database)
+	reviewService := service.NewReviewService(reviewRepo, contractRepo, bidRepo)
+	organizationRepo := repository.NewOrganizationRepository(database)
+	organizationService := service.NewOrganizationService(organizationRepo, userRepo, bidRepo)
+	adminRepo := repository.NewAdminRepository(database)
+	adminService := service.NewAdminService(adminRepo, userRepo, tenderRepo, bidRepo, exchangeRateService)
 	userService := service.NewUserService(userRepo)
+	attachmentRepo := repository.NewAttachmentRepository(database)
+	attachmentService := service.NewAttachmentService(attachmentRepo, tenderRepo, bidRepo, evaluationService, fileStorage, cfg.Storage.MaxFileSize, cfg.Storage.AllowedTypes)
+	templateRepo := repository.NewTemplateRepository(database)
+	templateService := service.NewTemplateService(templateRepo, tenderRepo, attachmentRepo, userRepo, fileStorage)
 	controller.SetAuthService(authService)
+	controller.SetTwoFactorService(twoFactorService)
 	controller.SetTenderService(tenderService, redis)
 	controller.SetBidService(bidService)
 	controller.SetUserService(userService)
+	controller.SetAttachmentService(attachmentService)
+	controller.SetTemplateService(templateService)
+	controller.SetExchangeRateService(exchangeRateService)
+	controller.SetQualificationService(qualificationService)
+	controller.SetEvaluationService(evaluationService)
+	controller.SetProtestService(protestService)
+	controller.SetContractService(contractService)
+	controller.SetMilestoneService(milestoneService)
+	controller.SetInvoiceService(invoiceService)
+	controller.SetReviewService(reviewService)
+	controller.SetOrganizationService(organizationService)
+	controller.SetAdminService(adminService)
+	utils.SetUserRepository(userRepo)
+	utils.SetRevocationStore(redis)
+	go tenderService.RunPublishScheduler(context.Background(), time.Minute)
+	go protestService.RunFinalizeScheduler(context.Background(), time.Minute)
+	go milestoneService.RunOverdueScheduler(context.Background(), time.Hour)
 	routes.SetupRoutes(r)
 	r.Run(":8888")
 }
@@ -7,6 +7,9 @@ import (
 
 type ServerConfig struct {
 	Port string `yaml:"port"`
+	// TrustedProxies may set X-Forwarded-For. Client IPs are taken from
+	// the connection when it is empty.
+	TrustedProxies []string `yaml:"trusted_proxies"`
 }
 
 type DatabaseConfig struct {
@@ -26,9 +29,89 @@ type Postgres struct {
 	Username string `yaml:"username"`
 	DBName   string `yaml:"db_name"`
 }
+type StorageConfig struct {
+	Local        LocalStorage `yaml:"local"`
+	MaxFileSize  int64        `yaml:"max_file_size"`
+	AllowedTypes []string     `yaml:"allowed_types"`
+}
+
+type LocalStorage struct {
+	RootDir string `yaml:"root_dir"`
+}
+
+// ProcurementConfig holds the rules of the tendering process. A zero
+// standstill makes awards final immediately.
+type ProcurementConfig struct {
+	StandstillDays int `yaml:"standstill_days"`
+}
+
+// MailConfig sets who outgoing email is from and how it is delivered.
+// AppURL is the frontend address that links in emails point to.
+type MailConfig struct {
+	From   string   `yaml:"from"`
+	AppURL string   `yaml:"app_url"`
+	File   FileMail `yaml:"file"`
+}
+
+type FileMail struct {
+	Dir string `yaml:"dir"`
+}
+
+// AuthConfig sets how long tokens stay valid and how they are signed.
+// Access tokens are meant to be short-lived; refresh tokens are rotated on
+// every use. New tokens are signed with the key named by SigningKey; every
+// listed key is accepted when verifying, so a retired key can stay listed
+// until the tokens it signed have expired.
+type AuthConfig struct {
+	AccessTokenMinutes int    `yaml:"access_token_minutes"`
+	RefreshTokenDays   int    `yaml:"refresh_token_days"`
+	Issuer             string `yaml:"issuer"`
+	Audience           string `yaml:"audience"`
+	SigningKey         string `yaml:"signing_key"`
+	// TOTPIssuer is the name authenticator apps show for the account.
+	TOTPIssuer string              `yaml:"totp_issuer"`
+	Keys       []JWTKey            `yaml:"keys"`
+	Throttle   LoginThrottleConfig `yaml:"throttle"`
+	Password   PasswordConfig      `yaml:"password"`
+}
+
+// PasswordConfig is the password policy. The minimum length is never below 8.
+type PasswordConfig struct {
+	MinLength     int  `yaml:"min_length"`
+	RequireUpper  bool `yaml:"require_upper"`
+	RequireLower  bool `yaml:"require_lower"`
+	RequireDigit  bool `yaml:"require_digit"`
+	RequireSymbol bool `yaml:"require_symbol"`
+}
+
+// LoginThrottleConfig limits failed logins. Failures are counted per account
+// and per client IP over a sliding window. Every failure on an account delays
+// its next attempt a little longer; AccountLimit failures lock the account
+// and IPLimit failures lock the IP out for LockoutMinutes.
+type LoginThrottleConfig struct {
+	WindowMinutes  int `yaml:"window_minutes"`
+	AccountLimit   int `yaml:"account_limit"`
+	IPLimit        int `yaml:"ip_limit"`
+	LockoutMinutes int `yaml:"lockout_minutes"`
+}
+
+// JWTKey is a token signing key. HS256 keys use Secret. RS256 and EdDSA keys
+// read PEM files; a key with only a public key file can verify but not sign.
+type JWTKey struct {
+	ID             string `yaml:"id"`
+	Algorithm      string `yaml:"algorithm"`
+	Secret         string `yaml:"secret"`
+	PrivateKeyFile string `yaml:"private_key_file"`
+	PublicKeyFile  string `yaml:"public_key_file"`
+}
+
 type Config struct {
-	Server   ServerConfig   `yaml:"server"`
-	Database DatabaseConfig `yaml:"database"`
+	Server      ServerConfig      `yaml:"server"`
+	Database    DatabaseConfig    `yaml:"database"`
+	Storage     StorageConfig     `yaml:"storage"`
+	Procurement ProcurementConfig `yaml:"procurement"`
+	Auth        AuthConfig        `yaml:"auth"`
+	Mail        MailConfig        `yaml:"mail"`
 }
 
 func LoadConfig() (*Config, error) {
@@ -0,0 +1,275 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+	"tender-managment/internal/model"
+	"tender-managment/internal/service"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 200
+)
+
+var (
+	adminService *service.AdminService
+)
+
+func SetAdminService(adminSer *service.AdminService) {
+	adminService = adminSer
+}
+
+// AdminListUsersHandler godoc
+// @Summary Search users
+// @Description Lists users, newest first. q matches the username, email or full name
+// @Tags Admin
+// @Produce json
+// @Param q query string false "Search text"
+// @Param role query string false "client, contractor or admin"
+// @Param suspended query bool false "Only suspended or only active users"
+// @Param limit query int false "Page size, at most 200"
+// @Param offset query int false "Number of users to skip"
+// @Success 200 {array} model.UserProfile
+// @Failure 400 {object} map[string]string "Invalid query parameters"
+// @Failure 403 {object} map[string]string "Not an administrator"
+// @Security Bearer
+// @Router /api/admin/users [get]
+func AdminListUsersHandler(c *gin.Context) {
+	limit, offset, err := pagination(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	filter := model.UserSearch{
+		Query:  c.Query("q"),
+		Role:   c.Query("role"),
+		Limit:  limit,
+		Offset: offset,
+	}
+	if value := c.Query("suspended"); value != "" {
+		suspended, err := strconv.ParseBool(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid suspended filter"})
+			return
+		}
+		filter.Suspended = &suspended
+	}
+
+	users, err := adminService.SearchUsers(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to fetch users"})
+		return
+	}
+
+	c.JSON(http.StatusOK, users)
+}
+
+// AdminSuspendUserHandler godoc
+// @Summary Suspend a user
+// @Description Blocks the account from signing in and from using the API. Administrators cannot be suspended
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Param reason body model.ModerationReason true "Reason for the suspension"
+// @Success 200 {object} map[string]string "User suspended"
+// @Failure 400 {object} map[string]string "Missing reason or user cannot be suspended"
+// @Failure 404 {object} map[string]string "User not found"
+// @Security Bearer
+// @Router /api/admin/users/{id}/suspend [post]
+func AdminSuspendUserHandler(c *gin.Context) {
+	userID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid user ID"})
+		return
+	}
+
+	var payload model.ModerationReason
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid input", "error": err.Error()})
+		return
+	}
+
+	if err := adminService.SuspendUser(c.GetInt("user_id"), userID, payload.Reason); err != nil {
+		adminError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "User suspended"})
+}
+
+// AdminReactivateUserHandler godoc
+// @Summary Reactivate a user
+// @Description Lifts a suspension
+// @Tags Admin
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} map[string]string "User reactivated"
+// @Failure 400 {object} map[string]string "User is not suspended"
+// @Failure 404 {object} map[string]string "User not found"
+// @Security Bearer
+// @Router /api/admin/users/{id}/reactivate [post]
+func AdminReactivateUserHandler(c *gin.Context) {
+	userID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid user ID"})
+		return
+	}
+
+	if err := adminService.ReactivateUser(c.GetInt("user_id"), userID); err != nil {
+		adminError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "User reactivated"})
+}
+
+// AdminCloseTenderHandler godoc
+// @Summary Force-close a tender
+// @Description Closes a fraudulent draft or open tender and notifies its owner and bidders
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Tender ID"
+// @Param reason body model.ModerationReason true "Reason for closing the tender"
+// @Success 200 {object} map[string]string "Tender closed"
+// @Failure 400 {object} map[string]string "Missing reason or tender already closed"
+// @Failure 404 {object} map[string]string "Tender not found"
+// @Security Bearer
+// @Router /api/admin/tenders/{id}/close [post]
+func AdminCloseTenderHandler(c *gin.Context) {
+	tenderID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid tender ID"})
+		return
+	}
+
+	var payload model.ModerationReason
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid input", "error": err.Error()})
+		return
+	}
+
+	tender, err := adminService.CloseTender(c.GetInt("user_id"), tenderID, payload.Reason)
+	if err != nil {
+		adminError(c, err)
+		return
+	}
+
+	invalidateTenderLists(c, tender.ClientID)
+	c.JSON(http.StatusOK, gin.H{"message": "Tender closed"})
+}
+
+// AdminRemoveBidHandler godoc
+// @Summary Remove an abusive bid
+// @Description Deletes a bid that has not been awarded and notifies the contractor
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Bid ID"
+// @Param reason body model.ModerationReason true "Reason for removing the bid"
+// @Success 200 {object} map[string]string "Bid removed"
+// @Failure 400 {object} map[string]string "Missing reason or bid awarded"
+// @Failure 404 {object} map[string]string "Bid not found"
+// @Security Bearer
+// @Router /api/admin/bids/{id}/remove [post]
+func AdminRemoveBidHandler(c *gin.Context) {
+	bidID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid bid ID"})
+		return
+	}
+
+	var payload model.ModerationReason
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid input", "error": err.Error()})
+		return
+	}
+
+	bid, err := adminService.RemoveBid(c.GetInt("user_id"), bidID, payload.Reason)
+	if err != nil {
+		adminError(c, err)
+		return
+	}
+
+	_ = redisClient.Del(c.Request.Context(), fmt.Sprintf(bidDetailKey, bidID))
+	_ = redisClient.Del(c.Request.Context(), fmt.Sprintf(bidsByTenderKey, bid.TenderID))
+	invalidateContractorBids(c, bid.ContractorID)
+	c.JSON(http.StatusOK, gin.H{"message": "Bid removed"})
+}
+
+// AdminStatsHandler godoc
+// @Summary Platform statistics
+// @Description Counts users by role, tenders, bids, contracts and invoices by status, suspended users and organizations
+// @Tags Admin
+// @Produce json
+// @Success 200 {object} model.PlatformStats
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security Bearer
+// @Router /api/admin/stats [get]
+func AdminStatsHandler(c *gin.Context) {
+	stats, err := adminService.GetStats()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to compute statistics"})
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// AdminAuditTrailHandler godoc
+// @Summary Admin audit trail
+// @Description Lists every administrator action, newest first
+// @Tags Admin
+// @Produce json
+// @Param limit query int false "Page size, at most 200"
+// @Param offset query int false "Number of entries to skip"
+// @Success 200 {array} model.AdminAction
+// @Failure 400 {object} map[string]string "Invalid query parameters"
+// @Security Bearer
+// @Router /api/admin/audit [get]
+func AdminAuditTrailHandler(c *gin.Context) {
+	limit, offset, err := pagination(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	actions, err := adminService.ListActions(limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to fetch audit trail"})
+		return
+	}
+
+	c.JSON(http.StatusOK, actions)
+}
+
+// pagination reads the limit and offset query parameters.
+func pagination(c *gin.Context) (int, int, error) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultPageSize)))
+	if err != nil || limit <= 0 || limit > maxPageSize {
+		return 0, 0, errors.New("invalid limit")
+	}
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		return 0, 0, errors.New("invalid offset")
+	}
+	return limit, offset, nil
+}
+
+func adminError(c *gin.Context, err error) {
+	switch err.Error() {
+	case "user not found", "tender not found", "bid not found":
+		c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+	case "a reason is required", "user cannot be suspended", "user is not suspended",
+		"only draft or open tenders can be closed", "awarded bids cannot be removed":
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Admin action failed", "error": err.Error()})
+	}
+}
@@ -0,0 +1,223 @@
+package controller
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"io"
+	"net/http"
+	"strconv"
+	"tender-managment/internal/service"
+)
+
+var (
+	attachmentService *service.AttachmentService
+)
+
+func SetAttachmentService(attachmentSer *service.AttachmentService) {
+	attachmentService = attachmentSer
+}
+
+// UploadTenderAttachmentHandler godoc
+// @Summary Upload a tender attachment
+// @Description Uploads a document to a tender owned by the client
+// @Tags Attachment
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path int true "Tender ID"
+// @Param file formData file true "Document to attach"
+// @Success 201 {object} model.Attachment
+// @Failure 400 {object} map[string]string "Invalid tender ID or file"
+// @Failure 404 {object} map[string]string "Tender not found"
+// @Failure 413 {object} map[string]string "File too large"
+// @Failure 415 {object} map[string]string "File type not allowed"
+// @Security Bearer
+// @Router /api/client/tenders/{id}/attachments [post]
+func UploadTenderAttachmentHandler(c *gin.Context) {
+	tenderID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid tender ID"})
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "File is required"})
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Failed to read file"})
+		return
+	}
+	defer file.Close()
+
+	attachment, status, err := attachmentService.UploadTenderAttachment(c.Request.Context(), c.GetInt("user_id"), tenderID, fileHeader.Filename, file)
+	if err != nil {
+		c.JSON(status, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(status, attachment)
+}
+
+// ListTenderAttachmentsHandler godoc
+// @Summary List tender attachments
+// @Description Lists the documents attached to a tender
+// @Tags Attachment
+// @Produce json
+// @Param id path int true "Tender ID"
+// @Success 200 {array} model.Attachment
+// @Failure 400 {object} map[string]string "Invalid tender ID"
+// @Failure 404 {object} map[string]string "Tender not found"
+// @Security Bearer
+// @Router /api/client/tenders/{id}/attachments [get]
+func ListTenderAttachmentsHandler(c *gin.Context) {
+	tenderID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid tender ID"})
+		return
+	}
+
+	attachments, err := attachmentService.ListTenderAttachments(c.GetInt("user_id"), c.GetString("role"), tenderID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, attachments)
+}
+
+// UploadBidAttachmentHandler godoc
+// @Summary Upload a bid attachment
+// @Description Uploads a supporting document to a pending bid owned by the contractor
+// @Tags Attachment
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path int true "Bid ID"
+// @Param file formData file true "Document to attach"
+// @Success 201 {object} model.Attachment
+// @Failure 400 {object} map[string]string "Invalid bid ID or file"
+// @Failure 404 {object} map[string]string "Bid not found"
+// @Failure 413 {object} map[string]string "File too large"
+// @Failure 415 {object} map[string]string "File type not allowed"
+// @Security Bearer
+// @Router /api/contractor/bids/{id}/attachments [post]
+func UploadBidAttachmentHandler(c *gin.Context) {
+	bidID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid bid ID"})
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "File is required"})
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Failed to read file"})
+		return
+	}
+	defer file.Close()
+
+	attachment, status, err := attachmentService.UploadBidAttachment(c.Request.Context(), c.GetInt("user_id"), bidID, fileHeader.Filename, file)
+	if err != nil {
+		c.JSON(status, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(status, attachment)
+}
+
+// ListBidAttachmentsHandler godoc
+// @Summary List bid attachments
+// @Description Lists the documents attached to a bid, visible to the bidder and the tender owner
+// @Tags Attachment
+// @Produce json
+// @Param id path int true "Bid ID"
+// @Success 200 {array} model.Attachment
+// @Failure 400 {object} map[string]string "Invalid bid ID"
+// @Failure 403 {object} map[string]string "Conflict-of-interest declaration required"
+// @Failure 404 {object} map[string]string "Bid not found"
+// @Security Bearer
+// @Router /api/attachments/bids/{id} [get]
+func ListBidAttachmentsHandler(c *gin.Context) {
+	bidID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid bid ID"})
+		return
+	}
+
+	attachments, err := attachmentService.ListBidAttachments(c.GetInt("user_id"), c.GetString("role"), bidID)
+	if err != nil {
+		if isDeclarationError(err) {
+			c.JSON(http.StatusForbidden, gin.H{"message": err.Error()})
+			return
+		}
+		c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, attachments)
+}
+
+// DownloadAttachmentHandler godoc
+// @Summary Download an attachment
+// @Description Streams an attachment to the tender owner or a permitted bidder
+// @Tags Attachment
+// @Produce octet-stream
+// @Param id path int true "Attachment ID"
+// @Success 200 {file} file
+// @Failure 400 {object} map[string]string "Invalid attachment ID"
+// @Failure 403 {object} map[string]string "Conflict-of-interest declaration required"
+// @Failure 404 {object} map[string]string "Attachment not found"
+// @Security Bearer
+// @Router /api/attachments/{id} [get]
+func DownloadAttachmentHandler(c *gin.Context) {
+	attachmentID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid attachment ID"})
+		return
+	}
+
+	attachment, file, err := attachmentService.OpenAttachment(c.Request.Context(), c.GetInt("user_id"), c.GetString("role"), attachmentID)
+	if err != nil {
+		if isDeclarationError(err) {
+			c.JSON(http.StatusForbidden, gin.H{"message": err.Error()})
+			return
+		}
+		c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+		return
+	}
+	defer file.Close()
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", attachment.FileName))
+	c.Header("X-Content-SHA256", attachment.SHA256)
+	c.DataFromReader(http.StatusOK, attachment.Size, attachment.MimeType, io.Reader(file), nil)
+}
+
+// DeleteAttachmentHandler godoc
+// @Summary Delete an attachment
+// @Description Deletes an attachment uploaded by the current user
+// @Tags Attachment
+// @Param id path int true "Attachment ID"
+// @Success 200 {object} map[string]string "Attachment deleted successfully"
+// @Failure 400 {object} map[string]string "Invalid attachment ID"
+// @Failure 404 {object} map[string]string "Attachment not found"
+// @Security Bearer
+// @Router /api/attachments/{id} [delete]
+func DeleteAttachmentHandler(c *gin.Context) {
+	attachmentID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid attachment ID"})
+		return
+	}
+
+	if err := attachmentService.DeleteAttachment(c.Request.Context(), c.GetInt("user_id"), attachmentID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Attachment deleted successfully"})
+}
@@ -3,6 +3,7 @@ package controller
 import (
 	"github.com/gin-gonic/gin"
 	"net/http"
+	"strings"
 	"tender-managment/internal/model"
 	"tender-managment/internal/service"
 )
@@ -17,59 +18,355 @@ func SetAuthService(authSer *service.AuthService) {
 
 // Register godoc
 // @Summary Register a new user
-// @Description Registers a new user and returns an authentication token
+// @Description Registers a new user, mails a link to verify the email address and returns an access token and a refresh token
 // @Tags Auth
 // @Accept json
 // @Produce json
 // @Param payload body model.RegisterModel true "User Registration Payload"
 // @Success 201 {object} map[string]interface{}
-// @Failure 400 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{} "Invalid input or password rejected by the policy"
+// @Failure 500 {object} map[string]interface{}
 // @Router /register [post]
 func Register(c *gin.Context) {
 	var payload model.RegisterModel
-	if err := c.BindJSON(&payload); err != nil {
+	if err := c.ShouldBindJSON(&payload); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"message": "Invalid input",
 		})
 		return
 	}
 
-	token, err := authService.RegisterUser(payload.Username, payload.Email, payload.Password, payload.Role)
+	tokens, err := authService.RegisterUser(c.Request.Context(), payload.Username, payload.Email, payload.Password, payload.Role)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"message": err.Error(),
-		})
+		switch {
+		case err.Error() == "invalid role", err.Error() == "username or email cannot be empty",
+			err.Error() == "invalid email format", err.Error() == "Email already exists",
+			strings.HasPrefix(err.Error(), "password "):
+			c.JSON(http.StatusBadRequest, gin.H{
+				"message": err.Error(),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"message": "Failed to register user",
+			})
+		}
 		return
 	}
 
 	c.JSON(http.StatusCreated, gin.H{
-		"message": "User registered successfully",
-		"token":   token,
+		"message":       "User registered successfully",
+		"token":         tokens.Token,
+		"refresh_token": tokens.RefreshToken,
+		"expires_in":    tokens.ExpiresIn,
 	})
 }
 
 // Login godoc
 // @Summary Login an existing user
-// @Description Authenticates a user and returns an authentication token
+// @Description Authenticates a user and returns an access token and a refresh token. Accounts with two-factor authentication get two_factor_required and a challenge_token to complete at /login/2fa instead
 // @Tags Auth
 // @Accept json
 // @Produce json
 // @Param payload body model.LoginModel true "User Login Payload"
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} map[string]interface{}
-// @Failure 401 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{} "Invalid username or password"
+// @Failure 403 {object} map[string]interface{} "Account suspended"
+// @Failure 429 {object} map[string]interface{} "Too many failed attempts"
 // @Router /login [post]
 func Login(c *gin.Context) {
 	var payload model.LoginModel
 
-	if err := c.BindJSON(&payload); err != nil {
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": "Invalid input",
+		})
+		return
+	}
+
+	tokens, challenge, status, err := authService.AuthenticateUser(c.Request.Context(), payload.Username, payload.Password, c.ClientIP())
+	if err != nil {
+		c.JSON(status, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	if challenge != nil {
+		c.JSON(http.StatusOK, gin.H{
+			"message":             "Two-factor authentication required",
+			"two_factor_required": true,
+			"challenge_token":     challenge.ChallengeToken,
+			"expires_in":          challenge.ExpiresIn,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":       "Login successful",
+		"token":         tokens.Token,
+		"refresh_token": tokens.RefreshToken,
+		"expires_in":    tokens.ExpiresIn,
+	})
+}
+
+// LoginTwoFactor godoc
+// @Summary Complete a two-step login
+// @Description Exchanges the challenge token from /login and a code from the authenticator app, or a recovery code, for an access token and a refresh token
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param payload body model.TwoFactorLoginModel true "Challenge token and code"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{} "Invalid code or expired challenge"
+// @Failure 403 {object} map[string]interface{} "Account suspended"
+// @Failure 429 {object} map[string]interface{} "Too many failed attempts"
+// @Router /login/2fa [post]
+func LoginTwoFactor(c *gin.Context) {
+	var payload model.TwoFactorLoginModel
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": "Invalid input",
+		})
+		return
+	}
+
+	tokens, status, err := authService.CompleteLogin(c.Request.Context(), payload.ChallengeToken, payload.Code, c.ClientIP())
+	if err != nil {
+		c.JSON(status, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":       "Login successful",
+		"token":         tokens.Token,
+		"refresh_token": tokens.RefreshToken,
+		"expires_in":    tokens.ExpiresIn,
+	})
+}
+
+// Refresh godoc
+// @Summary Refresh the access token
+// @Description Trades a refresh token for a new access token and refresh token. Each refresh token can be used once; reusing one ends every session of the user
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param payload body model.RefreshModel true "Refresh token"
+// @Success 200 {object} model.TokenPair
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{} "Invalid or expired refresh token"
+// @Failure 403 {object} map[string]interface{} "Account suspended"
+// @Router /refresh [post]
+func Refresh(c *gin.Context) {
+	var payload model.RefreshModel
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": "Invalid input",
+		})
+		return
+	}
+
+	tokens, status, err := authService.Refresh(c.Request.Context(), payload.RefreshToken)
+	if err != nil {
+		c.JSON(status, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, tokens)
+}
+
+// Logout godoc
+// @Summary Log out
+// @Description Revokes the access token and the refresh token of the current session
+// @Tags Auth
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Security Bearer
+// @Router /api/logout [post]
+func Logout(c *gin.Context) {
+	err := authService.Logout(c.Request.Context(), c.GetInt("user_id"), c.GetString("jti"), c.GetTime("token_expires_at"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"message": "Failed to log out",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Logged out",
+	})
+}
+
+// LogoutAll godoc
+// @Summary Log out on all devices
+// @Description Revokes every access token and refresh token of the user
+// @Tags Auth
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Security Bearer
+// @Router /api/logout-all [post]
+func LogoutAll(c *gin.Context) {
+	err := authService.LogoutAll(c.Request.Context(), c.GetInt("user_id"), c.GetString("jti"), c.GetTime("token_expires_at"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"message": "Failed to log out",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Logged out on all devices",
+	})
+}
+
+// VerifyEmail godoc
+// @Summary Verify an email address
+// @Description Confirms the email address with the token from the verification email. Each token works once
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param payload body model.VerifyEmailModel true "Verification token"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{} "Invalid or expired token"
+// @Router /verify-email [post]
+func VerifyEmail(c *gin.Context) {
+	var payload model.VerifyEmailModel
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": "Invalid input",
+		})
+		return
+	}
+
+	if err := authService.VerifyEmail(payload.Token); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Email verified",
+	})
+}
+
+// ResendVerification godoc
+// @Summary Resend the verification email
+// @Description Mails a new verification link. Links sent earlier stop working
+// @Tags Auth
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{} "Email already verified"
+// @Security Bearer
+// @Router /api/users/me/verify-email [post]
+func ResendVerification(c *gin.Context) {
+	err := authService.ResendVerification(c.Request.Context(), c.GetInt("user_id"))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "email already verified" {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Verification email sent",
+	})
+}
+
+// RequestPasswordReset godoc
+// @Summary Request a password reset
+// @Description Mails a password reset link valid for one hour. The response is the same whether or not the address is registered
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param payload body model.PasswordResetRequestModel true "Account email"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /password-reset [post]
+func RequestPasswordReset(c *gin.Context) {
+	var payload model.PasswordResetRequestModel
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": "Invalid input",
+		})
+		return
+	}
+
+	authService.RequestPasswordReset(c.Request.Context(), payload.Email)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "If the address belongs to an account, a reset link has been sent to it",
+	})
+}
+
+// ResetPassword godoc
+// @Summary Reset the password
+// @Description Sets a new password with the token from the reset email and logs the user out on all devices
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param payload body model.PasswordResetModel true "Reset token and new password"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{} "Invalid or expired token, or password rejected by the policy"
+// @Router /password-reset/confirm [post]
+func ResetPassword(c *gin.Context) {
+	var payload model.PasswordResetModel
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": "Invalid input",
+		})
+		return
+	}
+
+	if err := authService.ResetPassword(c.Request.Context(), payload.Token, payload.Password); err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "invalid or expired token" || strings.HasPrefix(err.Error(), "password ") {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Password has been reset",
+	})
+}
+
+// ChangePassword godoc
+// @Summary Change the password
+// @Description Replaces the password after checking the current one. The new password must meet the password policy. Every other session is logged out
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param payload body model.ChangePasswordModel true "Current and new password"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{} "Password rejected by the policy"
+// @Failure 401 {object} map[string]interface{} "Current password is incorrect"
+// @Security Bearer
+// @Router /api/users/me/password [put]
+func ChangePassword(c *gin.Context) {
+	var payload model.ChangePasswordModel
+	if err := c.ShouldBindJSON(&payload); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"message": "Invalid input",
 		})
 		return
 	}
 
-	token, status, err := authService.AuthenticateUser(payload.Username, payload.Password)
+	status, err := authService.ChangePassword(c.Request.Context(), c.GetInt("user_id"), c.GetString("jti"), payload.CurrentPassword, payload.NewPassword)
 	if err != nil {
 		c.JSON(status, gin.H{
 			"message": err.Error(),
@@ -78,7 +375,6 @@ func Login(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"message": "Login successful",
-		"token":   token,
+		"message": "Password changed, other sessions have been logged out",
 	})
 }
@@ -2,10 +2,12 @@ package controller
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/gin-gonic/gin"
 	"net/http"
 	"strconv"
+	"strings"
 	"tender-managment/internal/model"
 	"tender-managment/internal/service"
 	"time"
@@ -30,7 +32,7 @@ const (
 
 // CreateBidHandler godoc
 // @Summary Create a bid for a tender
-// @Description Create a bid for a given tender with the specified price, delivery time, and comments
+// @Description Create a bid for a given tender with the specified price, delivery time, and comments. Tenders split into lots take a price per lot and tenders with a bill of quantities a unit price per line instead
 // @Tags bids
 // @Accept json
 // @Produce json
@@ -38,6 +40,7 @@ const (
 // @Param bid body model.CreateBid true "Bid Information (e.g., { \"price\": 1000, \"deliveryTime\": 30, \"comments\": \"Delivery within a month\" })"
 // @Success 201 {object} model.Bid "Details of the created bid"
 // @Failure 400 {object} map[string]string "Invalid tender ID or request body"
+// @Failure 403 {object} map[string]interface{} "Not invited, or missing qualifications listed under missing"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Security Bearer
 // @Router /api/contractor/tenders/{id}/bid [post]
@@ -53,7 +56,7 @@ func CreateBidHandler(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	if bid.Price <= 0 || bid.DeliveryTime <= 0 || bid.Comments == "" {
+	if (bid.Price.Amount <= 0 && len(bid.Lots) == 0 && len(bid.LineItems) == 0) || bid.DeliveryTime <= 0 || bid.Comments == "" {
 		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid bid data"})
 		return
 	}
@@ -61,31 +64,81 @@ func CreateBidHandler(c *gin.Context) {
 
 	createdBid, status, err := bidService.CreateBid(contractorId, tenderId, bid)
 	if err != nil {
+		var ineligible *service.IneligibleError
+		if errors.As(err, &ineligible) {
+			c.JSON(status, gin.H{"message": err.Error(), "missing": ineligible.Missing})
+			return
+		}
 		c.JSON(status, gin.H{"message": err.Error()})
 		return
 	}
 
 	tenderBidsKey := fmt.Sprintf(bidsByTenderKey, tenderId)
-	contractorBidsKey := fmt.Sprintf(bidsByContractorKey, contractorId)
 	_ = redisClient.Del(c.Request.Context(), tenderBidsKey)
-	_ = redisClient.Del(c.Request.Context(), contractorBidsKey)
+	invalidateContractorBids(c, contractorId)
 
 	c.JSON(status, createdBid)
 }
 
+// SubmitEOIHandler godoc
+// @Summary Express interest in a two-stage tender
+// @Description Submits a non-priced expression of interest for the first stage of a two-stage tender
+// @Tags bids
+// @Accept json
+// @Produce json
+// @Param id path int true "Tender ID"
+// @Param eoi body model.CreateEOI true "Expression of interest"
+// @Success 201 {object} model.Bid "The stored expression of interest"
+// @Failure 400 {object} map[string]string "Invalid input or tender is not in the EOI stage"
+// @Failure 403 {object} map[string]interface{} "Not invited, or missing qualifications listed under missing"
+// @Security Bearer
+// @Router /api/contractor/tenders/{id}/eoi [post]
+func SubmitEOIHandler(c *gin.Context) {
+	tenderId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tender ID"})
+		return
+	}
+
+	var eoi model.CreateEOI
+	if err := c.ShouldBindJSON(&eoi); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	contractorId := c.GetInt("user_id")
+
+	created, status, err := bidService.SubmitEOI(contractorId, tenderId, eoi)
+	if err != nil {
+		var ineligible *service.IneligibleError
+		if errors.As(err, &ineligible) {
+			c.JSON(status, gin.H{"message": err.Error(), "missing": ineligible.Missing})
+			return
+		}
+		c.JSON(status, gin.H{"message": err.Error()})
+		return
+	}
+
+	_ = redisClient.Del(c.Request.Context(), fmt.Sprintf(bidsByTenderKey, tenderId))
+	invalidateContractorBids(c, contractorId)
+
+	c.JSON(status, created)
+}
+
 // GetBidsByTenderID godoc
 // @Summary Get all bids for a tender
-// @Description Retrieve all bids for a given tender, with optional filtering and sorting
+// @Description Retrieve all bids for a given tender, with optional filtering and sorting. The owner and evaluators must first sign a conflict-of-interest declaration
 // @Tags bids
 // @Accept json
 // @Produce json
 // @Param id path int true "Tender ID"
-// @Param price query float64 false "Filter bids by price"
+// @Param type query string false "'bid' for priced bids (default) or 'eoi' for expressions of interest"
+// @Param price query string false "Filter bids by maximum price, e.g. 1500.00"
 // @Param delivery_time query string false "Filter bids by delivery time"
 // @Param sort_by query string false "Sort by 'price' or 'delivery_time'"
+// @Param with_reputation query bool false "Include each contractor's aggregated reputation"
 // @Success 200 {array} model.Bid "List of bids"
 // @Failure 400 {object} map[string]string "Invalid tender ID or query parameters"
-// @Failure 403 {object} map[string]string "Access denied"
+// @Failure 403 {object} map[string]string "Declaration missing or conflict declared"
 // @Failure 404 {object} map[string]string "No bids found"
 // @Security Bearer
 // @Router /api/client/tenders/{id}/bids [get]
@@ -98,32 +151,57 @@ func GetBidsByTenderID(c *gin.Context) {
 
 	userId := c.GetInt("user_id")
 	cacheKey := fmt.Sprintf(bidsByTenderKey, tenderId)
-
-	cachedData, err := redisClient.Get(c.Request.Context(), cacheKey)
-	if err == nil && cachedData != "" {
-		var bids []model.Bid
-		if err := json.Unmarshal([]byte(cachedData), &bids); err == nil {
-			c.JSON(http.StatusOK, bids)
+	withReputation := c.Query("with_reputation") == "true"
+	// Only the priced bids are cached; expressions of interest are read
+	// while shortlisting and always come from the database.
+	bidType := c.DefaultQuery("type", model.BidTypeBid)
+
+	if err := bidService.CheckBidAccess(tenderId, userId); err != nil {
+		if isDeclarationError(err) {
+			c.JSON(http.StatusForbidden, gin.H{"message": err.Error()})
 			return
 		}
+		c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+		return
+	}
+
+	if bidType == model.BidTypeBid {
+		cachedData, err := redisClient.Get(c.Request.Context(), cacheKey)
+		if err == nil && cachedData != "" {
+			var bids []model.Bid
+			if err := json.Unmarshal([]byte(cachedData), &bids); err == nil {
+				if withReputation {
+					reviewService.AttachReputation(bids)
+				}
+				c.JSON(http.StatusOK, bids)
+				return
+			}
+		}
 	}
 
-	priceFilter, _ := strconv.ParseFloat(c.DefaultQuery("price", "0"), 64)
+	priceFilter, _ := model.ParseAmount(c.DefaultQuery("price", "0"))
 	deliveryTimeFilter := c.DefaultQuery("delivery_time", "")
 	sortBy := c.DefaultQuery("sort_by", "")
 
-	bids, err := bidService.GetBidsByTenderID(tenderId, userId, priceFilter, deliveryTimeFilter, sortBy)
+	bids, err := bidService.GetBidsByTenderID(tenderId, userId, bidType, priceFilter, deliveryTimeFilter, sortBy)
 	if err != nil {
-		if err.Error() == "Tender not found or access denied" {
-			c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+		if err.Error() == "invalid bid type" {
+			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
 			return
 		}
 		c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
 		return
 	}
 
-	if bidsJSON, err := json.Marshal(bids); err == nil {
-		_ = redisClient.Set(c.Request.Context(), cacheKey, bidsJSON, bidListCacheDuration)
+	if bidType == model.BidTypeBid {
+		if bidsJSON, err := json.Marshal(bids); err == nil {
+			_ = redisClient.Set(c.Request.Context(), cacheKey, bidsJSON, bidListCacheDuration)
+		}
+	}
+
+	// Reputation changes independently of the bids, so it is never cached.
+	if withReputation {
+		reviewService.AttachReputation(bids)
 	}
 
 	c.JSON(http.StatusOK, bids)
@@ -176,14 +254,14 @@ func GetBidByIDHandler(c *gin.Context) {
 
 // UpdateBidStatusHandler godoc
 // @Summary Update the status of a bid
-// @Description Update the status of an existing bid (e.g., accepted, rejected)
+// @Description Withdraws a pending bid by setting its status to rejected
 // @Tags bids
 // @Accept json
 // @Produce json
 // @Param id path int true "Bid ID"
 // @Param updateData body model.UpdateBid true "Update bid request body"
 // @Success 200 {object} map[string]interface{} "Bid status updated successfully"
-// @Failure 400 {object} map[string]string "Invalid bid ID or request data"
+// @Failure 400 {object} map[string]string "Invalid bid ID, status or request data, or bid is not pending"
 // @Failure 404 {object} map[string]string "Bid not found"
 // @Failure 500 {object} map[string]string "Failed to update bid status"
 // @Security Bearer
@@ -210,15 +288,19 @@ func UpdateBidStatusHandler(c *gin.Context) {
 
 	err = bidService.UpdateBidStatus(contractorId, bidId, updateData.Status)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update bid status"})
+		switch err.Error() {
+		case "invalid status", "only pending bids can be withdrawn":
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update bid status"})
+		}
 		return
 	}
 	bidDetailKey := fmt.Sprintf(bidDetailKey, bidId)
 	tenderBidsKey := fmt.Sprintf(bidsByTenderKey, bid.TenderID)
-	contractorBidsKey := fmt.Sprintf(bidsByContractorKey, contractorId)
 	_ = redisClient.Del(c.Request.Context(), bidDetailKey)
 	_ = redisClient.Del(c.Request.Context(), tenderBidsKey)
-	_ = redisClient.Del(c.Request.Context(), contractorBidsKey)
+	invalidateContractorBids(c, contractorId)
 
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Bid status updated successfully",
@@ -293,10 +375,9 @@ func DeleteBidHandler(c *gin.Context) {
 	if bid != nil {
 		bidDetailKey := fmt.Sprintf(bidDetailKey, bidId)
 		tenderBidsKey := fmt.Sprintf(bidsByTenderKey, bid.TenderID)
-		contractorBidsKey := fmt.Sprintf(bidsByContractorKey, contractorId)
 		_ = redisClient.Del(c.Request.Context(), bidDetailKey)
 		_ = redisClient.Del(c.Request.Context(), tenderBidsKey)
-		_ = redisClient.Del(c.Request.Context(), contractorBidsKey)
+		invalidateContractorBids(c, contractorId)
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "Bid deleted successfully"})
@@ -304,14 +385,14 @@ func DeleteBidHandler(c *gin.Context) {
 
 // AwardBidHandler godoc
 // @Summary Award a bid for a tender
-// @Description Award a specific bid for a tender, indicating it has been selected
+// @Description Award a specific bid for a tender. The award stays provisional until the standstill period ends without pending protests
 // @Tags bids
 // @Accept json
 // @Produce json
 // @Param id path int true "Tender ID"
 // @Param bidId path int true "Bid ID"
 // @Success 200 {object} map[string]string "Bid awarded successfully"
-// @Failure 400 {object} map[string]string "Invalid tender or bid ID"
+// @Failure 400 {object} map[string]string "Invalid IDs, or the tender is not ready to be awarded"
 // @Failure 404 {object} map[string]string "Bid or tender not found"
 // @Security Bearer
 // @Router /api/client/tenders/{id}/award/{bidId} [post]
@@ -332,7 +413,13 @@ func AwardBidHandler(c *gin.Context) {
 
 	err = bidService.AwardBid(clientId, tenderId, bidId)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+		switch err.Error() {
+		case "evaluation committee has not signed off", "tender has already been awarded",
+			"tender can only be awarded after the deadline once bidding is closed":
+			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		default:
+			c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+		}
 		return
 	}
 
@@ -344,15 +431,140 @@ func AwardBidHandler(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Bid awarded successfully"})
 }
 
+// AwardLotHandler godoc
+// @Summary Award a lot of a tender
+// @Description Award a single lot to a bid that covers it. The tender is provisionally awarded once every lot is decided
+// @Tags bids
+// @Produce json
+// @Param id path int true "Tender ID"
+// @Param lotId path int true "Lot ID"
+// @Param bidId path int true "Bid ID"
+// @Success 200 {object} map[string]string "Lot awarded successfully"
+// @Failure 400 {object} map[string]string "Invalid IDs, or the tender is not ready to be awarded"
+// @Failure 404 {object} map[string]string "Bid, lot or tender not found"
+// @Security Bearer
+// @Router /api/client/tenders/{id}/lots/{lotId}/award/{bidId} [post]
+func AwardLotHandler(c *gin.Context) {
+	tenderId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tender ID"})
+		return
+	}
+
+	lotId, err := strconv.Atoi(c.Param("lotId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid lot ID"})
+		return
+	}
+
+	bidId, err := strconv.Atoi(c.Param("bidId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid bid ID"})
+		return
+	}
+
+	clientId := c.GetInt("user_id")
+
+	err = bidService.AwardLot(clientId, tenderId, lotId, bidId)
+	if err != nil {
+		switch err.Error() {
+		case "evaluation committee has not signed off", "tender has already been awarded",
+			"tender can only be awarded after the deadline once bidding is closed":
+			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		default:
+			c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+		}
+		return
+	}
+
+	tenderBidsKey := fmt.Sprintf(bidsByTenderKey, tenderId)
+	bidDetailKey := fmt.Sprintf(bidDetailKey, bidId)
+	_ = redisClient.Del(c.Request.Context(), tenderBidsKey)
+	_ = redisClient.Del(c.Request.Context(), bidDetailKey)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Lot awarded successfully"})
+}
+
+// CancelLotHandler godoc
+// @Summary Cancel a lot of a tender
+// @Description Close a lot without awarding it. The tender is decided once every lot is awarded or cancelled
+// @Tags bids
+// @Produce json
+// @Param id path int true "Tender ID"
+// @Param lotId path int true "Lot ID"
+// @Success 200 {object} map[string]string "Lot cancelled successfully"
+// @Failure 400 {object} map[string]string "Invalid tender or lot ID"
+// @Failure 404 {object} map[string]string "Lot or tender not found"
+// @Security Bearer
+// @Router /api/client/tenders/{id}/lots/{lotId}/cancel [post]
+func CancelLotHandler(c *gin.Context) {
+	tenderId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tender ID"})
+		return
+	}
+
+	lotId, err := strconv.Atoi(c.Param("lotId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid lot ID"})
+		return
+	}
+
+	clientId := c.GetInt("user_id")
+
+	err = bidService.CancelLot(clientId, tenderId, lotId)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Lot cancelled successfully"})
+}
+
+// CompareBidsHandler godoc
+// @Summary Compare bids line by line
+// @Description Lays out every bid's bill of quantities prices side by side with the min, max and average per line
+// @Tags bids
+// @Produce json
+// @Param id path int true "Tender ID"
+// @Param currency query string false "Show amounts converted into this ISO currency"
+// @Success 200 {object} model.BoQComparison
+// @Failure 400 {object} map[string]string "Invalid tender ID"
+// @Failure 404 {object} map[string]string "Tender not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security Bearer
+// @Router /api/client/tenders/{id}/bids/comparison [get]
+func CompareBidsHandler(c *gin.Context) {
+	tenderId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tender ID"})
+		return
+	}
+
+	comparison, err := bidService.CompareBids(c.GetInt("user_id"), tenderId, c.Query("currency"))
+	if err != nil {
+		switch {
+		case isDeclarationError(err):
+			c.JSON(http.StatusForbidden, gin.H{"message": err.Error()})
+		case strings.HasPrefix(err.Error(), "failed to fetch exchange rate"):
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to compare bids"})
+		default:
+			c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, comparison)
+}
+
 // GetContractorBidHistory godoc
 // @Summary Retrieve Contractor's Bid History
-// @Description Retrieves a list of bids placed by a specific contractor
+// @Description Retrieves the bids placed by a specific contractor. Other users only see bids that won a finalized award
 // @Tags User
 // @Produce json
 // @Param id path int true "Contractor ID"
 // @Success 200 {array} model.Bid "List of bids placed by the contractor"
 // @Failure 400 {object} map[string]string "Invalid contractor ID"
-// @Failure 404 {object} map[string]string "No bids found for the contractor"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Security Bearer
 // @Router /api/users/{id}/bids [get]
@@ -363,31 +575,12 @@ func GetContractorBidHistory(ctx *gin.Context) {
 		return
 	}
 
-	cacheKey := fmt.Sprintf(bidsByContractorKey, contractorID)
-
-	cachedData, err := redisClient.Get(ctx.Request.Context(), cacheKey)
-	if err == nil && cachedData != "" {
-		var bids []model.Bid
-		if err := json.Unmarshal([]byte(cachedData), &bids); err == nil {
-			ctx.JSON(http.StatusOK, bids)
-			return
-		}
-	}
-
-	bids, err := bidService.GetBidsByContractor(contractorID)
+	bids, err := bidService.GetBidHistory(ctx.GetInt("user_id"), contractorID)
 	if err != nil {
-		if err.Error() == "no bids found" {
-			ctx.JSON(http.StatusNotFound, gin.H{"message": "No bids found for this contractor"})
-			return
-		}
-		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to fetch bid history", "error": err.Error()})
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to fetch bid history"})
 		return
 	}
 
-	if bidsJSON, err := json.Marshal(bids); err == nil {
-		_ = redisClient.Set(ctx.Request.Context(), cacheKey, bidsJSON, bidListCacheDuration)
-	}
-
 	ctx.JSON(http.StatusOK, bids)
 }
 
@@ -404,3 +597,15 @@ func GetContractorBidHistory(ctx *gin.Context) {
 func ManaulWebSocketSwag() {
 
 }
+
+// invalidateContractorBids drops the cached bid lists of the contractor and
+// their colleagues, who share the organization's bids.
+func invalidateContractorBids(c *gin.Context, contractorID int) {
+	ids, err := userService.ColleagueIDs(contractorID)
+	if err != nil {
+		ids = []int{contractorID}
+	}
+	for _, id := range ids {
+		_ = redisClient.Del(c.Request.Context(), fmt.Sprintf(bidsByContractorKey, id))
+	}
+}
@@ -0,0 +1,236 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+	"strings"
+	"tender-managment/internal/model"
+	"tender-managment/internal/service"
+	"time"
+)
+
+var (
+	contractService *service.ContractService
+)
+
+func SetContractService(contractSer *service.ContractService) {
+	contractService = contractSer
+}
+
+// CreateContractHandler godoc
+// @Summary Draft a contract from the winning bid
+// @Description Creates a contract for an awarded bid once the award is final. Price and delivery terms come from the bid; without an end date the contract runs for the bid's delivery time
+// @Tags Contract
+// @Accept json
+// @Produce json
+// @Param id path int true "Tender ID"
+// @Param contract body model.CreateContract true "Contract details"
+// @Success 201 {object} model.Contract
+// @Failure 400 {object} map[string]string "Invalid input, award not final or contract already exists"
+// @Failure 404 {object} map[string]string "Tender or bid not found"
+// @Security Bearer
+// @Router /api/client/tenders/{id}/contracts [post]
+func CreateContractHandler(c *gin.Context) {
+	tenderID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid tender ID"})
+		return
+	}
+
+	var payload model.CreateContract
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid input", "error": err.Error()})
+		return
+	}
+
+	startDate, err := time.Parse(time.RFC3339, payload.StartDate)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid start_date format"})
+		return
+	}
+	var endDate *time.Time
+	if payload.EndDate != "" {
+		parsed, err := time.Parse(time.RFC3339, payload.EndDate)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid end_date format"})
+			return
+		}
+		endDate = &parsed
+	}
+
+	contract, err := contractService.CreateContract(c.GetInt("user_id"), tenderID, payload, startDate, endDate)
+	if err != nil {
+		switch err.Error() {
+		case "tender not found", "bid not found":
+			c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+		case "award is not final yet", "only awarded bids can be contracted",
+			"end date must not be before the start date", "a contract already exists for this bid":
+			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to create contract", "error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, contract)
+}
+
+// ListContractsHandler godoc
+// @Summary List own contracts
+// @Description Lists the contracts the user is a party to, as client or contractor, optionally filtered by status
+// @Tags Contract
+// @Produce json
+// @Param status query string false "pending, active, completed or terminated"
+// @Success 200 {array} model.Contract
+// @Failure 400 {object} map[string]string "Unknown status"
+// @Security Bearer
+// @Router /api/contracts [get]
+func ListContractsHandler(c *gin.Context) {
+	contracts, err := contractService.ListContracts(c.GetInt("user_id"), c.Query("status"))
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "unknown contract status") {
+			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to fetch contracts"})
+		return
+	}
+
+	c.JSON(http.StatusOK, contracts)
+}
+
+// GetContractHandler godoc
+// @Summary Get a contract
+// @Description Returns a contract with links to its tender and bid
+// @Tags Contract
+// @Produce json
+// @Param id path int true "Contract ID"
+// @Success 200 {object} model.Contract
+// @Failure 400 {object} map[string]string "Invalid contract ID"
+// @Failure 404 {object} map[string]string "Contract not found"
+// @Security Bearer
+// @Router /api/contracts/{id} [get]
+func GetContractHandler(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid contract ID"})
+		return
+	}
+
+	contract, err := contractService.GetContract(c.GetInt("user_id"), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, contract)
+}
+
+// AcceptContractHandler godoc
+// @Summary Accept a contract
+// @Description Records the caller's acceptance. The contract becomes active once both parties have accepted it
+// @Tags Contract
+// @Produce json
+// @Param id path int true "Contract ID"
+// @Success 200 {object} model.Contract
+// @Failure 400 {object} map[string]string "Contract is no longer pending"
+// @Failure 404 {object} map[string]string "Contract not found"
+// @Security Bearer
+// @Router /api/contracts/{id}/accept [post]
+func AcceptContractHandler(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid contract ID"})
+		return
+	}
+
+	contract, err := contractService.AcceptContract(c.GetInt("user_id"), id)
+	if err != nil {
+		switch err.Error() {
+		case "contract not found":
+			c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+		case "contract is no longer pending":
+			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to accept contract", "error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, contract)
+}
+
+// CompleteContractHandler godoc
+// @Summary Complete a contract
+// @Description Lets the client mark an active contract as fulfilled once every milestone has been accepted
+// @Tags Contract
+// @Produce json
+// @Param id path int true "Contract ID"
+// @Success 200 {object} map[string]string "Contract completed"
+// @Failure 400 {object} map[string]string "Contract is not active or milestones are outstanding"
+// @Failure 404 {object} map[string]string "Contract not found"
+// @Security Bearer
+// @Router /api/contracts/{id}/complete [post]
+func CompleteContractHandler(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid contract ID"})
+		return
+	}
+
+	if err := contractService.CompleteContract(c.GetInt("user_id"), id); err != nil {
+		switch err.Error() {
+		case "contract not found":
+			c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+		case "only active contracts can be completed", "all milestones must be accepted before the contract is completed":
+			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to complete contract", "error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Contract completed"})
+}
+
+// TerminateContractHandler godoc
+// @Summary Terminate a contract
+// @Description Lets either party end a pending or active contract. The reason is passed on to the other party
+// @Tags Contract
+// @Accept json
+// @Produce json
+// @Param id path int true "Contract ID"
+// @Param termination body model.TerminateContract true "Reason for the termination"
+// @Success 200 {object} map[string]string "Contract terminated"
+// @Failure 400 {object} map[string]string "Missing reason or contract already closed"
+// @Failure 404 {object} map[string]string "Contract not found"
+// @Security Bearer
+// @Router /api/contracts/{id}/terminate [post]
+func TerminateContractHandler(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid contract ID"})
+		return
+	}
+
+	var payload model.TerminateContract
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid input", "error": err.Error()})
+		return
+	}
+
+	if err := contractService.TerminateContract(c.GetInt("user_id"), id, payload.Reason); err != nil {
+		switch err.Error() {
+		case "contract not found":
+			c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+		case "contract is already closed", "a reason is required to terminate a contract":
+			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to terminate contract", "error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Contract terminated"})
+}
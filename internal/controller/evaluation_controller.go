@@ -0,0 +1,403 @@
+package controller
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+	"strings"
+	"tender-managment/internal/model"
+	"tender-managment/internal/service"
+)
+
+var (
+	evaluationService *service.EvaluationService
+)
+
+func SetEvaluationService(evaluationSer *service.EvaluationService) {
+	evaluationService = evaluationSer
+}
+
+// AppointEvaluatorsHandler godoc
+// @Summary Appoint evaluators to a tender
+// @Description Adds client users to the tender's evaluation committee. Once a committee exists, awarding requires its sign-off
+// @Tags Evaluation
+// @Accept json
+// @Produce json
+// @Param id path int true "Tender ID"
+// @Param evaluators body model.AppointEvaluators true "Users to appoint"
+// @Success 200 {array} model.Evaluator
+// @Failure 400 {object} map[string]string "Invalid input or committee is locked"
+// @Failure 404 {object} map[string]string "Tender not found"
+// @Security Bearer
+// @Router /api/client/tenders/{id}/evaluators [post]
+func AppointEvaluatorsHandler(c *gin.Context) {
+	tenderID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid tender ID"})
+		return
+	}
+
+	var payload model.AppointEvaluators
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid input", "error": err.Error()})
+		return
+	}
+
+	evaluators, err := evaluationService.AppointEvaluators(c.GetInt("user_id"), tenderID, payload.EvaluatorIDs)
+	if err != nil {
+		switch {
+		case err.Error() == "tender not found":
+			c.JSON(http.StatusNotFound, gin.H{"message": "Tender not found"})
+		case err.Error() == "no evaluators to appoint", err.Error() == "evaluation committee can no longer be changed",
+			strings.HasSuffix(err.Error(), "cannot be an evaluator"):
+			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to appoint evaluators", "error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, evaluators)
+}
+
+// ListEvaluatorsHandler godoc
+// @Summary List the evaluation committee
+// @Description Lists the tender's evaluators with their submission and sign-off status
+// @Tags Evaluation
+// @Produce json
+// @Param id path int true "Tender ID"
+// @Success 200 {array} model.Evaluator
+// @Failure 400 {object} map[string]string "Invalid tender ID"
+// @Failure 404 {object} map[string]string "Tender not found"
+// @Security Bearer
+// @Router /api/client/tenders/{id}/evaluators [get]
+func ListEvaluatorsHandler(c *gin.Context) {
+	tenderID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid tender ID"})
+		return
+	}
+
+	evaluators, err := evaluationService.ListEvaluators(c.GetInt("user_id"), tenderID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"message": "Tender not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, evaluators)
+}
+
+// RemoveEvaluatorHandler godoc
+// @Summary Remove an evaluator
+// @Description Removes an evaluator and their scores from the committee before anyone has signed off
+// @Tags Evaluation
+// @Param id path int true "Tender ID"
+// @Param evaluatorId path int true "Evaluator user ID"
+// @Success 200 {object} map[string]string "Evaluator removed"
+// @Failure 400 {object} map[string]string "Invalid ID or committee is locked"
+// @Failure 404 {object} map[string]string "Tender or evaluator not found"
+// @Security Bearer
+// @Router /api/client/tenders/{id}/evaluators/{evaluatorId} [delete]
+func RemoveEvaluatorHandler(c *gin.Context) {
+	tenderID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid tender ID"})
+		return
+	}
+
+	evaluatorID, err := strconv.Atoi(c.Param("evaluatorId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid evaluator ID"})
+		return
+	}
+
+	if err := evaluationService.RemoveEvaluator(c.GetInt("user_id"), tenderID, evaluatorID); err != nil {
+		switch err.Error() {
+		case "tender not found", "evaluator not found":
+			c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+		case "evaluation committee can no longer be changed":
+			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to remove evaluator", "error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Evaluator removed"})
+}
+
+// GetEvaluationSheetHandler godoc
+// @Summary Get the evaluator's scoring sheet
+// @Description Returns the bids to score and the evaluator's own scores. Other evaluators' scores stay hidden
+// @Tags Evaluation
+// @Produce json
+// @Param id path int true "Tender ID"
+// @Success 200 {object} model.EvaluationSheet
+// @Failure 400 {object} map[string]string "Invalid tender ID"
+// @Failure 404 {object} map[string]string "Tender not found"
+// @Security Bearer
+// @Router /api/evaluations/tenders/{id} [get]
+func GetEvaluationSheetHandler(c *gin.Context) {
+	tenderID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid tender ID"})
+		return
+	}
+
+	sheet, err := evaluationService.GetSheet(c.GetInt("user_id"), tenderID)
+	if err != nil {
+		if err.Error() == "tender not found" {
+			c.JSON(http.StatusNotFound, gin.H{"message": "Tender not found"})
+			return
+		}
+		if isDeclarationError(err) {
+			c.JSON(http.StatusForbidden, gin.H{"message": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to fetch evaluation", "error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, sheet)
+}
+
+// SaveScoresHandler godoc
+// @Summary Save scores
+// @Description Saves or updates the evaluator's scores (0-100) until they are submitted
+// @Tags Evaluation
+// @Accept json
+// @Produce json
+// @Param id path int true "Tender ID"
+// @Param scores body []model.BidScore true "Scores per bid"
+// @Success 200 {object} map[string]string "Scores saved"
+// @Failure 400 {object} map[string]string "Invalid scores or already submitted"
+// @Failure 404 {object} map[string]string "Tender not found"
+// @Security Bearer
+// @Router /api/evaluations/tenders/{id}/scores [put]
+func SaveScoresHandler(c *gin.Context) {
+	tenderID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid tender ID"})
+		return
+	}
+
+	var payload []model.BidScore
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid input", "error": err.Error()})
+		return
+	}
+
+	if err := evaluationService.SaveScores(c.GetInt("user_id"), tenderID, payload); err != nil {
+		if err.Error() == "tender not found" {
+			c.JSON(http.StatusNotFound, gin.H{"message": "Tender not found"})
+			return
+		}
+		if isDeclarationError(err) {
+			c.JSON(http.StatusForbidden, gin.H{"message": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Scores saved"})
+}
+
+// SubmitScoresHandler godoc
+// @Summary Submit scores
+// @Description Locks the evaluator's scores. Every bid must be scored and the tender closed
+// @Tags Evaluation
+// @Produce json
+// @Param id path int true "Tender ID"
+// @Success 200 {object} map[string]string "Scores submitted"
+// @Failure 400 {object} map[string]string "Missing scores, tender still open or already submitted"
+// @Failure 404 {object} map[string]string "Tender not found"
+// @Security Bearer
+// @Router /api/evaluations/tenders/{id}/submit [post]
+func SubmitScoresHandler(c *gin.Context) {
+	tenderID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid tender ID"})
+		return
+	}
+
+	if err := evaluationService.SubmitScores(c.GetInt("user_id"), tenderID); err != nil {
+		if err.Error() == "tender not found" {
+			c.JSON(http.StatusNotFound, gin.H{"message": "Tender not found"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Scores submitted"})
+}
+
+// GetEvaluationResultsHandler godoc
+// @Summary Get committee results
+// @Description Returns all scores with averages and variance flags once every evaluator has submitted. Available to the tender owner and the evaluators
+// @Tags Evaluation
+// @Produce json
+// @Param id path int true "Tender ID"
+// @Success 200 {object} model.EvaluationResults
+// @Failure 400 {object} map[string]string "Scoring is not complete"
+// @Failure 404 {object} map[string]string "Tender not found"
+// @Security Bearer
+// @Router /api/evaluations/tenders/{id}/results [get]
+func GetEvaluationResultsHandler(c *gin.Context) {
+	tenderID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid tender ID"})
+		return
+	}
+
+	results, err := evaluationService.GetResults(c.GetInt("user_id"), tenderID)
+	if err != nil {
+		switch err.Error() {
+		case "tender not found":
+			c.JSON(http.StatusNotFound, gin.H{"message": "Tender not found"})
+		case "tender has no evaluation committee", "not every evaluator has submitted their scores":
+			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to fetch results", "error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, results)
+}
+
+// SignOffEvaluationHandler godoc
+// @Summary Sign off the committee results
+// @Description Records the evaluator's approval of the results. The tender can be awarded once every evaluator has signed off
+// @Tags Evaluation
+// @Produce json
+// @Param id path int true "Tender ID"
+// @Success 200 {object} map[string]string "Signed off"
+// @Failure 400 {object} map[string]string "Scoring is not complete or already signed off"
+// @Failure 404 {object} map[string]string "Tender not found"
+// @Security Bearer
+// @Router /api/evaluations/tenders/{id}/signoff [post]
+func SignOffEvaluationHandler(c *gin.Context) {
+	tenderID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid tender ID"})
+		return
+	}
+
+	if err := evaluationService.SignOff(c.GetInt("user_id"), tenderID); err != nil {
+		if err.Error() == "tender not found" {
+			c.JSON(http.StatusNotFound, gin.H{"message": "Tender not found"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Signed off"})
+}
+
+// GetDeclarationFormHandler godoc
+// @Summary Get the conflict-of-interest declaration form
+// @Description Lists the companies bidding on the tender that the owner or an evaluator has to declare against before viewing bids
+// @Tags Evaluation
+// @Produce json
+// @Param id path int true "Tender ID"
+// @Success 200 {array} model.DeclaredBidder
+// @Failure 400 {object} map[string]string "Invalid tender ID"
+// @Failure 404 {object} map[string]string "Tender not found"
+// @Security Bearer
+// @Router /api/client/tenders/{id}/declaration [get]
+func GetDeclarationFormHandler(c *gin.Context) {
+	tenderID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid tender ID"})
+		return
+	}
+
+	bidders, err := evaluationService.DeclarationForm(c.GetInt("user_id"), tenderID)
+	if err != nil {
+		if err.Error() == "tender not found" {
+			c.JSON(http.StatusNotFound, gin.H{"message": "Tender not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to fetch bidders", "error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, bidders)
+}
+
+// SignDeclarationHandler godoc
+// @Summary Sign a conflict-of-interest declaration
+// @Description Declares any conflict with the companies bidding on the tender. An evaluator who declares a conflict is removed from the committee
+// @Tags Evaluation
+// @Accept json
+// @Produce json
+// @Param id path int true "Tender ID"
+// @Param declaration body model.SignDeclaration true "Conflicting contractors, empty when there is no conflict"
+// @Success 201 {object} model.COIDeclaration
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 404 {object} map[string]string "Tender not found"
+// @Security Bearer
+// @Router /api/client/tenders/{id}/declaration [post]
+func SignDeclarationHandler(c *gin.Context) {
+	tenderID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid tender ID"})
+		return
+	}
+
+	var payload model.SignDeclaration
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid input", "error": err.Error()})
+		return
+	}
+
+	declaration, err := evaluationService.SignDeclaration(c.GetInt("user_id"), tenderID, payload)
+	if err != nil {
+		switch {
+		case err.Error() == "tender not found":
+			c.JSON(http.StatusNotFound, gin.H{"message": "Tender not found"})
+		case strings.HasSuffix(err.Error(), "has not bid on this tender"):
+			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to sign declaration", "error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, declaration)
+}
+
+// ListDeclarationsHandler godoc
+// @Summary List conflict-of-interest declarations
+// @Description Lists every declaration signed for the tender with its timestamp
+// @Tags Evaluation
+// @Produce json
+// @Param id path int true "Tender ID"
+// @Success 200 {array} model.COIDeclaration
+// @Failure 400 {object} map[string]string "Invalid tender ID"
+// @Failure 404 {object} map[string]string "Tender not found"
+// @Security Bearer
+// @Router /api/client/tenders/{id}/declarations [get]
+func ListDeclarationsHandler(c *gin.Context) {
+	tenderID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid tender ID"})
+		return
+	}
+
+	declarations, err := evaluationService.ListDeclarations(c.GetInt("user_id"), tenderID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"message": "Tender not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, declarations)
+}
+
+func isDeclarationError(err error) bool {
+	return errors.Is(err, service.ErrDeclarationRequired) || errors.Is(err, service.ErrDeclaredConflict)
+}
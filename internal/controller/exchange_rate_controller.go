@@ -0,0 +1,68 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strings"
+	"tender-managment/internal/model"
+	"tender-managment/internal/service"
+)
+
+var (
+	exchangeRateService *service.ExchangeRateService
+)
+
+func SetExchangeRateService(exchangeRateSer *service.ExchangeRateService) {
+	exchangeRateService = exchangeRateSer
+}
+
+// ListExchangeRatesHandler godoc
+// @Summary List exchange rates
+// @Description Lists the locally maintained exchange rates used to convert amounts between currencies
+// @Tags ExchangeRate
+// @Produce json
+// @Success 200 {array} model.ExchangeRate
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security Bearer
+// @Router /api/exchange-rates [get]
+func ListExchangeRatesHandler(c *gin.Context) {
+	rates, err := exchangeRateService.ListRates()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to fetch exchange rates"})
+		return
+	}
+
+	c.JSON(http.StatusOK, rates)
+}
+
+// SetExchangeRateHandler godoc
+// @Summary Set an exchange rate
+// @Description Stores how many units of the quote currency one unit of the base currency buys. The change is recorded in the admin audit trail
+// @Tags ExchangeRate
+// @Accept json
+// @Produce json
+// @Param rate body model.SetExchangeRate true "Currency pair and rate"
+// @Success 200 {object} model.ExchangeRate
+// @Failure 400 {object} map[string]string "Invalid currency or rate"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security Bearer
+// @Router /api/exchange-rates [put]
+func SetExchangeRateHandler(c *gin.Context) {
+	var payload model.SetExchangeRate
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid input"})
+		return
+	}
+
+	rate, err := adminService.SetExchangeRate(c.GetInt("user_id"), payload)
+	if err != nil {
+		if err.Error() == "invalid currency" || strings.HasPrefix(err.Error(), "invalid exchange rate") {
+			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to save exchange rate"})
+		return
+	}
+
+	c.JSON(http.StatusOK, rate)
+}
@@ -0,0 +1,291 @@
+package controller
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+	"strings"
+	"tender-managment/internal/model"
+	"tender-managment/internal/service"
+	"time"
+)
+
+var (
+	invoiceService *service.InvoiceService
+)
+
+func SetInvoiceService(invoiceSer *service.InvoiceService) {
+	invoiceService = invoiceSer
+}
+
+// CreateInvoiceHandler godoc
+// @Summary Raise an invoice
+// @Description Invoices an active or completed contract, or one of its accepted milestones. Amounts and taxes are calculated server-side; invoicing beyond the awarded price is allowed but returned with warnings
+// @Tags Invoice
+// @Accept json
+// @Produce json
+// @Param id path int true "Contract ID"
+// @Param invoice body model.CreateInvoice true "Invoice lines and taxes"
+// @Success 201 {object} model.Invoice
+// @Failure 400 {object} map[string]string "Invalid invoice"
+// @Failure 404 {object} map[string]string "Contract or milestone not found"
+// @Security Bearer
+// @Router /api/contracts/{id}/invoices [post]
+func CreateInvoiceHandler(c *gin.Context) {
+	contractID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid contract ID"})
+		return
+	}
+
+	var payload model.CreateInvoice
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid input", "error": err.Error()})
+		return
+	}
+
+	invoice, err := invoiceService.CreateInvoice(c.GetInt("user_id"), contractID, payload)
+	if err != nil {
+		switch {
+		case err.Error() == "contract not found", err.Error() == "milestone not found":
+			c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+		case strings.HasPrefix(err.Error(), "failed to"):
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to create invoice", "error": err.Error()})
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, invoice)
+}
+
+// ListInvoicesHandler godoc
+// @Summary List a contract's invoices
+// @Description Lists the invoices raised under the contract with their lines and taxes
+// @Tags Invoice
+// @Produce json
+// @Param id path int true "Contract ID"
+// @Success 200 {array} model.Invoice
+// @Failure 400 {object} map[string]string "Invalid contract ID"
+// @Failure 404 {object} map[string]string "Contract not found"
+// @Security Bearer
+// @Router /api/contracts/{id}/invoices [get]
+func ListInvoicesHandler(c *gin.Context) {
+	contractID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid contract ID"})
+		return
+	}
+
+	invoices, err := invoiceService.ListInvoices(c.GetInt("user_id"), contractID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"message": "Contract not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, invoices)
+}
+
+// ReconcileInvoicesHandler godoc
+// @Summary Reconcile invoices with the contract price
+// @Description Compares the net invoiced, approved and paid totals with the awarded price and flags over-invoicing
+// @Tags Invoice
+// @Produce json
+// @Param id path int true "Contract ID"
+// @Success 200 {object} model.InvoiceReconciliation
+// @Failure 400 {object} map[string]string "Invalid contract ID"
+// @Failure 404 {object} map[string]string "Contract not found"
+// @Security Bearer
+// @Router /api/contracts/{id}/invoices/reconciliation [get]
+func ReconcileInvoicesHandler(c *gin.Context) {
+	contractID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid contract ID"})
+		return
+	}
+
+	reconciliation, err := invoiceService.Reconcile(c.GetInt("user_id"), contractID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"message": "Contract not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, reconciliation)
+}
+
+// GetInvoiceHandler godoc
+// @Summary Get an invoice
+// @Tags Invoice
+// @Produce json
+// @Param id path int true "Invoice ID"
+// @Success 200 {object} model.Invoice
+// @Failure 400 {object} map[string]string "Invalid invoice ID"
+// @Failure 404 {object} map[string]string "Invoice not found"
+// @Security Bearer
+// @Router /api/invoices/{id} [get]
+func GetInvoiceHandler(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid invoice ID"})
+		return
+	}
+
+	invoice, err := invoiceService.GetInvoice(c.GetInt("user_id"), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, invoice)
+}
+
+// DownloadInvoicePDFHandler godoc
+// @Summary Download an invoice as PDF
+// @Tags Invoice
+// @Produce application/pdf
+// @Param id path int true "Invoice ID"
+// @Success 200 {file} file
+// @Failure 400 {object} map[string]string "Invalid invoice ID"
+// @Failure 404 {object} map[string]string "Invoice not found"
+// @Security Bearer
+// @Router /api/invoices/{id}/pdf [get]
+func DownloadInvoicePDFHandler(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid invoice ID"})
+		return
+	}
+
+	var buf bytes.Buffer
+	invoice, err := invoiceService.RenderPDF(c.GetInt("user_id"), id, &buf)
+	if err != nil {
+		if err.Error() == "invoice not found" {
+			c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to render invoice", "error": err.Error()})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", invoice.Number+".pdf"))
+	c.Data(http.StatusOK, "application/pdf", buf.Bytes())
+}
+
+// ApproveInvoiceHandler godoc
+// @Summary Approve an invoice
+// @Tags Invoice
+// @Produce json
+// @Param id path int true "Invoice ID"
+// @Success 200 {object} map[string]string "Invoice approved"
+// @Failure 400 {object} map[string]string "Invoice is not submitted"
+// @Failure 404 {object} map[string]string "Invoice not found"
+// @Security Bearer
+// @Router /api/invoices/{id}/approve [post]
+func ApproveInvoiceHandler(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid invoice ID"})
+		return
+	}
+
+	if err := invoiceService.ApproveInvoice(c.GetInt("user_id"), id); err != nil {
+		invoiceError(c, err, "Failed to approve invoice")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Invoice approved"})
+}
+
+// DisputeInvoiceHandler godoc
+// @Summary Dispute an invoice
+// @Description Disputes a submitted or approved invoice. Disputed invoices do not count towards the invoiced total
+// @Tags Invoice
+// @Accept json
+// @Produce json
+// @Param id path int true "Invoice ID"
+// @Param dispute body model.DisputeInvoice true "Reason for the dispute"
+// @Success 200 {object} map[string]string "Invoice disputed"
+// @Failure 400 {object} map[string]string "Missing reason or invoice already paid"
+// @Failure 404 {object} map[string]string "Invoice not found"
+// @Security Bearer
+// @Router /api/invoices/{id}/dispute [post]
+func DisputeInvoiceHandler(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid invoice ID"})
+		return
+	}
+
+	var payload model.DisputeInvoice
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid input", "error": err.Error()})
+		return
+	}
+
+	if err := invoiceService.DisputeInvoice(c.GetInt("user_id"), id, payload.Reason); err != nil {
+		invoiceError(c, err, "Failed to dispute invoice")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Invoice disputed"})
+}
+
+// RecordPaymentHandler godoc
+// @Summary Record an invoice payment
+// @Description Marks an approved invoice as paid
+// @Tags Invoice
+// @Accept json
+// @Produce json
+// @Param id path int true "Invoice ID"
+// @Param payment body model.RecordPayment true "Payment reference and optional date (RFC3339)"
+// @Success 200 {object} map[string]string "Payment recorded"
+// @Failure 400 {object} map[string]string "Invalid payment or invoice not approved"
+// @Failure 404 {object} map[string]string "Invoice not found"
+// @Security Bearer
+// @Router /api/invoices/{id}/payment [post]
+func RecordPaymentHandler(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid invoice ID"})
+		return
+	}
+
+	var payload model.RecordPayment
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid input", "error": err.Error()})
+		return
+	}
+
+	var paidAt *time.Time
+	if payload.PaidAt != "" {
+		parsed, err := time.Parse(time.RFC3339, payload.PaidAt)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid paid_at format"})
+			return
+		}
+		paidAt = &parsed
+	}
+
+	if err := invoiceService.RecordPayment(c.GetInt("user_id"), id, payload.Reference, paidAt); err != nil {
+		invoiceError(c, err, "Failed to record payment")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Payment recorded"})
+}
+
+func invoiceError(c *gin.Context, err error, message string) {
+	switch err.Error() {
+	case "invoice not found":
+		c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+	case "only submitted invoices can be approved", "paid or disputed invoices cannot be disputed",
+		"only approved invoices can be paid", "a reason is required to dispute an invoice",
+		"a payment reference is required", "payment date cannot be in the future":
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"message": message, "error": err.Error()})
+	}
+}
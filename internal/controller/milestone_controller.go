@@ -0,0 +1,283 @@
+package controller
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"tender-managment/internal/model"
+	"tender-managment/internal/service"
+)
+
+var (
+	milestoneService *service.MilestoneService
+)
+
+func SetMilestoneService(milestoneSer *service.MilestoneService) {
+	milestoneService = milestoneSer
+}
+
+// SetMilestonesHandler godoc
+// @Summary Set a contract's milestones
+// @Description Replaces the milestone schedule. Amounts must add up to the contract price, and the schedule is locked once a milestone has been delivered or invoiced
+// @Tags Contract
+// @Accept json
+// @Produce json
+// @Param id path int true "Contract ID"
+// @Param milestones body []model.SetMilestone true "Milestones in delivery order"
+// @Success 200 {array} model.Milestone
+// @Failure 400 {object} map[string]string "Invalid schedule or schedule locked"
+// @Failure 404 {object} map[string]string "Contract not found"
+// @Security Bearer
+// @Router /api/contracts/{id}/milestones [put]
+func SetMilestonesHandler(c *gin.Context) {
+	contractID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid contract ID"})
+		return
+	}
+
+	var payload []model.SetMilestone
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid input", "error": err.Error()})
+		return
+	}
+
+	milestones, err := milestoneService.SetSchedule(c.GetInt("user_id"), contractID, payload)
+	if err != nil {
+		if err.Error() == "contract not found" {
+			c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, milestones)
+}
+
+// ListMilestonesHandler godoc
+// @Summary List a contract's milestones
+// @Description Lists the milestones with their delivery status and proof documents
+// @Tags Contract
+// @Produce json
+// @Param id path int true "Contract ID"
+// @Success 200 {array} model.Milestone
+// @Failure 400 {object} map[string]string "Invalid contract ID"
+// @Failure 404 {object} map[string]string "Contract not found"
+// @Security Bearer
+// @Router /api/contracts/{id}/milestones [get]
+func ListMilestonesHandler(c *gin.Context) {
+	contractID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid contract ID"})
+		return
+	}
+
+	milestones, err := milestoneService.ListMilestones(c.GetInt("user_id"), contractID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"message": "Contract not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, milestones)
+}
+
+// DeliverMilestoneHandler godoc
+// @Summary Deliver a milestone
+// @Description Marks a milestone of an active contract as delivered for the client's review. Rejected milestones can be delivered again
+// @Tags Contract
+// @Accept json
+// @Produce json
+// @Param id path int true "Contract ID"
+// @Param milestoneId path int true "Milestone ID"
+// @Param delivery body model.DeliverMilestone false "Delivery note"
+// @Success 200 {object} model.Milestone
+// @Failure 400 {object} map[string]string "Contract not active or milestone already delivered"
+// @Failure 404 {object} map[string]string "Milestone not found"
+// @Security Bearer
+// @Router /api/contracts/{id}/milestones/{milestoneId}/deliver [post]
+func DeliverMilestoneHandler(c *gin.Context) {
+	contractID, milestoneID, ok := milestoneParams(c)
+	if !ok {
+		return
+	}
+
+	var payload model.DeliverMilestone
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&payload); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid input", "error": err.Error()})
+			return
+		}
+	}
+
+	milestone, err := milestoneService.Deliver(c.GetInt("user_id"), contractID, milestoneID, payload.Note)
+	if err != nil {
+		switch err.Error() {
+		case "milestone not found":
+			c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+		case "contract is not active", "milestone has already been delivered":
+			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to deliver milestone", "error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, milestone)
+}
+
+// UploadMilestoneDocumentHandler godoc
+// @Summary Attach proof of delivery
+// @Description Adds a document to a milestone that has not been accepted yet
+// @Tags Contract
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path int true "Contract ID"
+// @Param milestoneId path int true "Milestone ID"
+// @Param file formData file true "Proof of delivery"
+// @Success 201 {object} model.MilestoneDocument
+// @Failure 400 {object} map[string]string "Invalid file or milestone already accepted"
+// @Failure 404 {object} map[string]string "Milestone not found"
+// @Failure 413 {object} map[string]string "File too large"
+// @Failure 415 {object} map[string]string "File type not allowed"
+// @Security Bearer
+// @Router /api/contracts/{id}/milestones/{milestoneId}/documents [post]
+func UploadMilestoneDocumentHandler(c *gin.Context) {
+	contractID, milestoneID, ok := milestoneParams(c)
+	if !ok {
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "File is required"})
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Failed to read file"})
+		return
+	}
+	defer file.Close()
+
+	doc, status, err := milestoneService.AddDocument(c.Request.Context(), c.GetInt("user_id"), contractID, milestoneID, fileHeader.Filename, file)
+	if err != nil {
+		c.JSON(status, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(status, doc)
+}
+
+// AcceptMilestoneHandler godoc
+// @Summary Accept a milestone
+// @Description Accepts a delivered milestone
+// @Tags Contract
+// @Accept json
+// @Produce json
+// @Param id path int true "Contract ID"
+// @Param milestoneId path int true "Milestone ID"
+// @Param review body model.ReviewMilestone false "Optional comment"
+// @Success 200 {object} model.Milestone
+// @Failure 400 {object} map[string]string "Milestone not awaiting review"
+// @Failure 404 {object} map[string]string "Milestone not found"
+// @Security Bearer
+// @Router /api/contracts/{id}/milestones/{milestoneId}/accept [post]
+func AcceptMilestoneHandler(c *gin.Context) {
+	reviewMilestone(c, true)
+}
+
+// RejectMilestoneHandler godoc
+// @Summary Reject a milestone
+// @Description Rejects a delivered milestone. A comment is required and sent to the contractor
+// @Tags Contract
+// @Accept json
+// @Produce json
+// @Param id path int true "Contract ID"
+// @Param milestoneId path int true "Milestone ID"
+// @Param review body model.ReviewMilestone true "What needs to change"
+// @Success 200 {object} model.Milestone
+// @Failure 400 {object} map[string]string "Missing comment or milestone not awaiting review"
+// @Failure 404 {object} map[string]string "Milestone not found"
+// @Security Bearer
+// @Router /api/contracts/{id}/milestones/{milestoneId}/reject [post]
+func RejectMilestoneHandler(c *gin.Context) {
+	reviewMilestone(c, false)
+}
+
+func reviewMilestone(c *gin.Context, accept bool) {
+	contractID, milestoneID, ok := milestoneParams(c)
+	if !ok {
+		return
+	}
+
+	var payload model.ReviewMilestone
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&payload); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid input", "error": err.Error()})
+			return
+		}
+	}
+
+	milestone, err := milestoneService.Review(c.GetInt("user_id"), contractID, milestoneID, accept, payload.Comment)
+	if err != nil {
+		switch {
+		case err.Error() == "milestone not found":
+			c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+		case err.Error() == "milestone is not awaiting review", strings.HasPrefix(err.Error(), "a comment is required"):
+			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to review milestone", "error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, milestone)
+}
+
+// DownloadMilestoneDocumentHandler godoc
+// @Summary Download a milestone document
+// @Description Streams a proof of delivery to either party of the contract
+// @Tags Contract
+// @Produce octet-stream
+// @Param id path int true "Document ID"
+// @Success 200 {file} file
+// @Failure 400 {object} map[string]string "Invalid document ID"
+// @Failure 404 {object} map[string]string "Document not found"
+// @Security Bearer
+// @Router /api/milestones/documents/{id} [get]
+func DownloadMilestoneDocumentHandler(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid document ID"})
+		return
+	}
+
+	doc, file, err := milestoneService.OpenDocument(c.Request.Context(), c.GetInt("user_id"), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+		return
+	}
+	defer file.Close()
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", doc.FileName))
+	c.Header("X-Content-SHA256", doc.SHA256)
+	c.DataFromReader(http.StatusOK, doc.Size, doc.MimeType, io.Reader(file), nil)
+}
+
+func milestoneParams(c *gin.Context) (int, int, bool) {
+	contractID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid contract ID"})
+		return 0, 0, false
+	}
+	milestoneID, err := strconv.Atoi(c.Param("milestoneId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid milestone ID"})
+		return 0, 0, false
+	}
+	return contractID, milestoneID, true
+}
@@ -0,0 +1,268 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+	"strings"
+	"tender-managment/internal/model"
+	"tender-managment/internal/service"
+)
+
+var (
+	organizationService *service.OrganizationService
+)
+
+func SetOrganizationService(organizationSer *service.OrganizationService) {
+	organizationService = organizationSer
+}
+
+// CreateOrganizationHandler godoc
+// @Summary Register an organization
+// @Description Registers the caller's company and makes the caller its first member. Their existing tenders and bids move to the organization
+// @Tags Organization
+// @Accept json
+// @Produce json
+// @Param organization body model.SaveOrganization true "Company details"
+// @Success 201 {object} model.Organization
+// @Failure 400 {object} map[string]string "Invalid input, duplicate tax ID or already a member of an organization"
+// @Security Bearer
+// @Router /api/orgs [post]
+func CreateOrganizationHandler(c *gin.Context) {
+	var payload model.SaveOrganization
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid input", "error": err.Error()})
+		return
+	}
+
+	userID := c.GetInt("user_id")
+	org, err := organizationService.CreateOrganization(userID, payload)
+	if err != nil {
+		organizationError(c, err)
+		return
+	}
+
+	invalidateTenderLists(c, userID)
+	invalidateContractorBids(c, userID)
+	c.JSON(http.StatusCreated, org)
+}
+
+// GetOrganizationHandler godoc
+// @Summary Get an organization
+// @Description Returns the company details and contact people of an organization
+// @Tags Organization
+// @Produce json
+// @Param id path int true "Organization ID"
+// @Success 200 {object} model.Organization
+// @Failure 400 {object} map[string]string "Invalid organization ID"
+// @Failure 404 {object} map[string]string "Organization not found"
+// @Security Bearer
+// @Router /api/orgs/{id} [get]
+func GetOrganizationHandler(c *gin.Context) {
+	orgID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid organization ID"})
+		return
+	}
+
+	org, err := organizationService.GetOrganization(orgID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"message": "Organization not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, org)
+}
+
+// UpdateOrganizationHandler godoc
+// @Summary Update an organization
+// @Description Updates the company details and replaces the contact people. Only members can update their organization
+// @Tags Organization
+// @Accept json
+// @Produce json
+// @Param id path int true "Organization ID"
+// @Param organization body model.SaveOrganization true "Company details"
+// @Success 200 {object} model.Organization
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 404 {object} map[string]string "Organization not found"
+// @Security Bearer
+// @Router /api/orgs/{id} [put]
+func UpdateOrganizationHandler(c *gin.Context) {
+	orgID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid organization ID"})
+		return
+	}
+
+	var payload model.SaveOrganization
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid input", "error": err.Error()})
+		return
+	}
+
+	org, err := organizationService.UpdateOrganization(c.GetInt("user_id"), orgID, payload)
+	if err != nil {
+		organizationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, org)
+}
+
+// ListMembersHandler godoc
+// @Summary List organization members
+// @Description Lists the colleagues in the caller's organization
+// @Tags Organization
+// @Produce json
+// @Param id path int true "Organization ID"
+// @Success 200 {array} model.UserProfile
+// @Failure 400 {object} map[string]string "Invalid organization ID"
+// @Failure 404 {object} map[string]string "Organization not found"
+// @Security Bearer
+// @Router /api/orgs/{id}/members [get]
+func ListMembersHandler(c *gin.Context) {
+	orgID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid organization ID"})
+		return
+	}
+
+	members, err := organizationService.ListMembers(c.GetInt("user_id"), orgID)
+	if err != nil {
+		organizationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, members)
+}
+
+// AddMemberHandler godoc
+// @Summary Add a colleague
+// @Description Adds a registered user who has no organization yet with the role owner, procurement_officer, evaluator, viewer or bid_manager. Their tenders and bids move to the organization
+// @Tags Organization
+// @Accept json
+// @Produce json
+// @Param id path int true "Organization ID"
+// @Param member body model.AddMember true "Email and role of the colleague"
+// @Success 201 {object} model.UserProfile
+// @Failure 400 {object} map[string]string "Invalid input, unknown role or user already belongs to an organization"
+// @Failure 404 {object} map[string]string "Organization or user not found"
+// @Security Bearer
+// @Router /api/orgs/{id}/members [post]
+func AddMemberHandler(c *gin.Context) {
+	orgID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid organization ID"})
+		return
+	}
+
+	var payload model.AddMember
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid input", "error": err.Error()})
+		return
+	}
+
+	member, err := organizationService.AddMember(c.GetInt("user_id"), orgID, payload.Email, payload.Role)
+	if err != nil {
+		organizationError(c, err)
+		return
+	}
+
+	invalidateTenderLists(c, member.ID)
+	invalidateContractorBids(c, member.ID)
+	c.JSON(http.StatusCreated, member)
+}
+
+// RemoveMemberHandler godoc
+// @Summary Remove a colleague
+// @Description Takes a member out of the organization. Members can remove themselves to leave. Tenders and bids stay with the organization
+// @Tags Organization
+// @Produce json
+// @Param id path int true "Organization ID"
+// @Param userId path int true "User ID"
+// @Success 200 {object} map[string]string "Member removed"
+// @Failure 400 {object} map[string]string "Invalid ID or last owner"
+// @Failure 403 {object} map[string]string "Not allowed to remove other members"
+// @Failure 404 {object} map[string]string "Organization or member not found"
+// @Security Bearer
+// @Router /api/orgs/{id}/members/{userId} [delete]
+func RemoveMemberHandler(c *gin.Context) {
+	orgID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid organization ID"})
+		return
+	}
+	memberID, err := strconv.Atoi(c.Param("userId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid user ID"})
+		return
+	}
+
+	// Drop the caches while the member is still linked to their colleagues.
+	invalidateTenderLists(c, memberID)
+	invalidateContractorBids(c, memberID)
+
+	if err := organizationService.RemoveMember(c.GetInt("user_id"), orgID, memberID); err != nil {
+		organizationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Member removed"})
+}
+
+// SetMemberRoleHandler godoc
+// @Summary Change a colleague's role
+// @Description Gives a member the role owner, procurement_officer, evaluator, viewer or bid_manager. The last owner cannot step down
+// @Tags Organization
+// @Accept json
+// @Produce json
+// @Param id path int true "Organization ID"
+// @Param userId path int true "User ID"
+// @Param role body model.SetMemberRole true "New role"
+// @Success 200 {object} model.UserProfile
+// @Failure 400 {object} map[string]string "Unknown role or last owner"
+// @Failure 404 {object} map[string]string "Organization or member not found"
+// @Security Bearer
+// @Router /api/orgs/{id}/members/{userId}/role [put]
+func SetMemberRoleHandler(c *gin.Context) {
+	orgID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid organization ID"})
+		return
+	}
+	memberID, err := strconv.Atoi(c.Param("userId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid user ID"})
+		return
+	}
+
+	var payload model.SetMemberRole
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid input", "error": err.Error()})
+		return
+	}
+
+	member, err := organizationService.SetMemberRole(c.GetInt("user_id"), orgID, memberID, payload.Role)
+	if err != nil {
+		organizationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, member)
+}
+
+func organizationError(c *gin.Context, err error) {
+	switch {
+	case err.Error() == "organization not found", err.Error() == "user not found", err.Error() == "member not found":
+		c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+	case err.Error() == "only organization managers can remove other members":
+		c.JSON(http.StatusForbidden, gin.H{"message": err.Error()})
+	case err.Error() == "legal name and tax ID are required", err.Error() == "an organization with this tax ID already exists",
+		err.Error() == "user already belongs to an organization", err.Error() == "an organization needs at least one owner",
+		err.Error() == "administrators cannot join organizations",
+		strings.HasSuffix(err.Error(), "needs a name"), strings.HasPrefix(err.Error(), "unknown role"):
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to save organization", "error": err.Error()})
+	}
+}
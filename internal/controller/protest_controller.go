@@ -0,0 +1,219 @@
+package controller
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"tender-managment/internal/model"
+	"tender-managment/internal/service"
+)
+
+var (
+	protestService *service.ProtestService
+)
+
+func SetProtestService(protestSer *service.ProtestService) {
+	protestService = protestSer
+}
+
+// FileProtestHandler godoc
+// @Summary Protest an award
+// @Description Lets a losing bidder challenge a provisional award during the standstill period. The award cannot become final until the protest is resolved
+// @Tags Protest
+// @Accept json
+// @Produce json
+// @Param id path int true "Tender ID"
+// @Param protest body model.FileProtest true "Reasons for the protest"
+// @Success 201 {object} model.Protest
+// @Failure 400 {object} map[string]string "Standstill not running or protest already pending"
+// @Failure 404 {object} map[string]string "Tender not found"
+// @Security Bearer
+// @Router /api/contractor/tenders/{id}/protests [post]
+func FileProtestHandler(c *gin.Context) {
+	tenderID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid tender ID"})
+		return
+	}
+
+	var payload model.FileProtest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid input", "error": err.Error()})
+		return
+	}
+
+	protest, status, err := protestService.FileProtest(c.GetInt("user_id"), tenderID, payload.Reasons)
+	if err != nil {
+		c.JSON(status, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(status, protest)
+}
+
+// UploadProtestDocumentHandler godoc
+// @Summary Attach a document to a protest
+// @Description Adds supporting evidence to a pending protest
+// @Tags Protest
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path int true "Protest ID"
+// @Param file formData file true "Supporting document"
+// @Success 201 {object} model.ProtestDocument
+// @Failure 400 {object} map[string]string "Invalid file or protest already resolved"
+// @Failure 404 {object} map[string]string "Protest not found"
+// @Failure 413 {object} map[string]string "File too large"
+// @Failure 415 {object} map[string]string "File type not allowed"
+// @Security Bearer
+// @Router /api/contractor/protests/{id}/documents [post]
+func UploadProtestDocumentHandler(c *gin.Context) {
+	protestID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid protest ID"})
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "File is required"})
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Failed to read file"})
+		return
+	}
+	defer file.Close()
+
+	doc, status, err := protestService.AddDocument(c.Request.Context(), c.GetInt("user_id"), protestID, fileHeader.Filename, file)
+	if err != nil {
+		c.JSON(status, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(status, doc)
+}
+
+// ListOwnProtestsHandler godoc
+// @Summary List own protests
+// @Description Lists the contractor's protests with their resolution
+// @Tags Protest
+// @Produce json
+// @Success 200 {array} model.Protest
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security Bearer
+// @Router /api/contractor/protests [get]
+func ListOwnProtestsHandler(c *gin.Context) {
+	protests, err := protestService.ListOwnProtests(c.GetInt("user_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to fetch protests"})
+		return
+	}
+
+	c.JSON(http.StatusOK, protests)
+}
+
+// ListTenderProtestsHandler godoc
+// @Summary List protests against an award
+// @Description Lists every protest filed against the tender's award
+// @Tags Protest
+// @Produce json
+// @Param id path int true "Tender ID"
+// @Success 200 {array} model.Protest
+// @Failure 400 {object} map[string]string "Invalid tender ID"
+// @Failure 404 {object} map[string]string "Tender not found"
+// @Security Bearer
+// @Router /api/client/tenders/{id}/protests [get]
+func ListTenderProtestsHandler(c *gin.Context) {
+	tenderID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid tender ID"})
+		return
+	}
+
+	protests, err := protestService.ListProtests(c.GetInt("user_id"), tenderID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"message": "Tender not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, protests)
+}
+
+// ResolveProtestHandler godoc
+// @Summary Resolve a protest
+// @Description Upholds the award ("uphold") or reverses it ("reverse"). Reversing reopens the award decision and resolves every pending protest on the tender
+// @Tags Protest
+// @Accept json
+// @Produce json
+// @Param id path int true "Protest ID"
+// @Param decision body model.ResolveProtest true "Decision and explanation"
+// @Success 200 {object} map[string]string "Protest resolved"
+// @Failure 400 {object} map[string]string "Invalid decision or protest already resolved"
+// @Failure 404 {object} map[string]string "Protest not found"
+// @Security Bearer
+// @Router /api/client/protests/{id}/resolve [post]
+func ResolveProtestHandler(c *gin.Context) {
+	protestID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid protest ID"})
+		return
+	}
+
+	var payload model.ResolveProtest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid input", "error": err.Error()})
+		return
+	}
+
+	tenderID, err := protestService.ResolveProtest(c.GetInt("user_id"), protestID, payload)
+	if err != nil {
+		switch {
+		case err.Error() == "protest not found":
+			c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+		case err.Error() == "protest already resolved", err.Error() == "award is already final",
+			err.Error() == "a note explaining the decision is required", strings.HasPrefix(err.Error(), "unknown decision"):
+			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to resolve protest", "error": err.Error()})
+		}
+		return
+	}
+
+	_ = redisClient.Del(c.Request.Context(), fmt.Sprintf(bidsByTenderKey, tenderID))
+	invalidateTenderLists(c, c.GetInt("user_id"))
+	c.JSON(http.StatusOK, gin.H{"message": "Protest resolved"})
+}
+
+// DownloadProtestDocumentHandler godoc
+// @Summary Download a protest document
+// @Description Streams the document to the protester or the tender owner
+// @Tags Protest
+// @Produce octet-stream
+// @Param id path int true "Document ID"
+// @Success 200 {file} file
+// @Failure 400 {object} map[string]string "Invalid document ID"
+// @Failure 404 {object} map[string]string "Document not found"
+// @Security Bearer
+// @Router /api/protests/documents/{id} [get]
+func DownloadProtestDocumentHandler(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid document ID"})
+		return
+	}
+
+	doc, file, err := protestService.OpenDocument(c.Request.Context(), c.GetInt("user_id"), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+		return
+	}
+	defer file.Close()
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", doc.FileName))
+	c.Header("X-Content-SHA256", doc.SHA256)
+	c.DataFromReader(http.StatusOK, doc.Size, doc.MimeType, io.Reader(file), nil)
+}
@@ -0,0 +1,280 @@
+package controller
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"io"
+	"net/http"
+	"strconv"
+	"tender-managment/internal/model"
+	"tender-managment/internal/service"
+	"time"
+)
+
+var (
+	qualificationService *service.QualificationService
+)
+
+func SetQualificationService(qualificationSer *service.QualificationService) {
+	qualificationService = qualificationSer
+}
+
+// SubmitQualificationHandler godoc
+// @Summary Submit a qualification document
+// @Description Uploads a license, certification or turnover statement for review. Turnover submissions need an amount and currency
+// @Tags Qualification
+// @Accept multipart/form-data
+// @Produce json
+// @Param type formData string true "license, certification or turnover"
+// @Param code formData string false "License or certification code"
+// @Param amount formData string false "Annual turnover, e.g. 250000.00"
+// @Param currency formData string false "Turnover currency"
+// @Param expires_at formData string false "Document expiry (RFC3339)"
+// @Param file formData file true "Supporting document"
+// @Success 201 {object} model.Qualification
+// @Failure 400 {object} map[string]string "Invalid input or file"
+// @Failure 413 {object} map[string]string "File too large"
+// @Failure 415 {object} map[string]string "File type not allowed"
+// @Security Bearer
+// @Router /api/contractor/qualifications [post]
+func SubmitQualificationHandler(c *gin.Context) {
+	q := model.Qualification{
+		ContractorID: c.GetInt("user_id"),
+		Type:         c.PostForm("type"),
+		Code:         c.PostForm("code"),
+	}
+
+	if amount := c.PostForm("amount"); amount != "" {
+		value, err := model.ParseAmount(amount)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid amount"})
+			return
+		}
+		q.Turnover = &model.Money{Amount: value, Currency: c.PostForm("currency")}
+	}
+
+	if expiresAt := c.PostForm("expires_at"); expiresAt != "" {
+		parsed, err := time.Parse(time.RFC3339, expiresAt)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid expires_at format"})
+			return
+		}
+		q.ExpiresAt = &parsed
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "File is required"})
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Failed to read file"})
+		return
+	}
+	defer file.Close()
+
+	created, status, err := qualificationService.SubmitQualification(c.Request.Context(), &q, fileHeader.Filename, file)
+	if err != nil {
+		c.JSON(status, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(status, created)
+}
+
+// ListQualificationsHandler godoc
+// @Summary List own qualifications
+// @Description Lists the contractor's qualifications with their review status
+// @Tags Qualification
+// @Produce json
+// @Success 200 {array} model.Qualification
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security Bearer
+// @Router /api/contractor/qualifications [get]
+func ListQualificationsHandler(c *gin.Context) {
+	qualifications, err := qualificationService.ListQualifications(c.GetInt("user_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to fetch qualifications"})
+		return
+	}
+
+	c.JSON(http.StatusOK, qualifications)
+}
+
+// DeleteQualificationHandler godoc
+// @Summary Delete a qualification
+// @Description Withdraws a qualification and its document
+// @Tags Qualification
+// @Param id path int true "Qualification ID"
+// @Success 200 {object} map[string]string "Qualification deleted successfully"
+// @Failure 400 {object} map[string]string "Invalid qualification ID"
+// @Failure 404 {object} map[string]string "Qualification not found"
+// @Security Bearer
+// @Router /api/contractor/qualifications/{id} [delete]
+func DeleteQualificationHandler(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid qualification ID"})
+		return
+	}
+
+	if err := qualificationService.DeleteQualification(c.Request.Context(), c.GetInt("user_id"), id); err != nil {
+		if err.Error() == "qualification not found" {
+			c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to delete qualification", "error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Qualification deleted successfully"})
+}
+
+// TenderEligibilityHandler godoc
+// @Summary Check eligibility for a tender
+// @Description Reports whether the contractor holds every qualification the tender requires and lists what is missing
+// @Tags Qualification
+// @Produce json
+// @Param id path int true "Tender ID"
+// @Success 200 {object} model.Eligibility
+// @Failure 400 {object} map[string]string "Invalid tender ID"
+// @Failure 404 {object} map[string]string "Tender not found"
+// @Security Bearer
+// @Router /api/contractor/tenders/{id}/eligibility [get]
+func TenderEligibilityHandler(c *gin.Context) {
+	tenderID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid tender ID"})
+		return
+	}
+
+	eligibility, err := qualificationService.TenderEligibility(c.GetInt("user_id"), tenderID)
+	if err != nil {
+		if err.Error() == "tender not found" {
+			c.JSON(http.StatusNotFound, gin.H{"message": "Tender not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to check eligibility", "error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, eligibility)
+}
+
+// QualificationReviewQueueHandler godoc
+// @Summary List qualifications awaiting review
+// @Description Lists pending qualifications that match a requirement of one of the client's tenders
+// @Tags Qualification
+// @Produce json
+// @Success 200 {array} model.Qualification
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security Bearer
+// @Router /api/client/qualifications/review [get]
+func QualificationReviewQueueHandler(c *gin.Context) {
+	qualifications, err := qualificationService.ReviewQueue(c.GetInt("user_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to fetch review queue"})
+		return
+	}
+
+	c.JSON(http.StatusOK, qualifications)
+}
+
+// ApproveQualificationHandler godoc
+// @Summary Approve a qualification
+// @Description Approves a pending qualification so it counts towards tender requirements
+// @Tags Qualification
+// @Accept json
+// @Produce json
+// @Param id path int true "Qualification ID"
+// @Param review body model.ReviewQualification false "Optional note"
+// @Success 200 {object} map[string]string "Qualification approved"
+// @Failure 400 {object} map[string]string "Qualification already reviewed"
+// @Failure 404 {object} map[string]string "Qualification not found"
+// @Security Bearer
+// @Router /api/client/qualifications/{id}/approve [post]
+func ApproveQualificationHandler(c *gin.Context) {
+	reviewQualification(c, true)
+}
+
+// RejectQualificationHandler godoc
+// @Summary Reject a qualification
+// @Description Rejects a pending qualification. A reason is required and sent to the contractor
+// @Tags Qualification
+// @Accept json
+// @Produce json
+// @Param id path int true "Qualification ID"
+// @Param review body model.ReviewQualification true "Reason for the rejection"
+// @Success 200 {object} map[string]string "Qualification rejected"
+// @Failure 400 {object} map[string]string "Missing reason or qualification already reviewed"
+// @Failure 404 {object} map[string]string "Qualification not found"
+// @Security Bearer
+// @Router /api/client/qualifications/{id}/reject [post]
+func RejectQualificationHandler(c *gin.Context) {
+	reviewQualification(c, false)
+}
+
+func reviewQualification(c *gin.Context, approve bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid qualification ID"})
+		return
+	}
+
+	var payload model.ReviewQualification
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&payload); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid input", "error": err.Error()})
+			return
+		}
+	}
+
+	if err := qualificationService.ReviewQualification(c.GetInt("user_id"), id, approve, payload.Note); err != nil {
+		switch err.Error() {
+		case "qualification not found":
+			c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+		case "qualification already reviewed", "a reason is required to reject a qualification":
+			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to review qualification", "error": err.Error()})
+		}
+		return
+	}
+
+	message := "Qualification approved"
+	if !approve {
+		message = "Qualification rejected"
+	}
+	c.JSON(http.StatusOK, gin.H{"message": message})
+}
+
+// DownloadQualificationHandler godoc
+// @Summary Download a qualification document
+// @Description Streams the document to its owner or to a client who may review it
+// @Tags Qualification
+// @Produce octet-stream
+// @Param id path int true "Qualification ID"
+// @Success 200 {file} file
+// @Failure 400 {object} map[string]string "Invalid qualification ID"
+// @Failure 404 {object} map[string]string "Qualification not found"
+// @Security Bearer
+// @Router /api/qualifications/{id}/document [get]
+func DownloadQualificationHandler(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid qualification ID"})
+		return
+	}
+
+	q, file, err := qualificationService.OpenDocument(c.Request.Context(), c.GetInt("user_id"), c.GetString("role"), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+		return
+	}
+	defer file.Close()
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", q.FileName))
+	c.Header("X-Content-SHA256", q.SHA256)
+	c.DataFromReader(http.StatusOK, q.Size, q.MimeType, io.Reader(file), nil)
+}
@@ -0,0 +1,154 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+	"strings"
+	"tender-managment/internal/model"
+	"tender-managment/internal/service"
+)
+
+var (
+	reviewService *service.ReviewService
+)
+
+func SetReviewService(reviewSer *service.ReviewService) {
+	reviewService = reviewSer
+}
+
+// SubmitReviewHandler godoc
+// @Summary Review the other party of a contract
+// @Description Rates the counterparty of a completed contract from 1 to 5 on quality, timeliness and communication. Each party can review a contract once
+// @Tags Review
+// @Accept json
+// @Produce json
+// @Param id path int true "Contract ID"
+// @Param review body model.SubmitReview true "Scores and comment"
+// @Success 201 {object} model.Review
+// @Failure 400 {object} map[string]string "Invalid scores, contract not completed or already reviewed"
+// @Failure 404 {object} map[string]string "Contract not found"
+// @Security Bearer
+// @Router /api/contracts/{id}/reviews [post]
+func SubmitReviewHandler(c *gin.Context) {
+	contractID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid contract ID"})
+		return
+	}
+
+	var payload model.SubmitReview
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid input", "error": err.Error()})
+		return
+	}
+
+	review, err := reviewService.SubmitReview(c.GetInt("user_id"), contractID, payload)
+	if err != nil {
+		reviewError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, review)
+}
+
+// UpdateReviewHandler godoc
+// @Summary Edit a review
+// @Description Changes a review. Reviews can only be edited for 14 days after they were submitted
+// @Tags Review
+// @Accept json
+// @Produce json
+// @Param id path int true "Review ID"
+// @Param review body model.SubmitReview true "Scores and comment"
+// @Success 200 {object} model.Review
+// @Failure 400 {object} map[string]string "Invalid scores or edit window closed"
+// @Failure 404 {object} map[string]string "Review not found"
+// @Security Bearer
+// @Router /api/reviews/{id} [put]
+func UpdateReviewHandler(c *gin.Context) {
+	reviewID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid review ID"})
+		return
+	}
+
+	var payload model.SubmitReview
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid input", "error": err.Error()})
+		return
+	}
+
+	review, err := reviewService.UpdateReview(c.GetInt("user_id"), reviewID, payload)
+	if err != nil {
+		reviewError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, review)
+}
+
+// ListUserReviewsHandler godoc
+// @Summary List reviews of a user
+// @Description Lists the reviews a client or contractor has received, newest first
+// @Tags Review
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {array} model.Review
+// @Failure 400 {object} map[string]string "Invalid user ID"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security Bearer
+// @Router /api/users/{id}/reviews [get]
+func ListUserReviewsHandler(c *gin.Context) {
+	userID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid user ID"})
+		return
+	}
+
+	reviews, err := reviewService.ListReviews(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to fetch reviews"})
+		return
+	}
+
+	c.JSON(http.StatusOK, reviews)
+}
+
+// GetUserReputationHandler godoc
+// @Summary Get the reputation of a user
+// @Description Returns the number of reviews a user has received and their average scores
+// @Tags Review
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} model.Reputation
+// @Failure 400 {object} map[string]string "Invalid user ID"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security Bearer
+// @Router /api/users/{id}/reputation [get]
+func GetUserReputationHandler(c *gin.Context) {
+	userID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid user ID"})
+		return
+	}
+
+	reputation, err := reviewService.GetReputation(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to fetch reputation"})
+		return
+	}
+
+	c.JSON(http.StatusOK, reputation)
+}
+
+func reviewError(c *gin.Context, err error) {
+	switch {
+	case err.Error() == "contract not found", err.Error() == "review not found":
+		c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+	case err.Error() == "only completed contracts can be reviewed", err.Error() == "you have already reviewed this contract",
+		err.Error() == "the review can no longer be edited", strings.HasSuffix(err.Error(), "must be between 1 and 5"):
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to save review", "error": err.Error()})
+	}
+}
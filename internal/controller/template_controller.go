@@ -0,0 +1,228 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+	"tender-managment/internal/model"
+	"tender-managment/internal/service"
+	"time"
+)
+
+var (
+	templateService *service.TemplateService
+)
+
+func SetTemplateService(templateSer *service.TemplateService) {
+	templateService = templateSer
+}
+
+// SaveTenderTemplateHandler godoc
+// @Summary Save a tender as a template
+// @Description Stores the tender details and attachments as a reusable template
+// @Tags Template
+// @Accept json
+// @Produce json
+// @Param id path int true "Tender ID"
+// @Param template body model.CreateTemplate true "Template name"
+// @Success 201 {object} model.TenderTemplate
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 404 {object} map[string]string "Tender not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security Bearer
+// @Router /api/client/tenders/{id}/template [post]
+func SaveTenderTemplateHandler(c *gin.Context) {
+	tenderID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid tender ID"})
+		return
+	}
+
+	var payload model.CreateTemplate
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid input", "error": err.Error()})
+		return
+	}
+
+	template, err := templateService.SaveAsTemplate(c.GetInt("user_id"), tenderID, payload.Name)
+	if err != nil {
+		if err.Error() == "tender not found" {
+			c.JSON(http.StatusNotFound, gin.H{"message": "Tender not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to save template", "error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, template)
+}
+
+// ListTemplatesHandler godoc
+// @Summary List tender templates
+// @Description Lists the templates saved by the current client
+// @Tags Template
+// @Produce json
+// @Success 200 {array} model.TenderTemplate
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security Bearer
+// @Router /api/client/templates [get]
+func ListTemplatesHandler(c *gin.Context) {
+	templates, err := templateService.ListTemplates(c.GetInt("user_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to fetch templates"})
+		return
+	}
+
+	c.JSON(http.StatusOK, templates)
+}
+
+// DeleteTemplateHandler godoc
+// @Summary Delete a tender template
+// @Description Deletes a template owned by the current client
+// @Tags Template
+// @Param id path int true "Template ID"
+// @Success 200 {object} map[string]string "Template deleted successfully"
+// @Failure 400 {object} map[string]string "Invalid template ID"
+// @Failure 404 {object} map[string]string "Template not found"
+// @Security Bearer
+// @Router /api/client/templates/{id} [delete]
+func DeleteTemplateHandler(c *gin.Context) {
+	templateID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid template ID"})
+		return
+	}
+
+	if err := templateService.DeleteTemplate(c.Request.Context(), c.GetInt("user_id"), templateID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"message": "Template not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Template deleted successfully"})
+}
+
+// CreateTenderFromTemplateHandler godoc
+// @Summary Create a draft tender from a template
+// @Description Creates a new draft tender from a saved template
+// @Tags Template
+// @Accept json
+// @Produce json
+// @Param id path int true "Template ID"
+// @Param tender body model.CreateTenderFrom true "Deadline and optional overrides"
+// @Success 201 {object} model.Tender
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 404 {object} map[string]string "Template not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security Bearer
+// @Router /api/client/templates/{id}/tenders [post]
+func CreateTenderFromTemplateHandler(c *gin.Context) {
+	templateID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid template ID"})
+		return
+	}
+
+	overrides, ok := bindTenderOverrides(c)
+	if !ok {
+		return
+	}
+
+	clientID := c.GetInt("user_id")
+	tender, err := templateService.CreateTenderFromTemplate(clientID, templateID, overrides)
+	if err != nil {
+		switch err.Error() {
+		case "template not found":
+			c.JSON(http.StatusNotFound, gin.H{"message": "Template not found"})
+		case "publish time must be before the deadline":
+			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to create tender", "error": err.Error()})
+		}
+		return
+	}
+
+	invalidateTenderLists(c, clientID)
+
+	c.JSON(http.StatusCreated, tender)
+}
+
+// CloneTenderHandler godoc
+// @Summary Clone a tender
+// @Description Copies an existing tender and its attachments into a new draft
+// @Tags Template
+// @Accept json
+// @Produce json
+// @Param id path int true "Tender ID"
+// @Param tender body model.CreateTenderFrom true "Deadline and optional overrides"
+// @Success 201 {object} model.Tender
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 404 {object} map[string]string "Tender not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security Bearer
+// @Router /api/client/tenders/{id}/clone [post]
+func CloneTenderHandler(c *gin.Context) {
+	tenderID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid tender ID"})
+		return
+	}
+
+	overrides, ok := bindTenderOverrides(c)
+	if !ok {
+		return
+	}
+
+	clientID := c.GetInt("user_id")
+	tender, err := templateService.CloneTender(clientID, tenderID, overrides)
+	if err != nil {
+		switch err.Error() {
+		case "tender not found":
+			c.JSON(http.StatusNotFound, gin.H{"message": "Tender not found"})
+		case "publish time must be before the deadline":
+			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to clone tender", "error": err.Error()})
+		}
+		return
+	}
+
+	invalidateTenderLists(c, clientID)
+
+	c.JSON(http.StatusCreated, tender)
+}
+
+func bindTenderOverrides(c *gin.Context) (model.Tender, bool) {
+	var payload model.CreateTenderFrom
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid input", "error": err.Error()})
+		return model.Tender{}, false
+	}
+
+	if payload.Budget < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid tender data"})
+		return model.Tender{}, false
+	}
+
+	deadline, err := time.Parse(time.RFC3339, payload.Deadline)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid deadline format"})
+		return model.Tender{}, false
+	}
+
+	overrides := model.Tender{
+		Title:    payload.Title,
+		Deadline: deadline,
+		Budget:   model.Money{Amount: payload.Budget},
+	}
+
+	if payload.PublishAt != "" {
+		publishAt, err := time.Parse(time.RFC3339, payload.PublishAt)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid publish_at format"})
+			return model.Tender{}, false
+		}
+		overrides.PublishAt = &publishAt
+	}
+
+	return overrides, true
+}
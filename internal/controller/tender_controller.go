@@ -6,6 +6,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"net/http"
 	"strconv"
+	"strings"
 	"tender-managment/internal/db"
 	"tender-managment/internal/model"
 	"tender-managment/internal/service"
@@ -13,8 +14,9 @@ import (
 )
 
 const (
-	tenderListCacheKey = "tenders:client:%d"
-	cacheExpiration    = 5 * time.Minute
+	tenderListCacheKey       = "tenders:client:%d"
+	publicTenderListCacheKey = "tenders:client:%d:public"
+	cacheExpiration          = 5 * time.Minute
 )
 
 var (
@@ -29,7 +31,7 @@ func SetTenderService(tenderSer *service.TenderService, redis *db.Redis) {
 
 // CreateTenderHandler godoc
 // @Summary Create a new tender
-// @Description Creates a new tender with provided details
+// @Description Creates a new tender with provided details. Tenders created as drafts or with a publish time stay hidden until published. Invite-only tenders are visible to the invited contractors only. Two-stage tenders collect expressions of interest before priced bids
 // @Tags Tender
 // @Accept json
 // @Produce json
@@ -47,7 +49,7 @@ func CreateTenderHandler(c *gin.Context) {
 		return
 	}
 
-	if payload.Budget < 0 {
+	if payload.Budget.Amount <= 0 {
 		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid tender data"})
 		return
 	}
@@ -59,23 +61,43 @@ func CreateTenderHandler(c *gin.Context) {
 	}
 
 	tender := model.Tender{
-		Title:       payload.Title,
-		Description: payload.Description,
-		Deadline:    parsedTime,
-		Budget:      payload.Budget,
-		Attachment:  payload.Attachment,
-		ClientID:    c.GetInt("user_id"),
-		Status:      "open",
+		Title:              payload.Title,
+		Description:        payload.Description,
+		EvaluationCriteria: payload.EvaluationCriteria,
+		Deadline:           parsedTime,
+		Budget:             payload.Budget,
+		ClientID:           c.GetInt("user_id"),
+		Status:             model.TenderStatusOpen,
+		Visibility:         payload.Visibility,
+		Stage:              model.TenderStageRFP,
+	}
+	if payload.TwoStage {
+		tender.Stage = model.TenderStageEOI
+	}
+
+	if payload.PublishAt != "" {
+		publishAt, err := time.Parse(time.RFC3339, payload.PublishAt)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid publish_at format"})
+			return
+		}
+		tender.PublishAt = &publishAt
+	}
+	if payload.Draft || tender.PublishAt != nil {
+		tender.Status = model.TenderStatusDraft
 	}
 
-	createdTender, err := tenderService.CreateTender(&tender)
+	createdTender, err := tenderService.CreateTender(&tender, payload.InvitedIDs)
 	if err != nil {
+		if isTenderInputError(err) {
+			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to create tender", "error": err.Error()})
 		return
 	}
 
-	cacheKey := fmt.Sprintf(tenderListCacheKey, tender.ClientID)
-	_ = redisClient.Del(c.Request.Context(), cacheKey)
+	invalidateTenderLists(c, tender.ClientID)
 
 	c.JSON(http.StatusCreated, createdTender)
 }
@@ -102,7 +124,7 @@ func ListTendersHandler(c *gin.Context) {
 		}
 	}
 
-	tenders, err := tenderService.ListTenders(clientID)
+	tenders, err := tenderService.ListTenders(clientID, clientID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to fetch tenders"})
 		return
@@ -126,6 +148,7 @@ func ListTendersHandler(c *gin.Context) {
 // @Success 200 {object} map[string]string "Tender status updated successfully"
 // @Failure 400 {object} map[string]string "Invalid input or tender status"
 // @Failure 404 {object} map[string]string "Tender not found"
+// @Failure 409 {object} map[string]string "Awarded tenders cannot change status"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Security Bearer
 // @Router /api/client/tenders/{id} [put]
@@ -149,14 +172,17 @@ func UpdateTenderStatusHandler(c *gin.Context) {
 			c.JSON(http.StatusNotFound, gin.H{"message": "Tender not found"})
 		case "invalid status":
 			c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid tender status"})
+		case "only draft tenders can be published":
+			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		case "awarded tenders cannot change status":
+			c.JSON(http.StatusConflict, gin.H{"message": err.Error()})
 		default:
 			c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to update tender", "error": err.Error()})
 		}
 		return
 	}
 
-	cacheKey := fmt.Sprintf(tenderListCacheKey, clientID)
-	_ = redisClient.Del(c.Request.Context(), cacheKey)
+	invalidateTenderLists(c, clientID)
 
 	c.JSON(http.StatusOK, gin.H{"message": "Tender status updated successfully"})
 }
@@ -189,8 +215,7 @@ func DeleteTenderHandler(c *gin.Context) {
 		return
 	}
 
-	cacheKey := fmt.Sprintf(tenderListCacheKey, clientID)
-	_ = redisClient.Del(c.Request.Context(), cacheKey)
+	invalidateTenderLists(c, clientID)
 
 	c.JSON(http.StatusOK, gin.H{"message": "Tender deleted successfully"})
 }
@@ -214,7 +239,10 @@ func GetClientTenderHistory(ctx *gin.Context) {
 		return
 	}
 
-	cacheKey := fmt.Sprintf(tenderListCacheKey, clientID)
+	cacheKey := fmt.Sprintf(publicTenderListCacheKey, clientID)
+	if ctx.GetInt("user_id") == clientID {
+		cacheKey = fmt.Sprintf(tenderListCacheKey, clientID)
+	}
 	cachedData, err := redisClient.Get(ctx.Request.Context(), cacheKey)
 	if err == nil && cachedData != "" {
 		var tenders []model.Tender
@@ -224,7 +252,7 @@ func GetClientTenderHistory(ctx *gin.Context) {
 		}
 	}
 
-	tenders, err := tenderService.ListTenders(clientID)
+	tenders, err := tenderService.ListTenders(ctx.GetInt("user_id"), clientID)
 	if err != nil {
 		if err.Error() == "no tenders found" {
 			ctx.JSON(http.StatusNotFound, gin.H{"message": "No tenders found for this client"})
@@ -240,3 +268,578 @@ func GetClientTenderHistory(ctx *gin.Context) {
 
 	ctx.JSON(http.StatusOK, tenders)
 }
+
+// UpdateDraftTenderHandler godoc
+// @Summary Edit a draft tender
+// @Description Replaces the details of a tender that has not been published yet
+// @Tags Tender
+// @Accept json
+// @Produce json
+// @Param id path int true "Tender ID"
+// @Param tender body model.UpdateTender true "Tender details"
+// @Success 200 {object} map[string]string "Tender updated successfully"
+// @Failure 400 {object} map[string]string "Invalid input or tender is not a draft"
+// @Failure 404 {object} map[string]string "Tender not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security Bearer
+// @Router /api/client/tenders/{id}/draft [put]
+func UpdateDraftTenderHandler(c *gin.Context) {
+	tenderID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid tender ID"})
+		return
+	}
+
+	var payload model.UpdateTender
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid input", "error": err.Error()})
+		return
+	}
+
+	if payload.Budget.Amount <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid tender data"})
+		return
+	}
+
+	deadline, err := time.Parse(time.RFC3339, payload.Deadline)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid deadline format"})
+		return
+	}
+
+	clientID := c.GetInt("user_id")
+	tender := model.Tender{
+		ID:                 tenderID,
+		ClientID:           clientID,
+		Title:              payload.Title,
+		Description:        payload.Description,
+		EvaluationCriteria: payload.EvaluationCriteria,
+		Deadline:           deadline,
+		Budget:             payload.Budget,
+		Visibility:         payload.Visibility,
+	}
+
+	if payload.PublishAt != "" {
+		publishAt, err := time.Parse(time.RFC3339, payload.PublishAt)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid publish_at format"})
+			return
+		}
+		tender.PublishAt = &publishAt
+	}
+
+	if err := tenderService.UpdateDraftTender(clientID, &tender); err != nil {
+		switch err.Error() {
+		case "tender not found":
+			c.JSON(http.StatusNotFound, gin.H{"message": "Tender not found"})
+		case "only draft tenders can be edited", "publish time must be before the deadline", "invalid currency", "invalid visibility":
+			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to update tender", "error": err.Error()})
+		}
+		return
+	}
+
+	invalidateTenderLists(c, clientID)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Tender updated successfully"})
+}
+
+// PublishTenderHandler godoc
+// @Summary Publish a draft tender
+// @Description Opens a draft tender for bidding immediately and notifies contractors
+// @Tags Tender
+// @Produce json
+// @Param id path int true "Tender ID"
+// @Success 200 {object} map[string]string "Tender published successfully"
+// @Failure 400 {object} map[string]string "Tender is not a draft"
+// @Failure 404 {object} map[string]string "Tender not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security Bearer
+// @Router /api/client/tenders/{id}/publish [post]
+func PublishTenderHandler(c *gin.Context) {
+	tenderID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid tender ID"})
+		return
+	}
+
+	clientID := c.GetInt("user_id")
+	if err := tenderService.PublishTender(clientID, tenderID); err != nil {
+		switch err.Error() {
+		case "tender not found":
+			c.JSON(http.StatusNotFound, gin.H{"message": "Tender not found"})
+		case "only draft tenders can be published":
+			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to publish tender", "error": err.Error()})
+		}
+		return
+	}
+
+	invalidateTenderLists(c, clientID)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Tender published successfully"})
+}
+
+// CreateLotHandler godoc
+// @Summary Add a lot to a tender
+// @Description Splits a tender into lots, each with its own budget. Lots can only be changed before bids arrive
+// @Tags Tender
+// @Accept json
+// @Produce json
+// @Param id path int true "Tender ID"
+// @Param lot body model.CreateLot true "Lot details"
+// @Success 201 {object} model.Lot
+// @Failure 400 {object} map[string]string "Invalid input or lots are locked"
+// @Failure 404 {object} map[string]string "Tender not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security Bearer
+// @Router /api/client/tenders/{id}/lots [post]
+func CreateLotHandler(c *gin.Context) {
+	tenderID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid tender ID"})
+		return
+	}
+
+	var payload model.CreateLot
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid input", "error": err.Error()})
+		return
+	}
+
+	lot := model.Lot{
+		TenderID:    tenderID,
+		LotNumber:   payload.LotNumber,
+		Title:       payload.Title,
+		Description: payload.Description,
+		Budget:      payload.Budget,
+	}
+
+	createdLot, err := tenderService.AddLot(c.GetInt("user_id"), &lot)
+	if err != nil {
+		switch err.Error() {
+		case "tender not found":
+			c.JSON(http.StatusNotFound, gin.H{"message": "Tender not found"})
+		case "invalid lot data", "tender structure can only be changed before bids are submitted":
+			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to create lot", "error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, createdLot)
+}
+
+// ListLotsHandler godoc
+// @Summary List the lots of a tender
+// @Description Retrieves the lots a tender is split into
+// @Tags Tender
+// @Produce json
+// @Param id path int true "Tender ID"
+// @Success 200 {array} model.Lot
+// @Failure 400 {object} map[string]string "Invalid tender ID"
+// @Failure 404 {object} map[string]string "Tender not found"
+// @Security Bearer
+// @Router /api/client/tenders/{id}/lots [get]
+func ListLotsHandler(c *gin.Context) {
+	tenderID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid tender ID"})
+		return
+	}
+
+	lots, err := tenderService.ListLots(c.GetInt("user_id"), tenderID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"message": "Tender not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, lots)
+}
+
+// DeleteLotHandler godoc
+// @Summary Remove a lot from a tender
+// @Description Deletes a lot before any bids have been submitted
+// @Tags Tender
+// @Param id path int true "Tender ID"
+// @Param lotId path int true "Lot ID"
+// @Success 200 {object} map[string]string "Lot deleted successfully"
+// @Failure 400 {object} map[string]string "Invalid ID or lots are locked"
+// @Failure 404 {object} map[string]string "Tender or lot not found"
+// @Security Bearer
+// @Router /api/client/tenders/{id}/lots/{lotId} [delete]
+func DeleteLotHandler(c *gin.Context) {
+	tenderID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid tender ID"})
+		return
+	}
+
+	lotID, err := strconv.Atoi(c.Param("lotId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid lot ID"})
+		return
+	}
+
+	if err := tenderService.DeleteLot(c.GetInt("user_id"), tenderID, lotID); err != nil {
+		switch err.Error() {
+		case "tender not found", "lot not found":
+			c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+		case "tender structure can only be changed before bids are submitted":
+			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to delete lot", "error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Lot deleted successfully"})
+}
+
+// SetBoQHandler godoc
+// @Summary Set the bill of quantities of a tender
+// @Description Replaces the tender's bill of quantities. It can only be changed before bids arrive
+// @Tags Tender
+// @Accept json
+// @Produce json
+// @Param id path int true "Tender ID"
+// @Param items body []model.CreateBoQItem true "Bill of quantities lines"
+// @Success 200 {array} model.BoQItem
+// @Failure 400 {object} map[string]string "Invalid input or structure is locked"
+// @Failure 404 {object} map[string]string "Tender not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security Bearer
+// @Router /api/client/tenders/{id}/boq [put]
+func SetBoQHandler(c *gin.Context) {
+	tenderID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid tender ID"})
+		return
+	}
+
+	var payload []model.CreateBoQItem
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid input", "error": err.Error()})
+		return
+	}
+
+	items := make([]model.BoQItem, 0, len(payload))
+	for _, item := range payload {
+		items = append(items, model.BoQItem{
+			LotID:       item.LotID,
+			ItemNumber:  item.ItemNumber,
+			Description: item.Description,
+			Unit:        item.Unit,
+			Quantity:    item.Quantity,
+		})
+	}
+
+	saved, err := tenderService.SetBoQ(c.GetInt("user_id"), tenderID, items)
+	if err != nil {
+		if err.Error() == "tender not found" {
+			c.JSON(http.StatusNotFound, gin.H{"message": "Tender not found"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, saved)
+}
+
+// GetBoQHandler godoc
+// @Summary Get the bill of quantities of a tender
+// @Description Retrieves the lines contractors have to price when bidding
+// @Tags Tender
+// @Produce json
+// @Param id path int true "Tender ID"
+// @Success 200 {array} model.BoQItem
+// @Failure 400 {object} map[string]string "Invalid tender ID"
+// @Failure 404 {object} map[string]string "Tender not found"
+// @Security Bearer
+// @Router /api/client/tenders/{id}/boq [get]
+func GetBoQHandler(c *gin.Context) {
+	tenderID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid tender ID"})
+		return
+	}
+
+	items, err := tenderService.GetBoQ(c.GetInt("user_id"), tenderID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"message": "Tender not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, items)
+}
+
+// ShortlistHandler godoc
+// @Summary Shortlist expressions of interest
+// @Description Shortlists contractors from the first stage of a two-stage tender
+// @Tags Tender
+// @Accept json
+// @Produce json
+// @Param id path int true "Tender ID"
+// @Param shortlist body model.ShortlistRequest true "Expressions of interest to shortlist"
+// @Success 200 {object} map[string]string "Contractors shortlisted"
+// @Failure 400 {object} map[string]string "Invalid input or tender is not in the EOI stage"
+// @Failure 404 {object} map[string]string "Tender not found"
+// @Security Bearer
+// @Router /api/client/tenders/{id}/shortlist [post]
+func ShortlistHandler(c *gin.Context) {
+	tenderID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid tender ID"})
+		return
+	}
+
+	var payload model.ShortlistRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid input", "error": err.Error()})
+		return
+	}
+
+	if err := tenderService.Shortlist(c.GetInt("user_id"), tenderID, payload.BidIDs); err != nil {
+		if err.Error() == "tender not found" {
+			c.JSON(http.StatusNotFound, gin.H{"message": "Tender not found"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	_ = redisClient.Del(c.Request.Context(), fmt.Sprintf(bidsByTenderKey, tenderID))
+
+	c.JSON(http.StatusOK, gin.H{"message": "Contractors shortlisted"})
+}
+
+// OpenRFPHandler godoc
+// @Summary Open the RFP stage of a two-stage tender
+// @Description Closes the expression-of-interest stage and opens priced bidding to shortlisted contractors until the new deadline
+// @Tags Tender
+// @Accept json
+// @Produce json
+// @Param id path int true "Tender ID"
+// @Param rfp body model.OpenRFPRequest true "Deadline of the RFP stage"
+// @Success 200 {object} map[string]string "RFP stage opened"
+// @Failure 400 {object} map[string]string "Invalid input, empty shortlist or wrong stage"
+// @Failure 404 {object} map[string]string "Tender not found"
+// @Security Bearer
+// @Router /api/client/tenders/{id}/rfp [post]
+func OpenRFPHandler(c *gin.Context) {
+	tenderID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid tender ID"})
+		return
+	}
+
+	var payload model.OpenRFPRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid input", "error": err.Error()})
+		return
+	}
+
+	deadline, err := time.Parse(time.RFC3339, payload.Deadline)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid deadline format"})
+		return
+	}
+
+	clientID := c.GetInt("user_id")
+	if err := tenderService.OpenRFP(clientID, tenderID, deadline); err != nil {
+		switch err.Error() {
+		case "tender not found":
+			c.JSON(http.StatusNotFound, gin.H{"message": "Tender not found"})
+		case "tender is not collecting expressions of interest", "deadline must be in the future", "shortlist at least one contractor first":
+			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to open RFP stage", "error": err.Error()})
+		}
+		return
+	}
+
+	_ = redisClient.Del(c.Request.Context(), fmt.Sprintf(bidsByTenderKey, tenderID))
+	invalidateTenderLists(c, clientID)
+
+	c.JSON(http.StatusOK, gin.H{"message": "RFP stage opened"})
+}
+
+// SetRequirementsHandler godoc
+// @Summary Set the qualification requirements of a tender
+// @Description Replaces the licenses, certifications and minimum turnover contractors need to bid. Requirements can only be changed before bids arrive
+// @Tags Tender
+// @Accept json
+// @Produce json
+// @Param id path int true "Tender ID"
+// @Param requirements body []model.Requirement true "Requirements"
+// @Success 200 {array} model.Requirement
+// @Failure 400 {object} map[string]string "Invalid input or structure is locked"
+// @Failure 404 {object} map[string]string "Tender not found"
+// @Security Bearer
+// @Router /api/client/tenders/{id}/requirements [put]
+func SetRequirementsHandler(c *gin.Context) {
+	tenderID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid tender ID"})
+		return
+	}
+
+	var payload []model.Requirement
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid input", "error": err.Error()})
+		return
+	}
+
+	saved, err := tenderService.SetRequirements(c.GetInt("user_id"), tenderID, payload)
+	if err != nil {
+		if err.Error() == "tender not found" {
+			c.JSON(http.StatusNotFound, gin.H{"message": "Tender not found"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, saved)
+}
+
+// GetRequirementsHandler godoc
+// @Summary Get the qualification requirements of a tender
+// @Description Retrieves the qualifications a contractor needs to bid on the tender
+// @Tags Tender
+// @Produce json
+// @Param id path int true "Tender ID"
+// @Success 200 {array} model.Requirement
+// @Failure 400 {object} map[string]string "Invalid tender ID"
+// @Failure 404 {object} map[string]string "Tender not found"
+// @Security Bearer
+// @Router /api/client/tenders/{id}/requirements [get]
+func GetRequirementsHandler(c *gin.Context) {
+	tenderID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid tender ID"})
+		return
+	}
+
+	requirements, err := tenderService.GetRequirements(c.GetInt("user_id"), tenderID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"message": "Tender not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, requirements)
+}
+
+// InviteContractorsHandler godoc
+// @Summary Invite contractors to a tender
+// @Description Invites contractors to an invite-only tender. Invitees of an open tender are notified immediately, those of a draft when it is published
+// @Tags Tender
+// @Accept json
+// @Produce json
+// @Param id path int true "Tender ID"
+// @Param invitation body model.InviteContractors true "Contractors to invite"
+// @Success 200 {array} model.TenderInvitation
+// @Failure 400 {object} map[string]string "Invalid input or tender is not invite-only"
+// @Failure 404 {object} map[string]string "Tender not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security Bearer
+// @Router /api/client/tenders/{id}/invitations [post]
+func InviteContractorsHandler(c *gin.Context) {
+	tenderID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid tender ID"})
+		return
+	}
+
+	var payload model.InviteContractors
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid input", "error": err.Error()})
+		return
+	}
+
+	invitations, err := tenderService.InviteContractors(c.GetInt("user_id"), tenderID, payload.ContractorIDs)
+	if err != nil {
+		switch {
+		case err.Error() == "tender not found":
+			c.JSON(http.StatusNotFound, gin.H{"message": "Tender not found"})
+		case isTenderInputError(err):
+			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to invite contractors", "error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, invitations)
+}
+
+// ListInvitationsHandler godoc
+// @Summary List the invitations of a tender
+// @Description Retrieves the contractors invited to an invite-only tender
+// @Tags Tender
+// @Produce json
+// @Param id path int true "Tender ID"
+// @Success 200 {array} model.TenderInvitation
+// @Failure 400 {object} map[string]string "Invalid tender ID"
+// @Failure 404 {object} map[string]string "Tender not found"
+// @Security Bearer
+// @Router /api/client/tenders/{id}/invitations [get]
+func ListInvitationsHandler(c *gin.Context) {
+	tenderID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid tender ID"})
+		return
+	}
+
+	invitations, err := tenderService.ListInvitations(c.GetInt("user_id"), tenderID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"message": "Tender not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, invitations)
+}
+
+// ListOpenTendersHandler godoc
+// @Summary List tenders open for bidding
+// @Description Retrieves the open tenders the contractor can bid on: all public tenders and the invite-only tenders they were invited to
+// @Tags Tender
+// @Produce json
+// @Success 200 {array} model.Tender
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security Bearer
+// @Router /api/contractor/tenders [get]
+func ListOpenTendersHandler(c *gin.Context) {
+	tenders, err := tenderService.ListMarketplace(c.GetInt("user_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to fetch tenders"})
+		return
+	}
+
+	c.JSON(http.StatusOK, tenders)
+}
+
+func isTenderInputError(err error) bool {
+	switch err.Error() {
+	case "publish time must be before the deadline", "invalid currency", "invalid visibility",
+		"only invite-only tenders accept invitations", "invitations can only be sent before the tender closes",
+		"no contractors to invite":
+		return true
+	}
+	return strings.HasSuffix(err.Error(), "is not a contractor")
+}
+
+func invalidateTenderLists(c *gin.Context, clientID int) {
+	ids, err := userService.ColleagueIDs(clientID)
+	if err != nil {
+		ids = []int{clientID}
+	}
+	for _, id := range ids {
+		_ = redisClient.Del(c.Request.Context(), fmt.Sprintf(tenderListCacheKey, id))
+		_ = redisClient.Del(c.Request.Context(), fmt.Sprintf(publicTenderListCacheKey, id))
+	}
+}
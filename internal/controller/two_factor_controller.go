@@ -0,0 +1,146 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"tender-managment/internal/model"
+	"tender-managment/internal/service"
+)
+
+var (
+	twoFactorService *service.TwoFactorService
+)
+
+func SetTwoFactorService(twoFactorSer *service.TwoFactorService) {
+	twoFactorService = twoFactorSer
+}
+
+// GetTwoFactorStatusHandler godoc
+// @Summary Two-factor authentication status
+// @Description Shows whether two-factor authentication is on and how many recovery codes are left
+// @Tags TwoFactor
+// @Produce json
+// @Success 200 {object} model.TwoFactorStatus
+// @Failure 404 {object} map[string]string "User not found"
+// @Security Bearer
+// @Router /api/users/me/2fa [get]
+func GetTwoFactorStatusHandler(c *gin.Context) {
+	status, err := twoFactorService.Status(c.GetInt("user_id"))
+	if err != nil {
+		twoFactorError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
+// SetupTwoFactorHandler godoc
+// @Summary Start two-factor enrollment
+// @Description Creates a TOTP secret and returns it with an otpauth:// provisioning URI to show as a QR code. Two-factor authentication is switched on by confirming a code
+// @Tags TwoFactor
+// @Produce json
+// @Success 200 {object} model.TwoFactorSetup
+// @Failure 400 {object} map[string]string "Already enabled"
+// @Security Bearer
+// @Router /api/users/me/2fa/setup [post]
+func SetupTwoFactorHandler(c *gin.Context) {
+	setup, err := twoFactorService.Setup(c.GetInt("user_id"))
+	if err != nil {
+		twoFactorError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, setup)
+}
+
+// EnableTwoFactorHandler godoc
+// @Summary Enable two-factor authentication
+// @Description Confirms enrollment with a code from the authenticator app and returns recovery codes. They are shown only once
+// @Tags TwoFactor
+// @Accept json
+// @Produce json
+// @Param payload body model.TwoFactorCodeModel true "Code from the authenticator app"
+// @Success 200 {object} model.RecoveryCodes
+// @Failure 400 {object} map[string]string "Invalid code or not set up"
+// @Security Bearer
+// @Router /api/users/me/2fa/enable [post]
+func EnableTwoFactorHandler(c *gin.Context) {
+	var payload model.TwoFactorCodeModel
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid input", "error": err.Error()})
+		return
+	}
+
+	codes, err := twoFactorService.Enable(c.GetInt("user_id"), payload.Code)
+	if err != nil {
+		twoFactorError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, codes)
+}
+
+// DisableTwoFactorHandler godoc
+// @Summary Disable two-factor authentication
+// @Description Switches two-factor authentication off after checking a current code or a recovery code
+// @Tags TwoFactor
+// @Accept json
+// @Produce json
+// @Param payload body model.TwoFactorCodeModel true "Code from the authenticator app or a recovery code"
+// @Success 200 {object} map[string]string "Two-factor authentication disabled"
+// @Failure 400 {object} map[string]string "Invalid code or not enabled"
+// @Security Bearer
+// @Router /api/users/me/2fa/disable [post]
+func DisableTwoFactorHandler(c *gin.Context) {
+	var payload model.TwoFactorCodeModel
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid input", "error": err.Error()})
+		return
+	}
+
+	if err := twoFactorService.Disable(c.GetInt("user_id"), payload.Code); err != nil {
+		twoFactorError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Two-factor authentication disabled"})
+}
+
+// RegenerateRecoveryCodesHandler godoc
+// @Summary Replace recovery codes
+// @Description Issues new recovery codes after checking a current code. The old codes stop working
+// @Tags TwoFactor
+// @Accept json
+// @Produce json
+// @Param payload body model.TwoFactorCodeModel true "Code from the authenticator app or a recovery code"
+// @Success 200 {object} model.RecoveryCodes
+// @Failure 400 {object} map[string]string "Invalid code or not enabled"
+// @Security Bearer
+// @Router /api/users/me/2fa/recovery-codes [post]
+func RegenerateRecoveryCodesHandler(c *gin.Context) {
+	var payload model.TwoFactorCodeModel
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid input", "error": err.Error()})
+		return
+	}
+
+	codes, err := twoFactorService.RegenerateRecoveryCodes(c.GetInt("user_id"), payload.Code)
+	if err != nil {
+		twoFactorError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, codes)
+}
+
+func twoFactorError(c *gin.Context, err error) {
+	switch err.Error() {
+	case "user not found":
+		c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+	case "invalid code", "two-factor authentication is already enabled",
+		"two-factor authentication is not set up", "two-factor authentication is not enabled":
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Two-factor authentication request failed", "error": err.Error()})
+	}
+}
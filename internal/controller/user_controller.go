@@ -1,6 +1,12 @@
 package controller
 
-import "tender-managment/internal/service"
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+	"tender-managment/internal/model"
+	"tender-managment/internal/service"
+)
 
 var (
 	userService *service.UserService
@@ -9,3 +15,77 @@ var (
 func SetUserService(userSer *service.UserService) {
 	userService = userSer
 }
+
+// GetMyProfileHandler godoc
+// @Summary Get own profile
+// @Description Returns the profile of the signed-in user, including their organization
+// @Tags User
+// @Produce json
+// @Success 200 {object} model.UserProfile
+// @Failure 404 {object} map[string]string "User not found"
+// @Security Bearer
+// @Router /api/users/me [get]
+func GetMyProfileHandler(c *gin.Context) {
+	profile, err := userService.GetProfile(c.GetInt("user_id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"message": "User not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, profile)
+}
+
+// UpdateMyProfileHandler godoc
+// @Summary Update own profile
+// @Description Updates the signed-in user's name, phone number and job title
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param profile body model.UpdateProfile true "Profile details"
+// @Success 200 {object} model.UserProfile
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security Bearer
+// @Router /api/users/me [put]
+func UpdateMyProfileHandler(c *gin.Context) {
+	var payload model.UpdateProfile
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid input", "error": err.Error()})
+		return
+	}
+
+	profile, err := userService.UpdateProfile(c.GetInt("user_id"), payload)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to update profile", "error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, profile)
+}
+
+// GetUserProfileHandler godoc
+// @Summary Get a user's profile
+// @Description Returns the public profile of a client or contractor
+// @Tags User
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} model.UserProfile
+// @Failure 400 {object} map[string]string "Invalid user ID"
+// @Failure 404 {object} map[string]string "User not found"
+// @Security Bearer
+// @Router /api/users/{id} [get]
+func GetUserProfileHandler(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid user ID"})
+		return
+	}
+
+	profile, err := userService.GetProfile(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"message": "User not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, profile)
+}
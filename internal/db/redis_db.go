@@ -57,3 +57,31 @@ func (r *Redis) Del(ctx context.Context, key string) error {
 	}
 	return nil
 }
+
+// Incr increments the counter at key and returns the new value. A new
+// counter expires after expiration.
+func (r *Redis) Incr(ctx context.Context, key string, expiration time.Duration) (int64, error) {
+	val, err := r.client.Incr(ctx, key).Result()
+	if err != nil {
+		return 0, err
+	}
+	if val == 1 {
+		if err := r.client.Expire(ctx, key, expiration).Err(); err != nil {
+			return 0, err
+		}
+	}
+	return val, nil
+}
+
+// TTL returns how long the key has left to live, or zero if it does not
+// exist.
+func (r *Redis) TTL(ctx context.Context, key string) (time.Duration, error) {
+	ttl, err := r.client.TTL(ctx, key).Result()
+	if err != nil {
+		return 0, err
+	}
+	if ttl < 0 {
+		return 0, nil
+	}
+	return ttl, nil
+}
@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"tender-managment/internal/model"
+)
+
+type AdminRepository struct {
+	db *sql.DB
+}
+
+func NewAdminRepository(db *sql.DB) *AdminRepository {
+	return &AdminRepository{db: db}
+}
+
+func (r *AdminRepository) RecordAction(action *model.AdminAction) error {
+	query := `
+        INSERT INTO admin_actions (admin_id, action, target_type, target_id, details)
+        VALUES ($1, $2, $3, $4, $5)
+        RETURNING id, created_at`
+
+	err := r.db.QueryRow(query, action.AdminID, action.Action, action.TargetType, action.TargetID, action.Details).
+		Scan(&action.ID, &action.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to record admin action: %w", err)
+	}
+	return nil
+}
+
+// ListActions returns the audit trail, newest first.
+func (r *AdminRepository) ListActions(limit, offset int) ([]model.AdminAction, error) {
+	rows, err := r.db.Query(`
+        SELECT id, admin_id, action, target_type, target_id, details, created_at
+        FROM admin_actions
+        ORDER BY id DESC
+        LIMIT $1 OFFSET $2`, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch admin actions: %w", err)
+	}
+	defer rows.Close()
+
+	actions := []model.AdminAction{}
+	for rows.Next() {
+		var a model.AdminAction
+		if err := rows.Scan(&a.ID, &a.AdminID, &a.Action, &a.TargetType, &a.TargetID, &a.Details, &a.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan admin action: %w", err)
+		}
+		actions = append(actions, a)
+	}
+
+	return actions, rows.Err()
+}
+
+func (r *AdminRepository) GetStats() (*model.PlatformStats, error) {
+	stats := &model.PlatformStats{}
+	var err error
+
+	if stats.Users, err = r.countBy(`SELECT role, COUNT(*) FROM users GROUP BY role`); err != nil {
+		return nil, err
+	}
+	if stats.Tenders, err = r.countBy(`SELECT status, COUNT(*) FROM tenders GROUP BY status`); err != nil {
+		return nil, err
+	}
+	if stats.Bids, err = r.countBy(`SELECT status, COUNT(*) FROM bids WHERE type = 'bid' GROUP BY status`); err != nil {
+		return nil, err
+	}
+	if stats.Contracts, err = r.countBy(`SELECT status, COUNT(*) FROM contracts GROUP BY status`); err != nil {
+		return nil, err
+	}
+	if stats.Invoices, err = r.countBy(`SELECT status, COUNT(*) FROM invoices GROUP BY status`); err != nil {
+		return nil, err
+	}
+
+	err = r.db.QueryRow(`
+        SELECT (SELECT COUNT(*) FROM users WHERE suspended_at IS NOT NULL),
+               (SELECT COUNT(*) FROM organizations)`).Scan(&stats.SuspendedUsers, &stats.Organizations)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute statistics: %w", err)
+	}
+
+	return stats, nil
+}
+
+// countBy runs a query returning (key, count) rows and collects them.
+func (r *AdminRepository) countBy(query string) (map[string]int, error) {
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute statistics: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var key string
+		var count int
+		if err := rows.Scan(&key, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan statistics: %w", err)
+		}
+		counts[key] = count
+	}
+
+	return counts, rows.Err()
+}
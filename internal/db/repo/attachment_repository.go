@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"tender-managment/internal/model"
+)
+
+type AttachmentRepository struct {
+	db *sql.DB
+}
+
+func NewAttachmentRepository(db *sql.DB) *AttachmentRepository {
+	return &AttachmentRepository{db: db}
+}
+
+func (r *AttachmentRepository) CreateAttachment(attachment *model.Attachment) (*model.Attachment, error) {
+	query := `
+		INSERT INTO attachments (tender_id, bid_id, uploaded_by, file_name, storage_key, mime_type, size, sha256)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, created_at`
+
+	err := r.db.QueryRow(
+		query,
+		attachment.TenderID,
+		attachment.BidID,
+		attachment.UploadedBy,
+		attachment.FileName,
+		attachment.StorageKey,
+		attachment.MimeType,
+		attachment.Size,
+		attachment.SHA256,
+	).Scan(&attachment.ID, &attachment.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create attachment: %w", err)
+	}
+
+	return attachment, nil
+}
+
+func (r *AttachmentRepository) GetAttachmentByID(id int) (*model.Attachment, error) {
+	query := `
+		SELECT id, tender_id, bid_id, uploaded_by, file_name, storage_key, mime_type, size, sha256, created_at
+		FROM attachments
+		WHERE id = $1`
+
+	var attachment model.Attachment
+	err := r.db.QueryRow(query, id).Scan(
+		&attachment.ID,
+		&attachment.TenderID,
+		&attachment.BidID,
+		&attachment.UploadedBy,
+		&attachment.FileName,
+		&attachment.StorageKey,
+		&attachment.MimeType,
+		&attachment.Size,
+		&attachment.SHA256,
+		&attachment.CreatedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("attachment not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &attachment, nil
+}
+
+func (r *AttachmentRepository) GetAttachmentsByTenderID(tenderID int) ([]model.Attachment, error) {
+	query := `
+		SELECT id, tender_id, bid_id, uploaded_by, file_name, storage_key, mime_type, size, sha256, created_at
+		FROM attachments
+		WHERE tender_id = $1 AND bid_id IS NULL
+		ORDER BY created_at`
+
+	return r.queryAttachments(query, tenderID)
+}
+
+func (r *AttachmentRepository) GetAttachmentsByBidID(bidID int) ([]model.Attachment, error) {
+	query := `
+		SELECT id, tender_id, bid_id, uploaded_by, file_name, storage_key, mime_type, size, sha256, created_at
+		FROM attachments
+		WHERE bid_id = $1
+		ORDER BY created_at`
+
+	return r.queryAttachments(query, bidID)
+}
+
+func (r *AttachmentRepository) queryAttachments(query string, args ...interface{}) ([]model.Attachment, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch attachments: %w", err)
+	}
+	defer rows.Close()
+
+	var attachments []model.Attachment
+	for rows.Next() {
+		var attachment model.Attachment
+		if err := rows.Scan(
+			&attachment.ID,
+			&attachment.TenderID,
+			&attachment.BidID,
+			&attachment.UploadedBy,
+			&attachment.FileName,
+			&attachment.StorageKey,
+			&attachment.MimeType,
+			&attachment.Size,
+			&attachment.SHA256,
+			&attachment.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan attachment: %w", err)
+		}
+		attachments = append(attachments, attachment)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error reading rows: %w", err)
+	}
+
+	return attachments, nil
+}
+
+func (r *AttachmentRepository) DeleteAttachment(id int) error {
+	result, err := r.db.Exec(`DELETE FROM attachments WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return errors.New("attachment not found")
+	}
+
+	return nil
+}
+
+// CountByStorageKey reports how many attachment rows still point at a stored
+// file, so the blob is only removed once nothing references it.
+func (r *AttachmentRepository) CountByStorageKey(key string) (int, error) {
+	var count int
+	query := `
+		SELECT (SELECT count(*) FROM attachments WHERE storage_key = $1) +
+		       (SELECT count(*) FROM tender_template_attachments WHERE storage_key = $1)`
+	err := r.db.QueryRow(query, key).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count attachments: %w", err)
+	}
+	return count, nil
+}
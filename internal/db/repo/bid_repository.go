@@ -16,9 +16,9 @@ func NewBidRepository(db *sql.DB) *BidRepository {
 }
 
 func (r *BidRepository) UpdateBidStatus(bid *model.Bid) error {
-	query := `UPDATE bids SET status = $1 WHERE id = $2 AND contractor_id = $3 RETURNING id, contractor_id, tender_id, price, delivery_time, comments, status`
+	query := `UPDATE bids SET status = $1 WHERE id = $2 AND contractor_id = $3 RETURNING id, contractor_id, tender_id, price, currency, delivery_time, comments, type, status`
 
-	err := r.db.QueryRow(query, bid.Status, bid.ID, bid.ContractorID).Scan(&bid.ID, &bid.ContractorID, &bid.TenderID, &bid.Price, &bid.DeliveryTime, &bid.Comments, &bid.Status)
+	err := r.db.QueryRow(query, bid.Status, bid.ID, bid.ContractorID).Scan(&bid.ID, &bid.ContractorID, &bid.TenderID, &bid.Price.Amount, &bid.Price.Currency, &bid.DeliveryTime, &bid.Comments, &bid.Type, &bid.Status)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return fmt.Errorf("bid not found or you do not have access to this bid")
@@ -39,8 +39,8 @@ func (r *BidRepository) GetBidsByContractorID(contractorID int) ([]model.Bid, er
 	if role != "contractor" {
 		return nil, errors.New("bid history created by contractor")
 	}
-	query := `SELECT id, contractor_id, tender_id, price, delivery_time, comments, created_at ,status
-			  FROM bids WHERE contractor_id = $1`
+	query := `SELECT id, contractor_id, COALESCE(org_id, 0), tender_id, price, currency, delivery_time, comments, created_at, type, status
+			  FROM bids WHERE ` + ownedByUser("bids", "contractor_id")
 
 	rows, err := r.db.Query(query, contractorID)
 	if err != nil {
@@ -50,7 +50,7 @@ func (r *BidRepository) GetBidsByContractorID(contractorID int) ([]model.Bid, er
 
 	for rows.Next() {
 		var bid model.Bid
-		if err := rows.Scan(&bid.ID, &bid.ContractorID, &bid.TenderID, &bid.Price, &bid.DeliveryTime, &bid.Comments, &bid.CreatedAt, &bid.Status); err != nil {
+		if err := rows.Scan(&bid.ID, &bid.ContractorID, &bid.OrgID, &bid.TenderID, &bid.Price.Amount, &bid.Price.Currency, &bid.DeliveryTime, &bid.Comments, &bid.CreatedAt, &bid.Type, &bid.Status); err != nil {
 			return nil, fmt.Errorf("error scanning bid row: %w", err)
 		}
 		bids = append(bids, bid)
@@ -63,6 +63,33 @@ func (r *BidRepository) GetBidsByContractorID(contractorID int) ([]model.Bid, er
 	return bids, nil
 }
 
+// GetAwardedBidsByContractorID returns the contractor's winning bids on
+// tenders whose award is final, which is all of a bid history that is public.
+func (r *BidRepository) GetAwardedBidsByContractorID(contractorID int) ([]model.Bid, error) {
+	query := `SELECT b.id, b.contractor_id, COALESCE(b.org_id, 0), b.tender_id, b.price, b.currency, b.delivery_time, b.comments, b.created_at, b.type, b.status
+			  FROM bids b
+			  JOIN tenders t ON t.id = b.tender_id
+			  WHERE b.contractor_id = $1 AND b.status = 'awarded' AND t.award_finalized_at IS NOT NULL
+			  ORDER BY t.award_finalized_at DESC`
+
+	rows, err := r.db.Query(query, contractorID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching bids: %w", err)
+	}
+	defer rows.Close()
+
+	bids := []model.Bid{}
+	for rows.Next() {
+		var bid model.Bid
+		if err := rows.Scan(&bid.ID, &bid.ContractorID, &bid.OrgID, &bid.TenderID, &bid.Price.Amount, &bid.Price.Currency, &bid.DeliveryTime, &bid.Comments, &bid.CreatedAt, &bid.Type, &bid.Status); err != nil {
+			return nil, fmt.Errorf("error scanning bid row: %w", err)
+		}
+		bids = append(bids, bid)
+	}
+
+	return bids, rows.Err()
+}
+
 func (r *BidRepository) CreateBid(bid model.Bid) (*model.Bid, error) {
 	var count int
 	err := r.db.QueryRow(`
@@ -78,23 +105,55 @@ func (r *BidRepository) CreateBid(bid model.Bid) (*model.Bid, error) {
 		return nil, fmt.Errorf("rate limit exceeded: you can only submit 5 bids per minute")
 	}
 
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create bid: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Expressions of interest are not priced and store a NULL price.
+	var price interface{} = bid.Price.Amount
+	if bid.Type == model.BidTypeEOI {
+		price = nil
+	}
+
 	query := `
-		INSERT INTO bids (tender_id, contractor_id, price, delivery_time, comments, status, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
-		RETURNING id, tender_id, contractor_id, price, delivery_time, comments, status, created_at, updated_at;
+		INSERT INTO bids (tender_id, contractor_id, price, currency, delivery_time, comments, type, status, org_id, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NULLIF($9, 0), CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+		RETURNING id, tender_id, contractor_id, price, currency, delivery_time, comments, type, status, created_at, updated_at;
 	`
-	row := r.db.QueryRow(query, bid.TenderID, bid.ContractorID, bid.Price, bid.DeliveryTime, bid.Comments, bid.Status)
-	err = row.Scan(&bid.ID, &bid.TenderID, &bid.ContractorID, &bid.Price, &bid.DeliveryTime, &bid.Comments, &bid.Status, &bid.CreatedAt, &bid.UpdatedAt)
+	row := tx.QueryRow(query, bid.TenderID, bid.ContractorID, price, bid.Price.Currency, bid.DeliveryTime, bid.Comments, bid.Type, bid.Status, bid.OrgID)
+	err = row.Scan(&bid.ID, &bid.TenderID, &bid.ContractorID, &bid.Price.Amount, &bid.Price.Currency, &bid.DeliveryTime, &bid.Comments, &bid.Type, &bid.Status, &bid.CreatedAt, &bid.UpdatedAt)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create bid: %w", err)
 	}
+
+	for i, lot := range bid.Lots {
+		err := tx.QueryRow(`INSERT INTO bid_lots (bid_id, lot_id, price) VALUES ($1, $2, $3) RETURNING status`,
+			bid.ID, lot.LotID, lot.Price).Scan(&bid.Lots[i].Status)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create bid lot: %w", err)
+		}
+	}
+
+	for _, item := range bid.LineItems {
+		_, err := tx.Exec(`INSERT INTO bid_line_items (bid_id, boq_item_id, unit_price) VALUES ($1, $2, $3)`,
+			bid.ID, item.BoQItemID, item.UnitPrice)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create bid line item: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to create bid: %w", err)
+	}
 	return &bid, nil
 }
 
 func (r *BidRepository) GetBidsByTenderID(tenderID int) ([]model.Bid, error) {
 	var bids []model.Bid
 	query := `
-		SELECT id, tender_id, contractor_id, price, delivery_time, comments, status, created_at, updated_at
+		SELECT id, tender_id, contractor_id, price, currency, delivery_time, comments, type, status, created_at, updated_at
 		FROM bids
 		WHERE tender_id = $1;
 	`
@@ -106,7 +165,7 @@ func (r *BidRepository) GetBidsByTenderID(tenderID int) ([]model.Bid, error) {
 
 	for rows.Next() {
 		var bid model.Bid
-		err := rows.Scan(&bid.ID, &bid.TenderID, &bid.ContractorID, &bid.Price, &bid.DeliveryTime, &bid.Comments, &bid.Status, &bid.CreatedAt, &bid.UpdatedAt)
+		err := rows.Scan(&bid.ID, &bid.TenderID, &bid.ContractorID, &bid.Price.Amount, &bid.Price.Currency, &bid.DeliveryTime, &bid.Comments, &bid.Type, &bid.Status, &bid.CreatedAt, &bid.UpdatedAt)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan bid: %w", err)
 		}
@@ -123,17 +182,39 @@ func (r *BidRepository) GetBidsByTenderID(tenderID int) ([]model.Bid, error) {
 func (r *BidRepository) GetBidByID(id int) (*model.Bid, error) {
 	var bid model.Bid
 	query := `
-		SELECT id, tender_id, contractor_id, price, delivery_time, comments, status, created_at, updated_at
+		SELECT id, tender_id, contractor_id, COALESCE(org_id, 0), price, currency, delivery_time, comments, type, status, created_at, updated_at
 		FROM bids	
 		WHERE id = $1;
 	`
-	err := r.db.QueryRow(query, id).Scan(&bid.ID, &bid.TenderID, &bid.ContractorID, &bid.Price, &bid.DeliveryTime, &bid.Comments, &bid.Status, &bid.CreatedAt, &bid.UpdatedAt)
+	err := r.db.QueryRow(query, id).Scan(&bid.ID, &bid.TenderID, &bid.ContractorID, &bid.OrgID, &bid.Price.Amount, &bid.Price.Currency, &bid.DeliveryTime, &bid.Comments, &bid.Type, &bid.Status, &bid.CreatedAt, &bid.UpdatedAt)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch bid with ID %d: %w", id, err)
 	}
+
+	bid.Lots, err = r.GetBidLots(bid.ID)
+	if err != nil {
+		return nil, err
+	}
+	bid.LineItems, err = r.GetBidLineItems(bid.ID)
+	if err != nil {
+		return nil, err
+	}
 	return &bid, nil
 }
 
+// IsManagedBy reports whether the user belongs to the organization that owns
+// the bid.
+func (r *BidRepository) IsManagedBy(bidID, userID int) (bool, error) {
+	query := `
+		SELECT EXISTS(SELECT 1 FROM bids b JOIN users u ON u.org_id = b.org_id WHERE b.id = $1 AND u.id = $2)`
+
+	var managed bool
+	if err := r.db.QueryRow(query, bidID, userID).Scan(&managed); err != nil {
+		return false, fmt.Errorf("failed to check bid ownership: %w", err)
+	}
+	return managed, nil
+}
+
 func (r *BidRepository) DeleteBid(id int) error {
 	query := `
 		DELETE FROM bids
@@ -159,23 +240,23 @@ func (r *BidRepository) AwardBid(bidID int) error {
 	return nil
 }
 
-func (r *BidRepository) GetBidsByTenderIDWithFilters(tenderID int, priceFilter float64, deliveryTimeFilter, sortBy string) ([]model.Bid, error) {
+func (r *BidRepository) GetBidsByTenderIDWithFilters(tenderID int, bidType string, priceFilter model.Amount, deliveryTimeFilter, sortBy string) ([]model.Bid, error) {
 	query := `
-        SELECT id, tender_id, contractor_id, price, delivery_time, status, created_at
+        SELECT id, tender_id, contractor_id, price, currency, delivery_time, type, status, created_at
         FROM bids
-        WHERE tender_id = $1`
+        WHERE tender_id = $1 AND type = $2`
 
 	var args []interface{}
-	args = append(args, tenderID)
+	args = append(args, tenderID, bidType)
 
 	if priceFilter > 0 {
-		query += " AND price <= $2"
 		args = append(args, priceFilter)
+		query += fmt.Sprintf(" AND price <= $%d", len(args))
 	}
 
 	if deliveryTimeFilter != "" {
-		query += " AND delivery_time = $3"
 		args = append(args, deliveryTimeFilter)
+		query += fmt.Sprintf(" AND delivery_time = $%d", len(args))
 	}
 
 	if sortBy == "price" {
@@ -195,7 +276,7 @@ func (r *BidRepository) GetBidsByTenderIDWithFilters(tenderID int, priceFilter f
 	var bids []model.Bid
 	for rows.Next() {
 		var bid model.Bid
-		if err := rows.Scan(&bid.ID, &bid.TenderID, &bid.ContractorID, &bid.Price, &bid.DeliveryTime, &bid.Status, &bid.CreatedAt); err != nil {
+		if err := rows.Scan(&bid.ID, &bid.TenderID, &bid.ContractorID, &bid.Price.Amount, &bid.Price.Currency, &bid.DeliveryTime, &bid.Type, &bid.Status, &bid.CreatedAt); err != nil {
 			return nil, err
 		}
 		bids = append(bids, bid)
@@ -205,6 +286,17 @@ func (r *BidRepository) GetBidsByTenderIDWithFilters(tenderID int, priceFilter f
 		return nil, err
 	}
 
+	for i := range bids {
+		bids[i].Lots, err = r.GetBidLots(bids[i].ID)
+		if err != nil {
+			return nil, err
+		}
+		bids[i].LineItems, err = r.GetBidLineItems(bids[i].ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return bids, nil
 }
 
@@ -221,3 +313,191 @@ func (r *BidRepository) CreateNotification(userID int, message string, relationI
 
 	return nil
 }
+
+func (r *BidRepository) HasContractorBid(tenderID, contractorID int) (bool, error) {
+	var exists bool
+	err := r.db.QueryRow(`SELECT EXISTS(SELECT 1 FROM bids WHERE tender_id = $1 AND contractor_id = $2)`, tenderID, contractorID).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to check bids: %w", err)
+	}
+	return exists, nil
+}
+
+func (r *BidRepository) GetBidLots(bidID string) ([]model.BidLot, error) {
+	rows, err := r.db.Query(`SELECT lot_id, price, status FROM bid_lots WHERE bid_id = $1 ORDER BY lot_id`, bidID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch bid lots: %w", err)
+	}
+	defer rows.Close()
+
+	var lots []model.BidLot
+	for rows.Next() {
+		var lot model.BidLot
+		if err := rows.Scan(&lot.LotID, &lot.Price, &lot.Status); err != nil {
+			return nil, fmt.Errorf("failed to scan bid lot: %w", err)
+		}
+		lots = append(lots, lot)
+	}
+
+	return lots, rows.Err()
+}
+
+// CountBidsByTenderID counts priced bids; expressions of interest are left out.
+func (r *BidRepository) CountBidsByTenderID(tenderID int) (int, error) {
+	var count int
+	err := r.db.QueryRow(`SELECT count(*) FROM bids WHERE tender_id = $1 AND type = 'bid'`, tenderID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count bids: %w", err)
+	}
+	return count, nil
+}
+
+// AwardLot awards a single lot to a bid. Competing offers for the same lot are
+// rejected and the bid itself is marked awarded, even when its other lots go
+// to someone else.
+func (r *BidRepository) AwardLot(lotID, bidID int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to award lot: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+		UPDATE tender_lots
+		SET status = 'awarded', awarded_bid_id = $2, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND status = 'open'`, lotID, bidID)
+	if err != nil {
+		return fmt.Errorf("failed to award lot: %w", err)
+	}
+	if rows, err := result.RowsAffected(); err != nil || rows == 0 {
+		return errors.New("lot not found or already decided")
+	}
+
+	_, err = tx.Exec(`
+		UPDATE bid_lots
+		SET status = CASE WHEN bid_id = $2 THEN 'awarded' ELSE 'rejected' END
+		WHERE lot_id = $1`, lotID, bidID)
+	if err != nil {
+		return fmt.Errorf("failed to update bid lots: %w", err)
+	}
+
+	_, err = tx.Exec(`UPDATE bids SET status = 'awarded', updated_at = CURRENT_TIMESTAMP WHERE id = $1`, bidID)
+	if err != nil {
+		return fmt.Errorf("failed to update bid: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+func (r *BidRepository) GetBidLineItems(bidID string) ([]model.BidLineItem, error) {
+	query := `
+		SELECT li.boq_item_id, li.unit_price, li.unit_price * b.quantity
+		FROM bid_line_items li
+		JOIN tender_boq_items b ON b.id = li.boq_item_id
+		WHERE li.bid_id = $1
+		ORDER BY b.item_number`
+
+	rows, err := r.db.Query(query, bidID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch bid line items: %w", err)
+	}
+	defer rows.Close()
+
+	var items []model.BidLineItem
+	for rows.Next() {
+		var item model.BidLineItem
+		if err := rows.Scan(&item.BoQItemID, &item.UnitPrice, &item.Total); err != nil {
+			return nil, fmt.Errorf("failed to scan bid line item: %w", err)
+		}
+		items = append(items, item)
+	}
+
+	return items, rows.Err()
+}
+
+// GetBoQOffersByTenderID returns every priced line of every bid on the tender,
+// keyed by bill of quantities item.
+func (r *BidRepository) GetBoQOffersByTenderID(tenderID int) (map[int][]model.BoQOffer, error) {
+	query := `
+		SELECT li.boq_item_id, b.id, b.contractor_id, li.unit_price, li.unit_price * i.quantity
+		FROM bid_line_items li
+		JOIN bids b ON b.id = li.bid_id
+		JOIN tender_boq_items i ON i.id = li.boq_item_id
+		WHERE b.tender_id = $1
+		ORDER BY li.boq_item_id, li.unit_price`
+
+	rows, err := r.db.Query(query, tenderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch bid line items: %w", err)
+	}
+	defer rows.Close()
+
+	offers := make(map[int][]model.BoQOffer)
+	for rows.Next() {
+		var itemID int
+		var offer model.BoQOffer
+		if err := rows.Scan(&itemID, &offer.BidID, &offer.ContractorID, &offer.UnitPrice, &offer.Total); err != nil {
+			return nil, fmt.Errorf("failed to scan bid line item: %w", err)
+		}
+		offers[itemID] = append(offers[itemID], offer)
+	}
+
+	return offers, rows.Err()
+}
+
+// ShortlistBid marks a pending expression of interest as shortlisted.
+func (r *BidRepository) ShortlistBid(tenderID, bidID int) error {
+	query := `
+		UPDATE bids
+		SET status = 'shortlisted', updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND tender_id = $2 AND type = 'eoi' AND status IN ('pending', 'shortlisted')`
+
+	result, err := r.db.Exec(query, bidID, tenderID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return fmt.Errorf("expression of interest %d not found", bidID)
+	}
+
+	return nil
+}
+
+// RejectPendingEOIs rejects every expression of interest that did not make
+// the shortlist.
+func (r *BidRepository) RejectPendingEOIs(tenderID int) error {
+	query := `
+		UPDATE bids
+		SET status = 'rejected', updated_at = CURRENT_TIMESTAMP
+		WHERE tender_id = $1 AND type = 'eoi' AND status = 'pending'`
+
+	_, err := r.db.Exec(query, tenderID)
+	return err
+}
+
+func (r *BidRepository) IsShortlisted(tenderID, contractorID int) (bool, error) {
+	query := `SELECT EXISTS(SELECT 1 FROM bids WHERE tender_id = $1 AND contractor_id = $2 AND type = 'eoi' AND status = 'shortlisted')`
+
+	var shortlisted bool
+	if err := r.db.QueryRow(query, tenderID, contractorID).Scan(&shortlisted); err != nil {
+		return false, err
+	}
+	return shortlisted, nil
+}
+
+// HasContractorBidOfType reports whether the contractor already responded to
+// the tender with a bid of the given type.
+func (r *BidRepository) HasContractorBidOfType(tenderID, contractorID int, bidType string) (bool, error) {
+	query := `SELECT EXISTS(SELECT 1 FROM bids WHERE tender_id = $1 AND contractor_id = $2 AND type = $3)`
+
+	var exists bool
+	if err := r.db.QueryRow(query, tenderID, contractorID, bidType).Scan(&exists); err != nil {
+		return false, err
+	}
+	return exists, nil
+}
@@ -0,0 +1,173 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"tender-managment/internal/model"
+)
+
+type ContractRepository struct {
+	db *sql.DB
+}
+
+func NewContractRepository(db *sql.DB) *ContractRepository {
+	return &ContractRepository{db: db}
+}
+
+const contractColumns = `id, tender_id, bid_id, client_id, contractor_id, price, currency, delivery_time, terms, start_date, end_date,
+        status, client_accepted_at, contractor_accepted_at, COALESCE(termination_reason, ''), created_at, updated_at`
+
+func scanContract(row rowScanner, c *model.Contract) error {
+	return row.Scan(
+		&c.ID,
+		&c.TenderID,
+		&c.BidID,
+		&c.ClientID,
+		&c.ContractorID,
+		&c.Price.Amount,
+		&c.Price.Currency,
+		&c.DeliveryTime,
+		&c.Terms,
+		&c.StartDate,
+		&c.EndDate,
+		&c.Status,
+		&c.ClientAcceptedAt,
+		&c.ContractorAcceptedAt,
+		&c.TerminationReason,
+		&c.CreatedAt,
+		&c.UpdatedAt,
+	)
+}
+
+func (r *ContractRepository) CreateContract(c *model.Contract) (*model.Contract, error) {
+	query := `
+        INSERT INTO contracts (tender_id, bid_id, client_id, contractor_id, price, currency, delivery_time, terms, start_date, end_date)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+        ON CONFLICT (bid_id) DO NOTHING
+        RETURNING ` + contractColumns
+
+	err := scanContract(r.db.QueryRow(query,
+		c.TenderID, c.BidID, c.ClientID, c.ContractorID, c.Price.Amount, c.Price.Currency,
+		c.DeliveryTime, c.Terms, c.StartDate, c.EndDate,
+	), c)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("a contract already exists for this bid")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create contract: %w", err)
+	}
+
+	return c, nil
+}
+
+func (r *ContractRepository) GetContractByID(id int) (*model.Contract, error) {
+	var c model.Contract
+	err := scanContract(r.db.QueryRow(`SELECT `+contractColumns+` FROM contracts WHERE id = $1`, id), &c)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("contract not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &c, nil
+}
+
+// ListContractsByUserID lists the contracts the user is a party to, newest
+// first. An empty status lists every contract.
+func (r *ContractRepository) ListContractsByUserID(userID int, status string) ([]model.Contract, error) {
+	query := `SELECT ` + contractColumns + ` FROM contracts WHERE (client_id = $1 OR contractor_id = $1)`
+	args := []interface{}{userID}
+	if status != "" {
+		args = append(args, status)
+		query += fmt.Sprintf(" AND status = $%d", len(args))
+	}
+	query += " ORDER BY created_at DESC"
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch contracts: %w", err)
+	}
+	defer rows.Close()
+
+	contracts := []model.Contract{}
+	for rows.Next() {
+		var c model.Contract
+		if err := scanContract(rows, &c); err != nil {
+			return nil, fmt.Errorf("failed to scan contract: %w", err)
+		}
+		contracts = append(contracts, c)
+	}
+
+	return contracts, rows.Err()
+}
+
+// Accept records the party's acceptance of a pending contract and activates
+// it once both sides have accepted.
+func (r *ContractRepository) Accept(contractID int, asClient bool) (*model.Contract, error) {
+	column := "contractor_accepted_at"
+	if asClient {
+		column = "client_accepted_at"
+	}
+
+	query := `
+        UPDATE contracts
+        SET ` + column + ` = COALESCE(` + column + `, CURRENT_TIMESTAMP), updated_at = CURRENT_TIMESTAMP
+        WHERE id = $1 AND status = 'pending'
+        RETURNING ` + contractColumns
+
+	var c model.Contract
+	err := scanContract(r.db.QueryRow(query, contractID), &c)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("contract is no longer pending")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if c.ClientAcceptedAt == nil || c.ContractorAcceptedAt == nil {
+		return &c, nil
+	}
+
+	query = `
+        UPDATE contracts
+        SET status = 'active', updated_at = CURRENT_TIMESTAMP
+        WHERE id = $1 AND status = 'pending'
+        RETURNING ` + contractColumns
+	if err := scanContract(r.db.QueryRow(query, contractID), &c); err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+
+	return &c, nil
+}
+
+// Complete marks an active contract as fulfilled.
+func (r *ContractRepository) Complete(contractID int) error {
+	query := `
+        UPDATE contracts
+        SET status = 'completed', updated_at = CURRENT_TIMESTAMP
+        WHERE id = $1 AND status = 'active'`
+
+	return r.transition(query, "only active contracts can be completed", contractID)
+}
+
+// Terminate ends a pending or active contract early.
+func (r *ContractRepository) Terminate(contractID int, reason string) error {
+	query := `
+        UPDATE contracts
+        SET status = 'terminated', termination_reason = $2, updated_at = CURRENT_TIMESTAMP
+        WHERE id = $1 AND status IN ('pending', 'active')`
+
+	return r.transition(query, "contract is already closed", contractID, reason)
+}
+
+func (r *ContractRepository) transition(query, conflict string, args ...interface{}) error {
+	result, err := r.db.Exec(query, args...)
+	if err != nil {
+		return err
+	}
+	if rows, err := result.RowsAffected(); err != nil || rows == 0 {
+		return errors.New(conflict)
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"tender-managment/internal/model"
+)
+
+type DeclarationRepository struct {
+	db *sql.DB
+}
+
+func NewDeclarationRepository(db *sql.DB) *DeclarationRepository {
+	return &DeclarationRepository{db: db}
+}
+
+// GetBidders lists every contractor that has responded to the tender.
+func (r *DeclarationRepository) GetBidders(tenderID int) ([]model.DeclaredBidder, error) {
+	query := `
+        SELECT DISTINCT u.id, u.username
+        FROM bids b
+                 JOIN users u ON u.id = b.contractor_id
+        WHERE b.tender_id = $1
+        ORDER BY u.id`
+
+	rows, err := r.db.Query(query, tenderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	bidders := []model.DeclaredBidder{}
+	for rows.Next() {
+		var bidder model.DeclaredBidder
+		if err := rows.Scan(&bidder.ContractorID, &bidder.Username); err != nil {
+			return nil, err
+		}
+		bidders = append(bidders, bidder)
+	}
+
+	return bidders, rows.Err()
+}
+
+func (r *DeclarationRepository) CreateDeclaration(declaration *model.COIDeclaration) (*model.COIDeclaration, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRow(`
+        INSERT INTO coi_declarations (tender_id, user_id, statement, has_conflict)
+        VALUES ($1, $2, $3, $4)
+        RETURNING id, signed_at`,
+		declaration.TenderID, declaration.UserID, declaration.Statement, declaration.HasConflict,
+	).Scan(&declaration.ID, &declaration.SignedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to store declaration: %w", err)
+	}
+
+	for _, bidder := range declaration.Bidders {
+		_, err := tx.Exec(`INSERT INTO coi_declaration_bidders (declaration_id, contractor_id, conflict) VALUES ($1, $2, $3)`,
+			declaration.ID, bidder.ContractorID, bidder.Conflict)
+		if err != nil {
+			return nil, fmt.Errorf("failed to store declared bidder: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return declaration, nil
+}
+
+// GetLatestDeclaration returns the user's most recent declaration for the
+// tender.
+func (r *DeclarationRepository) GetLatestDeclaration(tenderID, userID int) (*model.COIDeclaration, error) {
+	query := `
+        SELECT id, tender_id, user_id, COALESCE(statement, ''), has_conflict, signed_at
+        FROM coi_declarations
+        WHERE tender_id = $1 AND user_id = $2
+        ORDER BY signed_at DESC, id DESC
+        LIMIT 1`
+
+	var d model.COIDeclaration
+	err := r.db.QueryRow(query, tenderID, userID).Scan(&d.ID, &d.TenderID, &d.UserID, &d.Statement, &d.HasConflict, &d.SignedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("declaration not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	d.Bidders, err = r.getDeclaredBidders(d.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &d, nil
+}
+
+func (r *DeclarationRepository) GetDeclarationsByTenderID(tenderID int) ([]model.COIDeclaration, error) {
+	query := `
+        SELECT id, tender_id, user_id, COALESCE(statement, ''), has_conflict, signed_at
+        FROM coi_declarations
+        WHERE tender_id = $1
+        ORDER BY signed_at`
+
+	rows, err := r.db.Query(query, tenderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var declarations []model.COIDeclaration
+	for rows.Next() {
+		var d model.COIDeclaration
+		if err := rows.Scan(&d.ID, &d.TenderID, &d.UserID, &d.Statement, &d.HasConflict, &d.SignedAt); err != nil {
+			return nil, err
+		}
+		declarations = append(declarations, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range declarations {
+		declarations[i].Bidders, err = r.getDeclaredBidders(declarations[i].ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return declarations, nil
+}
+
+func (r *DeclarationRepository) getDeclaredBidders(declarationID int) ([]model.DeclaredBidder, error) {
+	query := `
+        SELECT d.contractor_id, u.username, d.conflict
+        FROM coi_declaration_bidders d
+                 JOIN users u ON u.id = d.contractor_id
+        WHERE d.declaration_id = $1
+        ORDER BY d.contractor_id`
+
+	rows, err := r.db.Query(query, declarationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	bidders := []model.DeclaredBidder{}
+	for rows.Next() {
+		var bidder model.DeclaredBidder
+		if err := rows.Scan(&bidder.ContractorID, &bidder.Username, &bidder.Conflict); err != nil {
+			return nil, err
+		}
+		bidders = append(bidders, bidder)
+	}
+
+	return bidders, rows.Err()
+}
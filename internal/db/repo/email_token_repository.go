@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+type EmailTokenRepository struct {
+	db *sql.DB
+}
+
+func NewEmailTokenRepository(db *sql.DB) *EmailTokenRepository {
+	return &EmailTokenRepository{db: db}
+}
+
+// CreateEmailToken stores the hash of a token mailed to the user. Tokens sent
+// earlier for the same purpose stop working.
+func (r *EmailTokenRepository) CreateEmailToken(userID int, purpose, hash string, expiresAt time.Time) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to create email token: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+        UPDATE email_tokens SET used_at = NOW()
+        WHERE user_id = $1 AND purpose = $2 AND used_at IS NULL`, userID, purpose)
+	if err != nil {
+		return fmt.Errorf("failed to create email token: %w", err)
+	}
+
+	_, err = tx.Exec(`
+        INSERT INTO email_tokens (user_id, purpose, token_hash, expires_at)
+        VALUES ($1, $2, $3, $4)`, userID, purpose, hash, expiresAt)
+	if err != nil {
+		return fmt.Errorf("failed to create email token: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to create email token: %w", err)
+	}
+	return nil
+}
+
+// GetEmailTokenUser returns the user an unused, unexpired token was issued to
+// without using it up.
+func (r *EmailTokenRepository) GetEmailTokenUser(purpose, hash string) (int, error) {
+	query := `
+        SELECT user_id FROM email_tokens
+        WHERE token_hash = $1 AND purpose = $2 AND used_at IS NULL AND expires_at > NOW()`
+
+	var userID int
+	err := r.db.QueryRow(query, hash, purpose).Scan(&userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, errors.New("invalid or expired token")
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch email token: %w", err)
+	}
+	return userID, nil
+}
+
+// ConsumeEmailToken marks an unexpired token as used and returns the user it
+// was issued to. Each token works once.
+func (r *EmailTokenRepository) ConsumeEmailToken(purpose, hash string) (int, error) {
+	query := `
+        UPDATE email_tokens SET used_at = NOW()
+        WHERE token_hash = $1 AND purpose = $2 AND used_at IS NULL AND expires_at > NOW()
+        RETURNING user_id`
+
+	var userID int
+	err := r.db.QueryRow(query, hash, purpose).Scan(&userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, errors.New("invalid or expired token")
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to use email token: %w", err)
+	}
+	return userID, nil
+}
@@ -0,0 +1,218 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"tender-managment/internal/model"
+)
+
+type EvaluationRepository struct {
+	db *sql.DB
+}
+
+func NewEvaluationRepository(db *sql.DB) *EvaluationRepository {
+	return &EvaluationRepository{db: db}
+}
+
+func (r *EvaluationRepository) AddEvaluators(tenderID int, evaluatorIDs []int) error {
+	query := `
+        INSERT INTO tender_evaluators (tender_id, evaluator_id)
+        VALUES ($1, $2)
+        ON CONFLICT DO NOTHING`
+
+	for _, evaluatorID := range evaluatorIDs {
+		if _, err := r.db.Exec(query, tenderID, evaluatorID); err != nil {
+			return fmt.Errorf("failed to appoint evaluator %d: %w", evaluatorID, err)
+		}
+	}
+
+	return nil
+}
+
+// RemoveEvaluator drops an evaluator from the committee together with their
+// scores.
+func (r *EvaluationRepository) RemoveEvaluator(tenderID, evaluatorID int) error {
+	result, err := r.db.Exec(`DELETE FROM tender_evaluators WHERE tender_id = $1 AND evaluator_id = $2`, tenderID, evaluatorID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return errors.New("evaluator not found")
+	}
+
+	return nil
+}
+
+func (r *EvaluationRepository) GetEvaluators(tenderID int) ([]model.Evaluator, error) {
+	query := `
+        SELECT e.tender_id, e.evaluator_id, u.username, e.appointed_at, e.submitted_at, e.signed_off_at
+        FROM tender_evaluators e
+                 JOIN users u ON u.id = e.evaluator_id
+        WHERE e.tender_id = $1
+        ORDER BY e.appointed_at`
+
+	rows, err := r.db.Query(query, tenderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var evaluators []model.Evaluator
+	for rows.Next() {
+		var e model.Evaluator
+		if err := rows.Scan(&e.TenderID, &e.EvaluatorID, &e.Username, &e.AppointedAt, &e.SubmittedAt, &e.SignedOffAt); err != nil {
+			return nil, err
+		}
+		evaluators = append(evaluators, e)
+	}
+
+	return evaluators, rows.Err()
+}
+
+func (r *EvaluationRepository) GetEvaluator(tenderID, evaluatorID int) (*model.Evaluator, error) {
+	query := `
+        SELECT e.tender_id, e.evaluator_id, u.username, e.appointed_at, e.submitted_at, e.signed_off_at
+        FROM tender_evaluators e
+                 JOIN users u ON u.id = e.evaluator_id
+        WHERE e.tender_id = $1 AND e.evaluator_id = $2`
+
+	var e model.Evaluator
+	err := r.db.QueryRow(query, tenderID, evaluatorID).Scan(&e.TenderID, &e.EvaluatorID, &e.Username, &e.AppointedAt, &e.SubmittedAt, &e.SignedOffAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("evaluator not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &e, nil
+}
+
+// SaveScores upserts an evaluator's scores. Scores are locked once the
+// evaluator has submitted.
+func (r *EvaluationRepository) SaveScores(tenderID, evaluatorID int, scores []model.BidScore) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var submitted bool
+	err = tx.QueryRow(`
+        SELECT submitted_at IS NOT NULL
+        FROM tender_evaluators
+        WHERE tender_id = $1 AND evaluator_id = $2
+        FOR UPDATE`, tenderID, evaluatorID).Scan(&submitted)
+	if errors.Is(err, sql.ErrNoRows) {
+		return errors.New("evaluator not found")
+	}
+	if err != nil {
+		return err
+	}
+	if submitted {
+		return errors.New("scores have already been submitted")
+	}
+
+	for _, score := range scores {
+		_, err := tx.Exec(`
+            INSERT INTO bid_scores (tender_id, evaluator_id, bid_id, score, comment)
+            VALUES ($1, $2, $3, $4, $5)
+            ON CONFLICT (bid_id, evaluator_id)
+                DO UPDATE SET score = EXCLUDED.score, comment = EXCLUDED.comment, updated_at = CURRENT_TIMESTAMP`,
+			tenderID, evaluatorID, score.BidID, score.Score, score.Comment)
+		if err != nil {
+			return fmt.Errorf("failed to save score for bid %d: %w", score.BidID, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *EvaluationRepository) GetScoresByEvaluator(tenderID, evaluatorID int) ([]model.BidScore, error) {
+	query := `
+        SELECT bid_id, evaluator_id, score, COALESCE(comment, ''), updated_at
+        FROM bid_scores
+        WHERE tender_id = $1 AND evaluator_id = $2
+        ORDER BY bid_id`
+
+	return r.queryScores(query, tenderID, evaluatorID)
+}
+
+func (r *EvaluationRepository) GetScores(tenderID int) ([]model.BidScore, error) {
+	query := `
+        SELECT bid_id, evaluator_id, score, COALESCE(comment, ''), updated_at
+        FROM bid_scores
+        WHERE tender_id = $1
+        ORDER BY bid_id, evaluator_id`
+
+	return r.queryScores(query, tenderID)
+}
+
+func (r *EvaluationRepository) queryScores(query string, args ...interface{}) ([]model.BidScore, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var scores []model.BidScore
+	for rows.Next() {
+		var score model.BidScore
+		if err := rows.Scan(&score.BidID, &score.EvaluatorID, &score.Score, &score.Comment, &score.UpdatedAt); err != nil {
+			return nil, err
+		}
+		scores = append(scores, score)
+	}
+
+	return scores, rows.Err()
+}
+
+func (r *EvaluationRepository) MarkSubmitted(tenderID, evaluatorID int) error {
+	query := `
+        UPDATE tender_evaluators
+        SET submitted_at = CURRENT_TIMESTAMP
+        WHERE tender_id = $1 AND evaluator_id = $2 AND submitted_at IS NULL`
+
+	result, err := r.db.Exec(query, tenderID, evaluatorID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return errors.New("scores have already been submitted")
+	}
+
+	return nil
+}
+
+func (r *EvaluationRepository) MarkSignedOff(tenderID, evaluatorID int) error {
+	query := `
+        UPDATE tender_evaluators
+        SET signed_off_at = CURRENT_TIMESTAMP
+        WHERE tender_id = $1 AND evaluator_id = $2 AND signed_off_at IS NULL`
+
+	result, err := r.db.Exec(query, tenderID, evaluatorID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return errors.New("already signed off")
+	}
+
+	return nil
+}
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"tender-managment/internal/model"
+)
+
+type ExchangeRateRepository struct {
+	db *sql.DB
+}
+
+func NewExchangeRateRepository(db *sql.DB) *ExchangeRateRepository {
+	return &ExchangeRateRepository{db: db}
+}
+
+// GetRate returns how many units of quote one unit of base buys. Only the
+// stored direction is looked up, inverting is left to the caller. A missing
+// pair is reported as sql.ErrNoRows.
+func (r *ExchangeRateRepository) GetRate(base, quote string) (string, error) {
+	var rate string
+	err := r.db.QueryRow(`SELECT rate FROM exchange_rates WHERE base_currency = $1 AND quote_currency = $2`, base, quote).Scan(&rate)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch exchange rate: %w", err)
+	}
+	return rate, nil
+}
+
+func (r *ExchangeRateRepository) ListRates() ([]model.ExchangeRate, error) {
+	rows, err := r.db.Query(`SELECT base_currency, quote_currency, rate, updated_at FROM exchange_rates ORDER BY base_currency, quote_currency`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch exchange rates: %w", err)
+	}
+	defer rows.Close()
+
+	var rates []model.ExchangeRate
+	for rows.Next() {
+		var rate model.ExchangeRate
+		if err := rows.Scan(&rate.BaseCurrency, &rate.QuoteCurrency, &rate.Rate, &rate.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan exchange rate: %w", err)
+		}
+		rates = append(rates, rate)
+	}
+
+	return rates, rows.Err()
+}
+
+// SetRate stores the rate for the pair, replacing any previous value.
+func (r *ExchangeRateRepository) SetRate(base, quote, rate string) (*model.ExchangeRate, error) {
+	query := `
+        INSERT INTO exchange_rates (base_currency, quote_currency, rate)
+        VALUES ($1, $2, $3)
+        ON CONFLICT (base_currency, quote_currency) DO UPDATE SET rate = EXCLUDED.rate, updated_at = CURRENT_TIMESTAMP
+        RETURNING base_currency, quote_currency, rate, updated_at`
+
+	var saved model.ExchangeRate
+	err := r.db.QueryRow(query, base, quote, rate).Scan(&saved.BaseCurrency, &saved.QuoteCurrency, &saved.Rate, &saved.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save exchange rate: %w", err)
+	}
+	return &saved, nil
+}
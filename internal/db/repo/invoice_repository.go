@@ -0,0 +1,245 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"tender-managment/internal/model"
+	"time"
+)
+
+type InvoiceRepository struct {
+	db *sql.DB
+}
+
+func NewInvoiceRepository(db *sql.DB) *InvoiceRepository {
+	return &InvoiceRepository{db: db}
+}
+
+const invoiceColumns = `id, number, contract_id, milestone_id, contractor_id, client_id, currency, subtotal, tax_total, total, status,
+        notes, COALESCE(dispute_reason, ''), COALESCE(payment_reference, ''), approved_at, paid_at, created_at, updated_at`
+
+func scanInvoice(row rowScanner, inv *model.Invoice) error {
+	return row.Scan(
+		&inv.ID,
+		&inv.Number,
+		&inv.ContractID,
+		&inv.MilestoneID,
+		&inv.ContractorID,
+		&inv.ClientID,
+		&inv.Currency,
+		&inv.Subtotal,
+		&inv.TaxTotal,
+		&inv.Total,
+		&inv.Status,
+		&inv.Notes,
+		&inv.DisputeReason,
+		&inv.PaymentReference,
+		&inv.ApprovedAt,
+		&inv.PaidAt,
+		&inv.CreatedAt,
+		&inv.UpdatedAt,
+	)
+}
+
+// CreateInvoice stores the invoice with its lines and taxes under the
+// contractor's next sequence number.
+func (r *InvoiceRepository) CreateInvoice(inv *model.Invoice) (*model.Invoice, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var sequence int
+	err = tx.QueryRow(`
+        INSERT INTO invoice_counters (contractor_id, last_sequence)
+        VALUES ($1, 1)
+        ON CONFLICT (contractor_id) DO UPDATE SET last_sequence = invoice_counters.last_sequence + 1
+        RETURNING last_sequence`, inv.ContractorID).Scan(&sequence)
+	if err != nil {
+		return nil, fmt.Errorf("failed to allocate invoice number: %w", err)
+	}
+	number := fmt.Sprintf("INV-%d-%06d", inv.ContractorID, sequence)
+
+	lines, taxes := inv.Lines, inv.Taxes
+	err = scanInvoice(tx.QueryRow(`
+        INSERT INTO invoices (contract_id, milestone_id, contractor_id, client_id, sequence, number, currency, subtotal, tax_total, total, notes)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+        RETURNING `+invoiceColumns,
+		inv.ContractID, inv.MilestoneID, inv.ContractorID, inv.ClientID, sequence, number, inv.Currency,
+		inv.Subtotal, inv.TaxTotal, inv.Total, inv.Notes,
+	), inv)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create invoice: %w", err)
+	}
+
+	for _, line := range lines {
+		_, err := tx.Exec(`INSERT INTO invoice_lines (invoice_id, description, quantity, unit_price, amount) VALUES ($1, $2, $3, $4, $5)`,
+			inv.ID, line.Description, line.Quantity, line.UnitPrice, line.Amount)
+		if err != nil {
+			return nil, fmt.Errorf("failed to save invoice line: %w", err)
+		}
+	}
+	for _, tax := range taxes {
+		_, err := tx.Exec(`INSERT INTO invoice_taxes (invoice_id, name, rate, amount) VALUES ($1, $2, $3, $4)`,
+			inv.ID, tax.Name, tax.Rate, tax.Amount)
+		if err != nil {
+			return nil, fmt.Errorf("failed to save invoice tax: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	inv.Lines, inv.Taxes = lines, taxes
+	return inv, nil
+}
+
+func (r *InvoiceRepository) GetInvoiceByID(id int) (*model.Invoice, error) {
+	var inv model.Invoice
+	err := scanInvoice(r.db.QueryRow(`SELECT `+invoiceColumns+` FROM invoices WHERE id = $1`, id), &inv)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("invoice not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.loadDetails(&inv); err != nil {
+		return nil, err
+	}
+	return &inv, nil
+}
+
+func (r *InvoiceRepository) GetInvoicesByContractID(contractID int) ([]model.Invoice, error) {
+	rows, err := r.db.Query(`SELECT `+invoiceColumns+` FROM invoices WHERE contract_id = $1 ORDER BY id`, contractID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch invoices: %w", err)
+	}
+	defer rows.Close()
+
+	invoices := []model.Invoice{}
+	for rows.Next() {
+		var inv model.Invoice
+		if err := scanInvoice(rows, &inv); err != nil {
+			return nil, fmt.Errorf("failed to scan invoice: %w", err)
+		}
+		invoices = append(invoices, inv)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range invoices {
+		if err := r.loadDetails(&invoices[i]); err != nil {
+			return nil, err
+		}
+	}
+	return invoices, nil
+}
+
+func (r *InvoiceRepository) loadDetails(inv *model.Invoice) error {
+	rows, err := r.db.Query(`SELECT description, quantity, unit_price, amount FROM invoice_lines WHERE invoice_id = $1 ORDER BY id`, inv.ID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch invoice lines: %w", err)
+	}
+	defer rows.Close()
+
+	inv.Lines = []model.InvoiceLine{}
+	for rows.Next() {
+		var line model.InvoiceLine
+		if err := rows.Scan(&line.Description, &line.Quantity, &line.UnitPrice, &line.Amount); err != nil {
+			return err
+		}
+		inv.Lines = append(inv.Lines, line)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	taxRows, err := r.db.Query(`SELECT name, rate, amount FROM invoice_taxes WHERE invoice_id = $1 ORDER BY id`, inv.ID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch invoice taxes: %w", err)
+	}
+	defer taxRows.Close()
+
+	inv.Taxes = []model.InvoiceTax{}
+	for taxRows.Next() {
+		var tax model.InvoiceTax
+		if err := taxRows.Scan(&tax.Name, &tax.Rate, &tax.Amount); err != nil {
+			return err
+		}
+		inv.Taxes = append(inv.Taxes, tax)
+	}
+
+	return taxRows.Err()
+}
+
+// InvoicedTotals sums invoice subtotals for a contract by status.
+func (r *InvoiceRepository) InvoicedTotals(contractID int) (map[string]model.Amount, error) {
+	rows, err := r.db.Query(`SELECT status, COALESCE(SUM(subtotal), 0) FROM invoices WHERE contract_id = $1 GROUP BY status`, contractID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	totals := make(map[string]model.Amount)
+	for rows.Next() {
+		var status string
+		var total model.Amount
+		if err := rows.Scan(&status, &total); err != nil {
+			return nil, err
+		}
+		totals[status] = total
+	}
+
+	return totals, rows.Err()
+}
+
+// MilestoneInvoiced sums the subtotals of undisputed invoices raised for a
+// milestone.
+func (r *InvoiceRepository) MilestoneInvoiced(milestoneID int) (model.Amount, error) {
+	var total model.Amount
+	err := r.db.QueryRow(`SELECT COALESCE(SUM(subtotal), 0) FROM invoices WHERE milestone_id = $1 AND status <> 'disputed'`, milestoneID).Scan(&total)
+	return total, err
+}
+
+func (r *InvoiceRepository) Approve(id int) error {
+	query := `
+        UPDATE invoices
+        SET status = 'approved', approved_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+        WHERE id = $1 AND status = 'submitted'`
+
+	return r.transition(query, "only submitted invoices can be approved", id)
+}
+
+func (r *InvoiceRepository) Dispute(id int, reason string) error {
+	query := `
+        UPDATE invoices
+        SET status = 'disputed', dispute_reason = $2, updated_at = CURRENT_TIMESTAMP
+        WHERE id = $1 AND status IN ('submitted', 'approved')`
+
+	return r.transition(query, "paid or disputed invoices cannot be disputed", id, reason)
+}
+
+func (r *InvoiceRepository) MarkPaid(id int, reference string, paidAt time.Time) error {
+	query := `
+        UPDATE invoices
+        SET status = 'paid', payment_reference = $2, paid_at = $3, updated_at = CURRENT_TIMESTAMP
+        WHERE id = $1 AND status = 'approved'`
+
+	return r.transition(query, "only approved invoices can be paid", id, reference, paidAt)
+}
+
+func (r *InvoiceRepository) transition(query, conflict string, args ...interface{}) error {
+	result, err := r.db.Exec(query, args...)
+	if err != nil {
+		return err
+	}
+	if rows, err := result.RowsAffected(); err != nil || rows == 0 {
+		return errors.New(conflict)
+	}
+	return nil
+}
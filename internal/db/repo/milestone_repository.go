@@ -0,0 +1,280 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"tender-managment/internal/model"
+)
+
+type MilestoneRepository struct {
+	db *sql.DB
+}
+
+func NewMilestoneRepository(db *sql.DB) *MilestoneRepository {
+	return &MilestoneRepository{db: db}
+}
+
+const milestoneColumns = `id, contract_id, sequence, title, description, due_date, amount, status,
+        COALESCE(delivery_note, ''), delivered_at, COALESCE(review_comment, ''), reviewed_at, created_at, updated_at`
+
+func scanMilestone(row rowScanner, m *model.Milestone) error {
+	return row.Scan(
+		&m.ID,
+		&m.ContractID,
+		&m.Sequence,
+		&m.Title,
+		&m.Description,
+		&m.DueDate,
+		&m.Amount,
+		&m.Status,
+		&m.DeliveryNote,
+		&m.DeliveredAt,
+		&m.ReviewComment,
+		&m.ReviewedAt,
+		&m.CreatedAt,
+		&m.UpdatedAt,
+	)
+}
+
+// ReplaceMilestones swaps the contract's schedule for milestones. It fails
+// once work on any milestone has been delivered or invoiced.
+func (r *MilestoneRepository) ReplaceMilestones(contractID int, milestones []model.Milestone) ([]model.Milestone, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Locking the current schedule makes an invoice being raised against one
+	// of its milestones either finish first or wait for the replacement.
+	if _, err := tx.Exec(`SELECT id FROM contract_milestones WHERE contract_id = $1 FOR UPDATE`, contractID); err != nil {
+		return nil, err
+	}
+
+	var started bool
+	err = tx.QueryRow(`
+        SELECT EXISTS(SELECT 1 FROM contract_milestones WHERE contract_id = $1 AND status <> 'pending')
+            OR EXISTS(SELECT 1 FROM invoices i JOIN contract_milestones m ON m.id = i.milestone_id WHERE m.contract_id = $1)`,
+		contractID).Scan(&started)
+	if err != nil {
+		return nil, err
+	}
+	if started {
+		return nil, errors.New("schedule can no longer be changed")
+	}
+
+	if _, err := tx.Exec(`DELETE FROM contract_milestones WHERE contract_id = $1`, contractID); err != nil {
+		return nil, err
+	}
+
+	for i := range milestones {
+		m := &milestones[i]
+		err := scanMilestone(tx.QueryRow(`
+            INSERT INTO contract_milestones (contract_id, sequence, title, description, due_date, amount)
+            VALUES ($1, $2, $3, $4, $5, $6)
+            RETURNING `+milestoneColumns,
+			contractID, i+1, m.Title, m.Description, m.DueDate, m.Amount,
+		), m)
+		if err != nil {
+			return nil, fmt.Errorf("failed to save milestone: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return milestones, nil
+}
+
+func (r *MilestoneRepository) GetMilestones(contractID int) ([]model.Milestone, error) {
+	rows, err := r.db.Query(`SELECT `+milestoneColumns+` FROM contract_milestones WHERE contract_id = $1 ORDER BY sequence`, contractID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch milestones: %w", err)
+	}
+	defer rows.Close()
+
+	milestones := []model.Milestone{}
+	for rows.Next() {
+		var m model.Milestone
+		if err := scanMilestone(rows, &m); err != nil {
+			return nil, fmt.Errorf("failed to scan milestone: %w", err)
+		}
+		milestones = append(milestones, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range milestones {
+		milestones[i].Documents, err = r.GetDocuments(milestones[i].ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return milestones, nil
+}
+
+func (r *MilestoneRepository) GetMilestoneByID(id int) (*model.Milestone, error) {
+	var m model.Milestone
+	err := scanMilestone(r.db.QueryRow(`SELECT `+milestoneColumns+` FROM contract_milestones WHERE id = $1`, id), &m)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("milestone not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &m, nil
+}
+
+// Deliver marks an outstanding or rejected milestone as delivered for review.
+func (r *MilestoneRepository) Deliver(id int, note string) (*model.Milestone, error) {
+	query := `
+        UPDATE contract_milestones
+        SET status = 'delivered', delivery_note = $2, delivered_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+        WHERE id = $1 AND status IN ('pending', 'rejected')
+        RETURNING ` + milestoneColumns
+
+	var m model.Milestone
+	err := scanMilestone(r.db.QueryRow(query, id, note), &m)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("milestone has already been delivered")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &m, nil
+}
+
+// Review accepts or rejects a delivered milestone.
+func (r *MilestoneRepository) Review(id int, status, comment string) (*model.Milestone, error) {
+	query := `
+        UPDATE contract_milestones
+        SET status = $2, review_comment = $3, reviewed_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+        WHERE id = $1 AND status = 'delivered'
+        RETURNING ` + milestoneColumns
+
+	var m model.Milestone
+	err := scanMilestone(r.db.QueryRow(query, id, status, comment), &m)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("milestone is not awaiting review")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &m, nil
+}
+
+// AllAccepted reports whether every milestone of the contract was accepted.
+func (r *MilestoneRepository) AllAccepted(contractID int) (bool, error) {
+	query := `SELECT NOT EXISTS(SELECT 1 FROM contract_milestones WHERE contract_id = $1 AND status <> 'accepted')`
+
+	var accepted bool
+	if err := r.db.QueryRow(query, contractID).Scan(&accepted); err != nil {
+		return false, err
+	}
+	return accepted, nil
+}
+
+// GetUnnotifiedOverdue returns outstanding milestones of active contracts
+// whose due date has passed and whose parties have not been alerted yet.
+func (r *MilestoneRepository) GetUnnotifiedOverdue() ([]model.Milestone, error) {
+	query := `
+        SELECT m.id, m.contract_id, m.sequence, m.title, m.description, m.due_date, m.amount, m.status,
+            COALESCE(m.delivery_note, ''), m.delivered_at, COALESCE(m.review_comment, ''), m.reviewed_at, m.created_at, m.updated_at
+        FROM contract_milestones m
+        JOIN contracts c ON c.id = m.contract_id
+        WHERE c.status = 'active'
+          AND m.status IN ('pending', 'rejected') AND m.due_date < CURRENT_DATE
+          AND m.overdue_notified_at IS NULL`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch overdue milestones: %w", err)
+	}
+	defer rows.Close()
+
+	var milestones []model.Milestone
+	for rows.Next() {
+		var m model.Milestone
+		if err := scanMilestone(rows, &m); err != nil {
+			return nil, fmt.Errorf("failed to scan milestone: %w", err)
+		}
+		milestones = append(milestones, m)
+	}
+
+	return milestones, rows.Err()
+}
+
+// MarkOverdueNotified records that the parties were alerted about the
+// overdue milestone, so they are not alerted again.
+func (r *MilestoneRepository) MarkOverdueNotified(id int) error {
+	_, err := r.db.Exec(`UPDATE contract_milestones SET overdue_notified_at = CURRENT_TIMESTAMP WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to mark milestone as notified: %w", err)
+	}
+	return nil
+}
+
+func (r *MilestoneRepository) AddDocument(doc *model.MilestoneDocument) (*model.MilestoneDocument, error) {
+	query := `
+        INSERT INTO milestone_documents (milestone_id, file_name, storage_key, mime_type, size, sha256)
+        VALUES ($1, $2, $3, $4, $5, $6)
+        RETURNING id, created_at`
+
+	err := r.db.QueryRow(query, doc.MilestoneID, doc.FileName, doc.StorageKey, doc.MimeType, doc.Size, doc.SHA256).
+		Scan(&doc.ID, &doc.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to store milestone document: %w", err)
+	}
+
+	return doc, nil
+}
+
+func (r *MilestoneRepository) GetDocuments(milestoneID int) ([]model.MilestoneDocument, error) {
+	query := `
+        SELECT id, milestone_id, file_name, storage_key, mime_type, size, sha256, created_at
+        FROM milestone_documents
+        WHERE milestone_id = $1
+        ORDER BY id`
+
+	rows, err := r.db.Query(query, milestoneID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var docs []model.MilestoneDocument
+	for rows.Next() {
+		var doc model.MilestoneDocument
+		if err := rows.Scan(&doc.ID, &doc.MilestoneID, &doc.FileName, &doc.StorageKey, &doc.MimeType, &doc.Size, &doc.SHA256, &doc.CreatedAt); err != nil {
+			return nil, err
+		}
+		docs = append(docs, doc)
+	}
+
+	return docs, rows.Err()
+}
+
+func (r *MilestoneRepository) GetDocumentByID(id int) (*model.MilestoneDocument, error) {
+	query := `
+        SELECT id, milestone_id, file_name, storage_key, mime_type, size, sha256, created_at
+        FROM milestone_documents
+        WHERE id = $1`
+
+	var doc model.MilestoneDocument
+	err := r.db.QueryRow(query, id).Scan(&doc.ID, &doc.MilestoneID, &doc.FileName, &doc.StorageKey, &doc.MimeType, &doc.Size, &doc.SHA256, &doc.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("document not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &doc, nil
+}
@@ -0,0 +1,233 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"tender-managment/internal/model"
+)
+
+type OrganizationRepository struct {
+	db *sql.DB
+}
+
+func NewOrganizationRepository(db *sql.DB) *OrganizationRepository {
+	return &OrganizationRepository{db: db}
+}
+
+const organizationColumns = `id, legal_name, tax_id, address, require_2fa_for_awards, created_at, updated_at`
+
+func scanOrganization(row rowScanner, o *model.Organization) error {
+	return row.Scan(&o.ID, &o.LegalName, &o.TaxID, &o.Address, &o.RequireTwoFactorForAwards, &o.CreatedAt, &o.UpdatedAt)
+}
+
+// CreateOrganization registers the organization and makes its founder the
+// first member, with the owner role.
+func (r *OrganizationRepository) CreateOrganization(org *model.Organization, founderID int) (*model.Organization, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create organization: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+        INSERT INTO organizations (legal_name, tax_id, address, require_2fa_for_awards)
+        VALUES ($1, $2, $3, $4)
+        ON CONFLICT (tax_id) DO NOTHING
+        RETURNING ` + organizationColumns
+
+	err = scanOrganization(tx.QueryRow(query, org.LegalName, org.TaxID, org.Address, org.RequireTwoFactorForAwards), org)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("an organization with this tax ID already exists")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create organization: %w", err)
+	}
+
+	if err := replaceContacts(tx, org); err != nil {
+		return nil, err
+	}
+	if err := joinOrganization(tx, org.ID, founderID, "owner"); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to create organization: %w", err)
+	}
+	return org, nil
+}
+
+func (r *OrganizationRepository) GetOrganizationByID(id int) (*model.Organization, error) {
+	var org model.Organization
+	err := scanOrganization(r.db.QueryRow(`SELECT `+organizationColumns+` FROM organizations WHERE id = $1`, id), &org)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("organization not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	org.Contacts, err = r.GetContacts(id)
+	if err != nil {
+		return nil, err
+	}
+	return &org, nil
+}
+
+func (r *OrganizationRepository) GetContacts(orgID int) ([]model.OrganizationContact, error) {
+	rows, err := r.db.Query(`SELECT id, name, email, phone, position FROM organization_contacts WHERE org_id = $1 ORDER BY id`, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch contacts: %w", err)
+	}
+	defer rows.Close()
+
+	contacts := []model.OrganizationContact{}
+	for rows.Next() {
+		var c model.OrganizationContact
+		if err := rows.Scan(&c.ID, &c.Name, &c.Email, &c.Phone, &c.Position); err != nil {
+			return nil, fmt.Errorf("failed to scan contact: %w", err)
+		}
+		contacts = append(contacts, c)
+	}
+
+	return contacts, rows.Err()
+}
+
+// UpdateOrganization saves the organization's details and replaces its
+// contacts.
+func (r *OrganizationRepository) UpdateOrganization(org *model.Organization) (*model.Organization, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to update organization: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+        UPDATE organizations SET legal_name = $2, tax_id = $3, address = $4, require_2fa_for_awards = $5,
+            updated_at = CURRENT_TIMESTAMP
+        WHERE id = $1
+        RETURNING ` + organizationColumns
+
+	err = scanOrganization(tx.QueryRow(query, org.ID, org.LegalName, org.TaxID, org.Address, org.RequireTwoFactorForAwards), org)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("organization not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update organization: %w", err)
+	}
+
+	if err := replaceContacts(tx, org); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to update organization: %w", err)
+	}
+	return org, nil
+}
+
+// AddMember moves a user without an organization into it with the given role.
+// The tenders and bids they created so far are handed over to the
+// organization.
+func (r *OrganizationRepository) AddMember(orgID, userID int, role string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to add member: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := joinOrganization(tx, orgID, userID, role); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// SetMemberRole changes a member's role. The last owner cannot step down.
+func (r *OrganizationRepository) SetMemberRole(orgID, userID int, role string) error {
+	return r.changeMember(orgID, userID, role != "owner",
+		`UPDATE users SET org_role = $3, updated_at = CURRENT_TIMESTAMP WHERE id = $1 AND org_id = $2`, role)
+}
+
+// RemoveMember takes the user out of the organization. Tenders and bids stay
+// with the organization. The last owner cannot leave.
+func (r *OrganizationRepository) RemoveMember(orgID, userID int) error {
+	return r.changeMember(orgID, userID, true,
+		`UPDATE users SET org_id = NULL, org_role = NULL, updated_at = CURRENT_TIMESTAMP WHERE id = $1 AND org_id = $2`)
+}
+
+// changeMember runs query against a member of the organization. When
+// dropsOwner is set and the member is the organization's only owner, the
+// change is refused.
+func (r *OrganizationRepository) changeMember(orgID, userID int, dropsOwner bool, query string, args ...interface{}) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to update member: %w", err)
+	}
+	defer tx.Rollback()
+
+	var role string
+	err = tx.QueryRow(`SELECT COALESCE(org_role, '') FROM users WHERE id = $1 AND org_id = $2 FOR UPDATE`, userID, orgID).Scan(&role)
+	if errors.Is(err, sql.ErrNoRows) {
+		return errors.New("member not found")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update member: %w", err)
+	}
+
+	if dropsOwner && role == "owner" {
+		var owners int
+		err := tx.QueryRow(`SELECT COUNT(*) FROM users WHERE org_id = $1 AND org_role = 'owner' AND id <> $2`, orgID, userID).Scan(&owners)
+		if err != nil {
+			return fmt.Errorf("failed to update member: %w", err)
+		}
+		if owners == 0 {
+			return errors.New("an organization needs at least one owner")
+		}
+	}
+
+	if _, err := tx.Exec(query, append([]interface{}{userID, orgID}, args...)...); err != nil {
+		return fmt.Errorf("failed to update member: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+func joinOrganization(tx *sql.Tx, orgID, userID int, role string) error {
+	res, err := tx.Exec(`UPDATE users SET org_id = $1, org_role = $3, updated_at = CURRENT_TIMESTAMP WHERE id = $2 AND org_id IS NULL`, orgID, userID, role)
+	if err != nil {
+		return fmt.Errorf("failed to add member: %w", err)
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return errors.New("user already belongs to an organization")
+	}
+
+	if _, err := tx.Exec(`UPDATE tenders SET org_id = $1 WHERE client_id = $2 AND org_id IS NULL`, orgID, userID); err != nil {
+		return fmt.Errorf("failed to transfer tenders: %w", err)
+	}
+	if _, err := tx.Exec(`UPDATE bids SET org_id = $1 WHERE contractor_id = $2 AND org_id IS NULL`, orgID, userID); err != nil {
+		return fmt.Errorf("failed to transfer bids: %w", err)
+	}
+	return nil
+}
+
+func replaceContacts(tx *sql.Tx, org *model.Organization) error {
+	if _, err := tx.Exec(`DELETE FROM organization_contacts WHERE org_id = $1`, org.ID); err != nil {
+		return fmt.Errorf("failed to replace contacts: %w", err)
+	}
+
+	for i, c := range org.Contacts {
+		err := tx.QueryRow(`
+            INSERT INTO organization_contacts (org_id, name, email, phone, position)
+            VALUES ($1, $2, $3, $4, $5)
+            RETURNING id`, org.ID, c.Name, c.Email, c.Phone, c.Position).Scan(&org.Contacts[i].ID)
+		if err != nil {
+			return fmt.Errorf("failed to save contact: %w", err)
+		}
+	}
+	return nil
+}
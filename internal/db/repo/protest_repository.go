@@ -0,0 +1,220 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"tender-managment/internal/model"
+)
+
+type ProtestRepository struct {
+	db *sql.DB
+}
+
+func NewProtestRepository(db *sql.DB) *ProtestRepository {
+	return &ProtestRepository{db: db}
+}
+
+const protestColumns = `id, tender_id, contractor_id, reasons, status, COALESCE(resolution_note, ''), resolved_at, created_at`
+
+func scanProtest(row rowScanner, p *model.Protest) error {
+	return row.Scan(&p.ID, &p.TenderID, &p.ContractorID, &p.Reasons, &p.Status, &p.ResolutionNote, &p.ResolvedAt, &p.CreatedAt)
+}
+
+func (r *ProtestRepository) CreateProtest(p *model.Protest) (*model.Protest, error) {
+	query := `
+        INSERT INTO award_protests (tender_id, contractor_id, reasons)
+        VALUES ($1, $2, $3)
+        RETURNING ` + protestColumns
+
+	if err := scanProtest(r.db.QueryRow(query, p.TenderID, p.ContractorID, p.Reasons), p); err != nil {
+		return nil, fmt.Errorf("failed to create protest: %w", err)
+	}
+
+	return p, nil
+}
+
+func (r *ProtestRepository) GetProtestByID(id int) (*model.Protest, error) {
+	var p model.Protest
+	err := scanProtest(r.db.QueryRow(`SELECT `+protestColumns+` FROM award_protests WHERE id = $1`, id), &p)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("protest not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	p.Documents, err = r.GetDocuments(p.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (r *ProtestRepository) GetProtestsByTenderID(tenderID int) ([]model.Protest, error) {
+	return r.queryProtests(`SELECT `+protestColumns+` FROM award_protests WHERE tender_id = $1 ORDER BY id`, tenderID)
+}
+
+func (r *ProtestRepository) GetProtestsByContractorID(contractorID int) ([]model.Protest, error) {
+	return r.queryProtests(`SELECT `+protestColumns+` FROM award_protests WHERE contractor_id = $1 ORDER BY id DESC`, contractorID)
+}
+
+func (r *ProtestRepository) queryProtests(query string, args ...interface{}) ([]model.Protest, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch protests: %w", err)
+	}
+	defer rows.Close()
+
+	protests := []model.Protest{}
+	for rows.Next() {
+		var p model.Protest
+		if err := scanProtest(rows, &p); err != nil {
+			return nil, fmt.Errorf("failed to scan protest: %w", err)
+		}
+		protests = append(protests, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range protests {
+		protests[i].Documents, err = r.GetDocuments(protests[i].ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return protests, nil
+}
+
+func (r *ProtestRepository) HasPendingProtest(tenderID, contractorID int) (bool, error) {
+	query := `SELECT EXISTS(SELECT 1 FROM award_protests WHERE tender_id = $1 AND contractor_id = $2 AND status = 'pending')`
+
+	var exists bool
+	if err := r.db.QueryRow(query, tenderID, contractorID).Scan(&exists); err != nil {
+		return false, err
+	}
+	return exists, nil
+}
+
+func (r *ProtestRepository) AddDocument(doc *model.ProtestDocument) (*model.ProtestDocument, error) {
+	query := `
+        INSERT INTO protest_documents (protest_id, file_name, storage_key, mime_type, size, sha256)
+        VALUES ($1, $2, $3, $4, $5, $6)
+        RETURNING id, created_at`
+
+	err := r.db.QueryRow(query, doc.ProtestID, doc.FileName, doc.StorageKey, doc.MimeType, doc.Size, doc.SHA256).
+		Scan(&doc.ID, &doc.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to store protest document: %w", err)
+	}
+
+	return doc, nil
+}
+
+func (r *ProtestRepository) GetDocuments(protestID int) ([]model.ProtestDocument, error) {
+	query := `
+        SELECT id, protest_id, file_name, storage_key, mime_type, size, sha256, created_at
+        FROM protest_documents
+        WHERE protest_id = $1
+        ORDER BY id`
+
+	rows, err := r.db.Query(query, protestID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var docs []model.ProtestDocument
+	for rows.Next() {
+		var doc model.ProtestDocument
+		if err := rows.Scan(&doc.ID, &doc.ProtestID, &doc.FileName, &doc.StorageKey, &doc.MimeType, &doc.Size, &doc.SHA256, &doc.CreatedAt); err != nil {
+			return nil, err
+		}
+		docs = append(docs, doc)
+	}
+
+	return docs, rows.Err()
+}
+
+func (r *ProtestRepository) GetDocumentByID(id int) (*model.ProtestDocument, error) {
+	query := `
+        SELECT id, protest_id, file_name, storage_key, mime_type, size, sha256, created_at
+        FROM protest_documents
+        WHERE id = $1`
+
+	var doc model.ProtestDocument
+	err := r.db.QueryRow(query, id).Scan(&doc.ID, &doc.ProtestID, &doc.FileName, &doc.StorageKey, &doc.MimeType, &doc.Size, &doc.SHA256, &doc.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("document not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &doc, nil
+}
+
+// UpholdAward dismisses a pending protest and leaves the award in place.
+func (r *ProtestRepository) UpholdAward(protestID int, note string) error {
+	query := `
+        UPDATE award_protests
+        SET status = 'award_upheld', resolution_note = $2, resolved_at = CURRENT_TIMESTAMP
+        WHERE id = $1 AND status = 'pending'`
+
+	result, err := r.db.Exec(query, protestID, note)
+	if err != nil {
+		return err
+	}
+	if rows, err := result.RowsAffected(); err != nil || rows == 0 {
+		return errors.New("protest already resolved")
+	}
+	return nil
+}
+
+// ReverseAward withdraws the tender's award. Awarded bids and lots go back to
+// pending and open, the tender returns to closed so it can be awarded again,
+// and every pending protest against the award is resolved as reversed.
+func (r *ProtestRepository) ReverseAward(tenderID int, note string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to reverse award: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+        UPDATE tenders
+        SET status = 'closed', standstill_ends_at = NULL, updated_at = CURRENT_TIMESTAMP
+        WHERE id = $1 AND status = 'awarded' AND award_finalized_at IS NULL`, tenderID)
+	if err != nil {
+		return fmt.Errorf("failed to reverse award: %w", err)
+	}
+	if rows, err := result.RowsAffected(); err != nil || rows == 0 {
+		return errors.New("award is already final")
+	}
+
+	statements := []string{
+		`UPDATE bid_lots SET status = 'pending'
+         WHERE lot_id IN (SELECT id FROM tender_lots WHERE tender_id = $1 AND status = 'awarded')`,
+		`UPDATE tender_lots SET status = 'open', awarded_bid_id = NULL, updated_at = CURRENT_TIMESTAMP
+         WHERE tender_id = $1 AND status = 'awarded'`,
+		`UPDATE bids SET status = 'pending', updated_at = CURRENT_TIMESTAMP
+         WHERE tender_id = $1 AND status = 'awarded'`,
+	}
+	for _, statement := range statements {
+		if _, err := tx.Exec(statement, tenderID); err != nil {
+			return fmt.Errorf("failed to reverse award: %w", err)
+		}
+	}
+
+	_, err = tx.Exec(`
+        UPDATE award_protests
+        SET status = 'award_reversed', resolution_note = $2, resolved_at = CURRENT_TIMESTAMP
+        WHERE tender_id = $1 AND status = 'pending'`, tenderID, note)
+	if err != nil {
+		return fmt.Errorf("failed to resolve protests: %w", err)
+	}
+
+	return tx.Commit()
+}
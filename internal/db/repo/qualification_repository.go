@@ -0,0 +1,249 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"tender-managment/internal/model"
+)
+
+type QualificationRepository struct {
+	db *sql.DB
+}
+
+func NewQualificationRepository(db *sql.DB) *QualificationRepository {
+	return &QualificationRepository{db: db}
+}
+
+const qualificationColumns = `id, contractor_id, type, code, amount, currency, expires_at, status, review_note,
+        reviewed_by, reviewed_at, file_name, storage_key, mime_type, size, sha256, created_at`
+
+func scanQualification(row rowScanner, q *model.Qualification) error {
+	var (
+		amount     *model.Amount
+		currency   sql.NullString
+		reviewNote sql.NullString
+	)
+
+	err := row.Scan(
+		&q.ID,
+		&q.ContractorID,
+		&q.Type,
+		&q.Code,
+		&amount,
+		&currency,
+		&q.ExpiresAt,
+		&q.Status,
+		&reviewNote,
+		&q.ReviewedBy,
+		&q.ReviewedAt,
+		&q.FileName,
+		&q.StorageKey,
+		&q.MimeType,
+		&q.Size,
+		&q.SHA256,
+		&q.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	if amount != nil {
+		q.Turnover = &model.Money{Amount: *amount, Currency: currency.String}
+	}
+	q.ReviewNote = reviewNote.String
+
+	return nil
+}
+
+func (r *QualificationRepository) CreateQualification(q *model.Qualification) (*model.Qualification, error) {
+	query := `
+        INSERT INTO contractor_qualifications
+            (contractor_id, type, code, amount, currency, expires_at, file_name, storage_key, mime_type, size, sha256)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+        RETURNING id, status, created_at`
+
+	var (
+		amount   interface{}
+		currency interface{}
+	)
+	if q.Turnover != nil {
+		amount = q.Turnover.Amount
+		currency = q.Turnover.Currency
+	}
+
+	err := r.db.QueryRow(
+		query,
+		q.ContractorID,
+		q.Type,
+		q.Code,
+		amount,
+		currency,
+		q.ExpiresAt,
+		q.FileName,
+		q.StorageKey,
+		q.MimeType,
+		q.Size,
+		q.SHA256,
+	).Scan(&q.ID, &q.Status, &q.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create qualification: %w", err)
+	}
+
+	return q, nil
+}
+
+func (r *QualificationRepository) GetQualificationByID(id int) (*model.Qualification, error) {
+	query := `SELECT ` + qualificationColumns + ` FROM contractor_qualifications WHERE id = $1`
+
+	var q model.Qualification
+	err := scanQualification(r.db.QueryRow(query, id), &q)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("qualification not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &q, nil
+}
+
+func (r *QualificationRepository) GetQualificationsByContractorID(contractorID int) ([]model.Qualification, error) {
+	query := `SELECT ` + qualificationColumns + ` FROM contractor_qualifications
+        WHERE contractor_id = $1
+        ORDER BY created_at DESC`
+
+	return r.queryQualifications(query, contractorID)
+}
+
+// GetReviewQueue returns pending qualifications matching a requirement of one
+// of the client's tenders, oldest first.
+func (r *QualificationRepository) GetReviewQueue(clientID int) ([]model.Qualification, error) {
+	query := `SELECT ` + qualificationColumns + ` FROM contractor_qualifications q
+        WHERE q.status = 'pending'
+          AND EXISTS(SELECT 1
+                     FROM tender_requirements tr
+                              JOIN tenders t ON t.id = tr.tender_id
+                     WHERE t.client_id = $1 AND tr.type = q.type AND tr.code = q.code)
+        ORDER BY q.created_at`
+
+	return r.queryQualifications(query, clientID)
+}
+
+// IsReviewableBy reports whether the qualification matches a requirement of
+// one of the client's tenders.
+func (r *QualificationRepository) IsReviewableBy(qualificationID, clientID int) (bool, error) {
+	query := `
+        SELECT EXISTS(SELECT 1
+                      FROM contractor_qualifications q
+                               JOIN tender_requirements tr ON tr.type = q.type AND tr.code = q.code
+                               JOIN tenders t ON t.id = tr.tender_id
+                      WHERE q.id = $1 AND t.client_id = $2)`
+
+	var reviewable bool
+	if err := r.db.QueryRow(query, qualificationID, clientID).Scan(&reviewable); err != nil {
+		return false, err
+	}
+	return reviewable, nil
+}
+
+func (r *QualificationRepository) ReviewQualification(id, reviewerID int, status, note string) error {
+	query := `
+        UPDATE contractor_qualifications
+        SET status = $1, review_note = $2, reviewed_by = $3, reviewed_at = CURRENT_TIMESTAMP
+        WHERE id = $4 AND status = 'pending'`
+
+	result, err := r.db.Exec(query, status, note, reviewerID, id)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return errors.New("qualification already reviewed")
+	}
+
+	return nil
+}
+
+func (r *QualificationRepository) DeleteQualification(id int) error {
+	_, err := r.db.Exec(`DELETE FROM contractor_qualifications WHERE id = $1`, id)
+	return err
+}
+
+func (r *QualificationRepository) queryQualifications(query string, args ...interface{}) ([]model.Qualification, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var qualifications []model.Qualification
+	for rows.Next() {
+		var q model.Qualification
+		if err := scanQualification(rows, &q); err != nil {
+			return nil, err
+		}
+		qualifications = append(qualifications, q)
+	}
+
+	return qualifications, rows.Err()
+}
+
+// ReplaceRequirements swaps the tender's requirement list in one transaction.
+func (r *QualificationRepository) ReplaceRequirements(tenderID int, requirements []model.Requirement) ([]model.Requirement, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM tender_requirements WHERE tender_id = $1`, tenderID); err != nil {
+		return nil, err
+	}
+
+	for i := range requirements {
+		requirements[i].TenderID = tenderID
+		err := tx.QueryRow(`
+            INSERT INTO tender_requirements (tender_id, type, code, min_amount)
+            VALUES ($1, $2, $3, $4)
+            RETURNING id`,
+			tenderID, requirements[i].Type, requirements[i].Code, requirements[i].MinAmount,
+		).Scan(&requirements[i].ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to save requirement: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return requirements, nil
+}
+
+func (r *QualificationRepository) GetRequirements(tenderID int) ([]model.Requirement, error) {
+	rows, err := r.db.Query(`
+        SELECT id, tender_id, type, code, min_amount
+        FROM tender_requirements
+        WHERE tender_id = $1
+        ORDER BY type, code`, tenderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var requirements []model.Requirement
+	for rows.Next() {
+		var requirement model.Requirement
+		if err := rows.Scan(&requirement.ID, &requirement.TenderID, &requirement.Type, &requirement.Code, &requirement.MinAmount); err != nil {
+			return nil, err
+		}
+		requirements = append(requirements, requirement)
+	}
+
+	return requirements, rows.Err()
+}
@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"tender-managment/internal/model"
+)
+
+type RefreshTokenRepository struct {
+	db *sql.DB
+}
+
+func NewRefreshTokenRepository(db *sql.DB) *RefreshTokenRepository {
+	return &RefreshTokenRepository{db: db}
+}
+
+const refreshTokenColumns = `id, user_id, token_hash, access_jti, two_factor, expires_at, revoked_at, created_at`
+
+func scanRefreshToken(row rowScanner, t *model.RefreshToken) error {
+	return row.Scan(&t.ID, &t.UserID, &t.TokenHash, &t.AccessJTI, &t.TwoFactor, &t.ExpiresAt, &t.RevokedAt, &t.CreatedAt)
+}
+
+func (r *RefreshTokenRepository) CreateRefreshToken(token *model.RefreshToken) error {
+	query := `
+        INSERT INTO refresh_tokens (user_id, token_hash, access_jti, two_factor, expires_at)
+        VALUES ($1, $2, $3, $4, $5)`
+
+	if _, err := r.db.Exec(query, token.UserID, token.TokenHash, token.AccessJTI, token.TwoFactor, token.ExpiresAt); err != nil {
+		return fmt.Errorf("failed to store refresh token: %w", err)
+	}
+	return nil
+}
+
+func (r *RefreshTokenRepository) GetRefreshTokenByHash(hash string) (*model.RefreshToken, error) {
+	var token model.RefreshToken
+	err := scanRefreshToken(r.db.QueryRow(`SELECT `+refreshTokenColumns+` FROM refresh_tokens WHERE token_hash = $1`, hash), &token)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("refresh token not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// Rotate revokes a refresh token and stores the one replacing it. It fails if
+// the old token was revoked in the meantime, so a token can only be used once.
+func (r *RefreshTokenRepository) Rotate(id int, next *model.RefreshToken) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to rotate refresh token: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`UPDATE refresh_tokens SET revoked_at = NOW() WHERE id = $1 AND revoked_at IS NULL`, id)
+	if err != nil {
+		return fmt.Errorf("failed to rotate refresh token: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return errors.New("refresh token already used")
+	}
+
+	query := `
+        INSERT INTO refresh_tokens (user_id, token_hash, access_jti, two_factor, expires_at)
+        VALUES ($1, $2, $3, $4, $5)`
+	if _, err := tx.Exec(query, next.UserID, next.TokenHash, next.AccessJTI, next.TwoFactor, next.ExpiresAt); err != nil {
+		return fmt.Errorf("failed to rotate refresh token: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to rotate refresh token: %w", err)
+	}
+	return nil
+}
+
+// RevokeSession revokes the refresh token issued alongside the access token.
+func (r *RefreshTokenRepository) RevokeSession(userID int, accessJTI string) error {
+	_, err := r.db.Exec(`
+        UPDATE refresh_tokens SET revoked_at = NOW()
+        WHERE user_id = $1 AND access_jti = $2 AND revoked_at IS NULL`, userID, accessJTI)
+	if err != nil {
+		return fmt.Errorf("failed to revoke refresh token: %w", err)
+	}
+	return nil
+}
+
+// RevokeAll revokes every live refresh token of the user except the one
+// issued alongside exceptJTI, and returns the revoked tokens.
+func (r *RefreshTokenRepository) RevokeAll(userID int, exceptJTI string) ([]model.RefreshToken, error) {
+	query := `
+        UPDATE refresh_tokens SET revoked_at = NOW()
+        WHERE user_id = $1 AND access_jti <> $2 AND revoked_at IS NULL AND expires_at > NOW()
+        RETURNING ` + refreshTokenColumns
+
+	rows, err := r.db.Query(query, userID, exceptJTI)
+	if err != nil {
+		return nil, fmt.Errorf("failed to revoke refresh tokens: %w", err)
+	}
+	defer rows.Close()
+
+	var tokens []model.RefreshToken
+	for rows.Next() {
+		var token model.RefreshToken
+		if err := scanRefreshToken(rows, &token); err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, token)
+	}
+	return tokens, rows.Err()
+}
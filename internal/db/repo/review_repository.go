@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"tender-managment/internal/model"
+)
+
+type ReviewRepository struct {
+	db *sql.DB
+}
+
+func NewReviewRepository(db *sql.DB) *ReviewRepository {
+	return &ReviewRepository{db: db}
+}
+
+const reviewColumns = `id, contract_id, reviewer_id, reviewee_id, quality, timeliness, communication, comment, created_at, updated_at`
+
+func scanReview(row rowScanner, r *model.Review) error {
+	return row.Scan(
+		&r.ID,
+		&r.ContractID,
+		&r.ReviewerID,
+		&r.RevieweeID,
+		&r.Quality,
+		&r.Timeliness,
+		&r.Communication,
+		&r.Comment,
+		&r.CreatedAt,
+		&r.UpdatedAt,
+	)
+}
+
+// CreateReview stores a review. Each party can review a contract only once.
+func (r *ReviewRepository) CreateReview(review *model.Review) (*model.Review, error) {
+	query := `
+        INSERT INTO reviews (contract_id, reviewer_id, reviewee_id, quality, timeliness, communication, comment)
+        VALUES ($1, $2, $3, $4, $5, $6, $7)
+        ON CONFLICT (contract_id, reviewer_id) DO NOTHING
+        RETURNING ` + reviewColumns
+
+	err := scanReview(r.db.QueryRow(query,
+		review.ContractID, review.ReviewerID, review.RevieweeID,
+		review.Quality, review.Timeliness, review.Communication, review.Comment,
+	), review)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("you have already reviewed this contract")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create review: %w", err)
+	}
+
+	return review, nil
+}
+
+func (r *ReviewRepository) GetReviewByID(id int) (*model.Review, error) {
+	var review model.Review
+	err := scanReview(r.db.QueryRow(`SELECT `+reviewColumns+` FROM reviews WHERE id = $1`, id), &review)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("review not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &review, nil
+}
+
+func (r *ReviewRepository) UpdateReview(review *model.Review) (*model.Review, error) {
+	query := `
+        UPDATE reviews
+        SET quality = $2, timeliness = $3, communication = $4, comment = $5, updated_at = CURRENT_TIMESTAMP
+        WHERE id = $1
+        RETURNING ` + reviewColumns
+
+	err := scanReview(r.db.QueryRow(query,
+		review.ID, review.Quality, review.Timeliness, review.Communication, review.Comment,
+	), review)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("review not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update review: %w", err)
+	}
+
+	return review, nil
+}
+
+// GetReviewsByRevieweeID lists the reviews a user has received, newest first.
+func (r *ReviewRepository) GetReviewsByRevieweeID(revieweeID int) ([]model.Review, error) {
+	rows, err := r.db.Query(`SELECT `+reviewColumns+` FROM reviews WHERE reviewee_id = $1 ORDER BY id DESC`, revieweeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch reviews: %w", err)
+	}
+	defer rows.Close()
+
+	reviews := []model.Review{}
+	for rows.Next() {
+		var review model.Review
+		if err := scanReview(rows, &review); err != nil {
+			return nil, fmt.Errorf("failed to scan review: %w", err)
+		}
+		reviews = append(reviews, review)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return reviews, nil
+}
+
+// GetReputation averages the scores the user has received, rounded to two
+// decimals. Users without reviews get a zero reputation.
+func (r *ReviewRepository) GetReputation(userID int) (*model.Reputation, error) {
+	query := `
+        SELECT COUNT(*),
+               COALESCE(ROUND(AVG(quality), 2), 0)::float8,
+               COALESCE(ROUND(AVG(timeliness), 2), 0)::float8,
+               COALESCE(ROUND(AVG(communication), 2), 0)::float8,
+               COALESCE(ROUND(AVG((quality + timeliness + communication) / 3.0), 2), 0)::float8
+        FROM reviews
+        WHERE reviewee_id = $1`
+
+	rep := model.Reputation{UserID: userID}
+	err := r.db.QueryRow(query, userID).Scan(&rep.Reviews, &rep.Quality, &rep.Timeliness, &rep.Communication, &rep.Overall)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute reputation: %w", err)
+	}
+
+	return &rep, nil
+}
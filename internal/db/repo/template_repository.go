@@ -0,0 +1,184 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"tender-managment/internal/model"
+)
+
+type TemplateRepository struct {
+	db *sql.DB
+}
+
+func NewTemplateRepository(db *sql.DB) *TemplateRepository {
+	return &TemplateRepository{db: db}
+}
+
+// CreateTemplateFromTender copies the tender details and its tender-level
+// attachments into a new template in a single transaction.
+func (r *TemplateRepository) CreateTemplateFromTender(tenderID int, name string) (*model.TenderTemplate, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var template model.TenderTemplate
+	err = tx.QueryRow(`
+		INSERT INTO tender_templates (client_id, name, title, description, evaluation_criteria, budget, currency)
+		SELECT client_id, $2, title, COALESCE(description, ''), evaluation_criteria, budget, currency
+		FROM tenders
+		WHERE id = $1
+		RETURNING id, client_id, name, title, description, evaluation_criteria, budget, currency, created_at`,
+		tenderID, name,
+	).Scan(&template.ID, &template.ClientID, &template.Name, &template.Title, &template.Description,
+		&template.EvaluationCriteria, &template.Budget.Amount, &template.Budget.Currency, &template.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("tender not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create template: %w", err)
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO tender_template_attachments (template_id, file_name, storage_key, mime_type, size, sha256)
+		SELECT $1, file_name, storage_key, mime_type, size, sha256
+		FROM attachments
+		WHERE tender_id = $2 AND bid_id IS NULL`,
+		template.ID, tenderID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy attachments: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	template.Attachments, err = r.getTemplateAttachments(template.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &template, nil
+}
+
+func (r *TemplateRepository) GetTemplateByID(id int) (*model.TenderTemplate, error) {
+	query := `
+		SELECT id, client_id, name, title, description, evaluation_criteria, budget, currency, created_at
+		FROM tender_templates
+		WHERE id = $1`
+
+	var template model.TenderTemplate
+	err := r.db.QueryRow(query, id).Scan(&template.ID, &template.ClientID, &template.Name, &template.Title,
+		&template.Description, &template.EvaluationCriteria, &template.Budget.Amount, &template.Budget.Currency, &template.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("template not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	template.Attachments, err = r.getTemplateAttachments(template.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &template, nil
+}
+
+func (r *TemplateRepository) GetTemplatesByClientID(clientID int) ([]model.TenderTemplate, error) {
+	query := `
+		SELECT id, client_id, name, title, description, evaluation_criteria, budget, currency, created_at
+		FROM tender_templates
+		WHERE client_id = $1
+		ORDER BY name`
+
+	rows, err := r.db.Query(query, clientID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch templates: %w", err)
+	}
+	defer rows.Close()
+
+	var templates []model.TenderTemplate
+	for rows.Next() {
+		var template model.TenderTemplate
+		if err := rows.Scan(&template.ID, &template.ClientID, &template.Name, &template.Title,
+			&template.Description, &template.EvaluationCriteria, &template.Budget.Amount, &template.Budget.Currency, &template.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan template: %w", err)
+		}
+		templates = append(templates, template)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error reading rows: %w", err)
+	}
+
+	for i := range templates {
+		templates[i].Attachments, err = r.getTemplateAttachments(templates[i].ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return templates, nil
+}
+
+// DeleteTemplate removes the template and returns the storage keys its
+// attachments pointed at, so the caller can clean up unreferenced files.
+func (r *TemplateRepository) DeleteTemplate(id int) ([]string, error) {
+	rows, err := r.db.Query(`SELECT storage_key FROM tender_template_attachments WHERE template_id = $1`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch template attachments: %w", err)
+	}
+	var keys []string
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	rows.Close()
+
+	result, err := r.db.Exec(`DELETE FROM tender_templates WHERE id = $1`, id)
+	if err != nil {
+		return nil, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if affected == 0 {
+		return nil, errors.New("template not found")
+	}
+
+	return keys, nil
+}
+
+func (r *TemplateRepository) getTemplateAttachments(templateID int) ([]model.TemplateAttachment, error) {
+	query := `
+		SELECT id, file_name, mime_type, size, sha256
+		FROM tender_template_attachments
+		WHERE template_id = $1
+		ORDER BY id`
+
+	rows, err := r.db.Query(query, templateID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch template attachments: %w", err)
+	}
+	defer rows.Close()
+
+	attachments := []model.TemplateAttachment{}
+	for rows.Next() {
+		var attachment model.TemplateAttachment
+		if err := rows.Scan(&attachment.ID, &attachment.FileName, &attachment.MimeType, &attachment.Size, &attachment.SHA256); err != nil {
+			return nil, fmt.Errorf("failed to scan template attachment: %w", err)
+		}
+		attachments = append(attachments, attachment)
+	}
+
+	return attachments, rows.Err()
+}
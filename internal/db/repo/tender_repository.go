@@ -5,12 +5,49 @@ import (
 	"errors"
 	"fmt"
 	"tender-managment/internal/model"
+	"time"
 )
 
 type TenderRepository struct {
 	db *sql.DB
 }
 
+const tenderColumns = `id, client_id, COALESCE(org_id, 0), title, description, evaluation_criteria, deadline, budget, currency, status, visibility, stage, eoi_deadline, publish_at, standstill_ends_at, award_finalized_at, created_at, updated_at`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanTender(row rowScanner, tender *model.Tender) error {
+	return row.Scan(
+		&tender.ID,
+		&tender.ClientID,
+		&tender.OrgID,
+		&tender.Title,
+		&tender.Description,
+		&tender.EvaluationCriteria,
+		&tender.Deadline,
+		&tender.Budget.Amount,
+		&tender.Budget.Currency,
+		&tender.Status,
+		&tender.Visibility,
+		&tender.Stage,
+		&tender.EOIDeadline,
+		&tender.PublishAt,
+		&tender.StandstillEndsAt,
+		&tender.AwardFinalizedAt,
+		&tender.CreatedAt,
+		&tender.UpdatedAt,
+	)
+}
+
+// ownedByUser is the condition matching rows owned by the user in $1: rows of
+// the user's organization, or rows they created outside of one.
+func ownedByUser(table, ownerColumn string) string {
+	return fmt.Sprintf(`CASE WHEN %[1]s.org_id IS NULL THEN %[1]s.%[2]s = $1
+            ELSE %[1]s.org_id = (SELECT org_id FROM users WHERE id = $1) END`, table, ownerColumn)
+}
+
 func NewTenderRepository(db *sql.DB) *TenderRepository {
 	return &TenderRepository{db: db}
 }
@@ -28,11 +65,11 @@ func (r *TenderRepository) GetTendersByClientID(clientID int) ([]model.GetTender
 
 	query := `
         SELECT 
-            t.id, t.title, t.description, t.deadline, t.budget, 
+            t.id, t.title, t.description, t.deadline, t.budget, t.currency,
             t.status, t.created_at, 
             (SELECT COUNT(*) FROM bids b WHERE b.tender_id = t.id) AS bids_count
         FROM tenders t
-        WHERE t.client_id = $1
+        WHERE ` + ownedByUser("t", "client_id") + `
         ORDER BY t.created_at DESC`
 
 	rows, err := r.db.Query(query, clientID)
@@ -45,7 +82,7 @@ func (r *TenderRepository) GetTendersByClientID(clientID int) ([]model.GetTender
 		var tender model.GetTender
 		if err := rows.Scan(
 			&tender.ID, &tender.Title, &tender.Description, &tender.Deadline,
-			&tender.Budget, &tender.Status, &tender.CreatedAt, &tender.BidsCount,
+			&tender.Budget.Amount, &tender.Budget.Currency, &tender.Status, &tender.CreatedAt, &tender.BidsCount,
 		); err != nil {
 			return nil, fmt.Errorf("failed to scan tender row: %w", err)
 		}
@@ -55,35 +92,150 @@ func (r *TenderRepository) GetTendersByClientID(clientID int) ([]model.GetTender
 	return tenders, nil
 }
 
+type rowQuerier interface {
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
 func (r *TenderRepository) CreateTender(tender *model.Tender) (*model.Tender, error) {
+	if err := insertTender(r.db, tender); err != nil {
+		return nil, err
+	}
+
+	return tender, nil
+}
+
+func insertTender(q rowQuerier, tender *model.Tender) error {
 	query := `
-        INSERT INTO tenders (client_id, title, description, deadline, budget, status , attachment_path)
-        VALUES ($1, $2, $3, $4, $5, $6, $7)
+        INSERT INTO tenders (client_id, title, description, evaluation_criteria, deadline, budget, currency, status, visibility, stage, publish_at, org_id)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, NULLIF($12, 0))
         RETURNING id, created_at, updated_at`
 
-	err := r.db.QueryRow(
+	return q.QueryRow(
 		query,
 		tender.ClientID,
 		tender.Title,
 		tender.Description,
+		tender.EvaluationCriteria,
 		tender.Deadline,
-		tender.Budget,
+		tender.Budget.Amount,
+		tender.Budget.Currency,
 		tender.Status,
-		tender.Attachment,
+		tender.Visibility,
+		tender.Stage,
+		tender.PublishAt,
+		tender.OrgID,
 	).Scan(&tender.ID, &tender.CreatedAt, &tender.UpdatedAt)
+}
 
+// CloneTender creates tender as a copy of the source tender's lots, bill of
+// quantities, invitations, requirements and tender-level attachments. It runs
+// in one transaction so a failed copy leaves no half-cloned draft behind.
+// Stored files are shared, only the attachment rows are duplicated.
+func (r *TenderRepository) CloneTender(sourceID int, tender *model.Tender) (*model.Tender, error) {
+	tx, err := r.db.Begin()
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to clone tender: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := insertTender(tx, tender); err != nil {
+		return nil, fmt.Errorf("failed to create tender: %w", err)
+	}
+
+	// Lots go first so bill of quantities items can be linked to the lot
+	// with the same number on the new tender.
+	_, err = tx.Exec(`
+        INSERT INTO tender_lots (tender_id, lot_number, title, description, budget)
+        SELECT $2, lot_number, title, description, budget
+        FROM tender_lots
+        WHERE tender_id = $1`, sourceID, tender.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy lots: %w", err)
+	}
+
+	_, err = tx.Exec(`
+        INSERT INTO tender_boq_items (tender_id, lot_id, item_number, description, unit, quantity)
+        SELECT $2, nl.id, b.item_number, b.description, b.unit, b.quantity
+        FROM tender_boq_items b
+        LEFT JOIN tender_lots ol ON ol.id = b.lot_id
+        LEFT JOIN tender_lots nl ON nl.tender_id = $2 AND nl.lot_number = ol.lot_number
+        WHERE b.tender_id = $1`, sourceID, tender.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy bill of quantities: %w", err)
+	}
+
+	_, err = tx.Exec(`
+        INSERT INTO tender_invitations (tender_id, contractor_id)
+        SELECT $2, contractor_id
+        FROM tender_invitations
+        WHERE tender_id = $1`, sourceID, tender.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy invitations: %w", err)
+	}
+
+	_, err = tx.Exec(`
+        INSERT INTO tender_requirements (tender_id, type, code, min_amount)
+        SELECT $2, type, code, min_amount
+        FROM tender_requirements
+        WHERE tender_id = $1`, sourceID, tender.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy requirements: %w", err)
+	}
+
+	_, err = tx.Exec(`
+        INSERT INTO attachments (tender_id, uploaded_by, file_name, storage_key, mime_type, size, sha256)
+        SELECT $2, $3, file_name, storage_key, mime_type, size, sha256
+        FROM attachments
+        WHERE tender_id = $1 AND bid_id IS NULL`, sourceID, tender.ID, tender.ClientID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy attachments: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to clone tender: %w", err)
 	}
 
 	return tender, nil
 }
 
-func (r *TenderRepository) ListTendersByClientID(clientID int) ([]model.Tender, error) {
-	query := `
-        SELECT id, client_id, title, description, deadline, budget, status, created_at, updated_at
-        FROM tenders
-        WHERE client_id = $1`
+// CreateTenderFromTemplate creates tender together with the template's files
+// in one transaction.
+func (r *TenderRepository) CreateTenderFromTemplate(templateID int, tender *model.Tender) (*model.Tender, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tender: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := insertTender(tx, tender); err != nil {
+		return nil, fmt.Errorf("failed to create tender: %w", err)
+	}
+
+	_, err = tx.Exec(`
+        INSERT INTO attachments (tender_id, uploaded_by, file_name, storage_key, mime_type, size, sha256)
+        SELECT $2, $3, file_name, storage_key, mime_type, size, sha256
+        FROM tender_template_attachments
+        WHERE template_id = $1`, templateID, tender.ID, tender.ClientID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy attachments: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to create tender: %w", err)
+	}
+
+	return tender, nil
+}
+
+// ListTendersByClientID lists a client's tenders, including those of their
+// organization. Unless includeHidden is set, drafts and invite-only tenders are
+// left out.
+func (r *TenderRepository) ListTendersByClientID(clientID int, includeHidden bool) ([]model.Tender, error) {
+	query := `SELECT ` + tenderColumns + ` FROM tenders WHERE ` + ownedByUser("tenders", "client_id")
+
+	if !includeHidden {
+		query += " AND status <> 'draft' AND visibility = 'public'"
+	}
 
 	rows, err := r.db.Query(query, clientID)
 	if err != nil {
@@ -94,18 +246,7 @@ func (r *TenderRepository) ListTendersByClientID(clientID int) ([]model.Tender,
 	var tenders []model.Tender
 	for rows.Next() {
 		var tender model.Tender
-		err := rows.Scan(
-			&tender.ID,
-			&tender.ClientID,
-			&tender.Title,
-			&tender.Description,
-			&tender.Deadline,
-			&tender.Budget,
-			&tender.Status,
-			&tender.CreatedAt,
-			&tender.UpdatedAt,
-		)
-		if err != nil {
+		if err := scanTender(rows, &tender); err != nil {
 			return nil, err
 		}
 		tenders = append(tenders, tender)
@@ -114,24 +255,24 @@ func (r *TenderRepository) ListTendersByClientID(clientID int) ([]model.Tender,
 	return tenders, nil
 }
 
-func (r *TenderRepository) GetTenderByID(tenderID int) (*model.Tender, error) {
+// IsManagedBy reports whether the user belongs to the organization that owns
+// the tender.
+func (r *TenderRepository) IsManagedBy(tenderID, userID int) (bool, error) {
 	query := `
-        SELECT id, client_id, title, description, deadline, budget, status, created_at, updated_at
-        FROM tenders
-        WHERE id = $1`
+        SELECT EXISTS(SELECT 1 FROM tenders t JOIN users u ON u.org_id = t.org_id WHERE t.id = $1 AND u.id = $2)`
+
+	var managed bool
+	if err := r.db.QueryRow(query, tenderID, userID).Scan(&managed); err != nil {
+		return false, fmt.Errorf("failed to check tender ownership: %w", err)
+	}
+	return managed, nil
+}
+
+func (r *TenderRepository) GetTenderByID(tenderID int) (*model.Tender, error) {
+	query := `SELECT ` + tenderColumns + ` FROM tenders WHERE id = $1`
 
 	var tender model.Tender
-	err := r.db.QueryRow(query, tenderID).Scan(
-		&tender.ID,
-		&tender.ClientID,
-		&tender.Title,
-		&tender.Description,
-		&tender.Deadline,
-		&tender.Budget,
-		&tender.Status,
-		&tender.CreatedAt,
-		&tender.UpdatedAt,
-	)
+	err := scanTender(r.db.QueryRow(query, tenderID), &tender)
 
 	if err == sql.ErrNoRows {
 		return nil, errors.New("tender not found")
@@ -165,6 +306,79 @@ func (r *TenderRepository) UpdateTenderStatus(tenderID int, status string) error
 	return nil
 }
 
+func (r *TenderRepository) UpdateDraftTender(tender *model.Tender) error {
+	query := `
+        UPDATE tenders
+        SET title = $1, description = $2, evaluation_criteria = $3, deadline = $4, budget = $5, currency = $6,
+            visibility = $7, publish_at = $8, updated_at = CURRENT_TIMESTAMP
+        WHERE id = $9 AND status = 'draft'
+        RETURNING updated_at`
+
+	err := r.db.QueryRow(
+		query,
+		tender.Title,
+		tender.Description,
+		tender.EvaluationCriteria,
+		tender.Deadline,
+		tender.Budget.Amount,
+		tender.Budget.Currency,
+		tender.Visibility,
+		tender.PublishAt,
+		tender.ID,
+	).Scan(&tender.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return errors.New("tender not found")
+	}
+
+	return err
+}
+
+// PublishTender opens a draft tender. It returns false when the tender was no
+// longer a draft, so concurrent publishers notify contractors only once.
+func (r *TenderRepository) PublishTender(tenderID int) (bool, error) {
+	query := `
+        UPDATE tenders
+        SET status = 'open', updated_at = CURRENT_TIMESTAMP
+        WHERE id = $1 AND status = 'draft'`
+
+	result, err := r.db.Exec(query, tenderID)
+	if err != nil {
+		return false, err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rows > 0, nil
+}
+
+func (r *TenderRepository) PublishDueTenders() ([]model.Tender, error) {
+	query := `
+        UPDATE tenders
+        SET status = 'open', updated_at = CURRENT_TIMESTAMP
+        WHERE status = 'draft' AND publish_at IS NOT NULL AND publish_at <= CURRENT_TIMESTAMP
+        RETURNING ` + tenderColumns
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to publish tenders: %w", err)
+	}
+	defer rows.Close()
+
+	var tenders []model.Tender
+	for rows.Next() {
+		var tender model.Tender
+		if err := scanTender(rows, &tender); err != nil {
+			return nil, fmt.Errorf("failed to scan tender row: %w", err)
+		}
+		tenders = append(tenders, tender)
+	}
+
+	return tenders, rows.Err()
+}
+
 func (r *TenderRepository) DeleteTender(tenderID int) error {
 	query := `DELETE FROM tenders WHERE id = $1`
 
@@ -183,3 +397,328 @@ func (r *TenderRepository) DeleteTender(tenderID int) error {
 
 	return nil
 }
+
+func (r *TenderRepository) CreateLot(lot *model.Lot) (*model.Lot, error) {
+	query := `
+        INSERT INTO tender_lots (tender_id, lot_number, title, description, budget)
+        VALUES ($1, $2, $3, $4, $5)
+        RETURNING id, status, created_at, updated_at`
+
+	err := r.db.QueryRow(query, lot.TenderID, lot.LotNumber, lot.Title, lot.Description, lot.Budget).
+		Scan(&lot.ID, &lot.Status, &lot.CreatedAt, &lot.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create lot: %w", err)
+	}
+
+	return lot, nil
+}
+
+func (r *TenderRepository) GetLotsByTenderID(tenderID int) ([]model.Lot, error) {
+	query := `
+        SELECT id, tender_id, lot_number, title, description, budget, status, awarded_bid_id, created_at, updated_at
+        FROM tender_lots
+        WHERE tender_id = $1
+        ORDER BY lot_number`
+
+	rows, err := r.db.Query(query, tenderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch lots: %w", err)
+	}
+	defer rows.Close()
+
+	var lots []model.Lot
+	for rows.Next() {
+		var lot model.Lot
+		if err := rows.Scan(&lot.ID, &lot.TenderID, &lot.LotNumber, &lot.Title, &lot.Description, &lot.Budget,
+			&lot.Status, &lot.AwardedBidID, &lot.CreatedAt, &lot.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan lot: %w", err)
+		}
+		lots = append(lots, lot)
+	}
+
+	return lots, rows.Err()
+}
+
+func (r *TenderRepository) DeleteLot(tenderID, lotID int) error {
+	result, err := r.db.Exec(`DELETE FROM tender_lots WHERE id = $1 AND tender_id = $2`, lotID, tenderID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return errors.New("lot not found")
+	}
+
+	return nil
+}
+
+func (r *TenderRepository) CancelLot(tenderID, lotID int) error {
+	query := `
+        UPDATE tender_lots
+        SET status = 'cancelled', updated_at = CURRENT_TIMESTAMP
+        WHERE id = $1 AND tender_id = $2 AND status = 'open'`
+
+	result, err := r.db.Exec(query, lotID, tenderID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return errors.New("lot not found or already decided")
+	}
+
+	return nil
+}
+
+// LotsDecided reports whether the tender has lots and none of them is still
+// open, and whether at least one of them was awarded.
+func (r *TenderRepository) LotsDecided(tenderID int) (decided bool, awarded bool, err error) {
+	var total, open, awardedCount int
+	err = r.db.QueryRow(`
+        SELECT count(*), count(*) FILTER (WHERE status = 'open'), count(*) FILTER (WHERE status = 'awarded')
+        FROM tender_lots
+        WHERE tender_id = $1`, tenderID).Scan(&total, &open, &awardedCount)
+	if err != nil {
+		return false, false, err
+	}
+
+	return total > 0 && open == 0, awardedCount > 0, nil
+}
+
+// ReplaceBoQItems swaps the whole bill of quantities of a tender.
+func (r *TenderRepository) ReplaceBoQItems(tenderID int, items []model.BoQItem) ([]model.BoQItem, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM tender_boq_items WHERE tender_id = $1`, tenderID); err != nil {
+		return nil, fmt.Errorf("failed to clear bill of quantities: %w", err)
+	}
+
+	query := `
+        INSERT INTO tender_boq_items (tender_id, lot_id, item_number, description, unit, quantity)
+        VALUES ($1, $2, $3, $4, $5, $6)
+        RETURNING id`
+
+	for i := range items {
+		items[i].TenderID = tenderID
+		err := tx.QueryRow(query, tenderID, items[i].LotID, items[i].ItemNumber, items[i].Description,
+			items[i].Unit, items[i].Quantity).Scan(&items[i].ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create bill of quantities item: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+func (r *TenderRepository) GetBoQItems(tenderID int) ([]model.BoQItem, error) {
+	query := `
+        SELECT id, tender_id, lot_id, item_number, description, unit, quantity
+        FROM tender_boq_items
+        WHERE tender_id = $1
+        ORDER BY item_number`
+
+	rows, err := r.db.Query(query, tenderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch bill of quantities: %w", err)
+	}
+	defer rows.Close()
+
+	var items []model.BoQItem
+	for rows.Next() {
+		var item model.BoQItem
+		if err := rows.Scan(&item.ID, &item.TenderID, &item.LotID, &item.ItemNumber, &item.Description,
+			&item.Unit, &item.Quantity); err != nil {
+			return nil, fmt.Errorf("failed to scan bill of quantities item: %w", err)
+		}
+		items = append(items, item)
+	}
+
+	return items, rows.Err()
+}
+
+// ListOpenTendersForContractor returns open public tenders plus the
+// invite-only tenders the contractor was invited to.
+func (r *TenderRepository) ListOpenTendersForContractor(contractorID int) ([]model.Tender, error) {
+	query := `SELECT ` + tenderColumns + ` FROM tenders
+        WHERE status = 'open'
+          AND (visibility = 'public'
+            OR EXISTS(SELECT 1 FROM tender_invitations i WHERE i.tender_id = tenders.id AND i.contractor_id = $1))
+        ORDER BY deadline`
+
+	rows, err := r.db.Query(query, contractorID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tenders []model.Tender
+	for rows.Next() {
+		var tender model.Tender
+		if err := scanTender(rows, &tender); err != nil {
+			return nil, err
+		}
+		tenders = append(tenders, tender)
+	}
+
+	return tenders, rows.Err()
+}
+
+// AddInvitations invites contractors to a tender and returns the ones that
+// had not been invited before.
+func (r *TenderRepository) AddInvitations(tenderID int, contractorIDs []int) ([]int, error) {
+	query := `
+        INSERT INTO tender_invitations (tender_id, contractor_id)
+        VALUES ($1, $2)
+        ON CONFLICT DO NOTHING`
+
+	var added []int
+	for _, contractorID := range contractorIDs {
+		result, err := r.db.Exec(query, tenderID, contractorID)
+		if err != nil {
+			return added, err
+		}
+		if rows, _ := result.RowsAffected(); rows > 0 {
+			added = append(added, contractorID)
+		}
+	}
+
+	return added, nil
+}
+
+func (r *TenderRepository) GetInvitations(tenderID int) ([]model.TenderInvitation, error) {
+	query := `
+        SELECT tender_id, contractor_id, invited_at
+        FROM tender_invitations
+        WHERE tender_id = $1
+        ORDER BY invited_at`
+
+	rows, err := r.db.Query(query, tenderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var invitations []model.TenderInvitation
+	for rows.Next() {
+		var invitation model.TenderInvitation
+		if err := rows.Scan(&invitation.TenderID, &invitation.ContractorID, &invitation.InvitedAt); err != nil {
+			return nil, err
+		}
+		invitations = append(invitations, invitation)
+	}
+
+	return invitations, rows.Err()
+}
+
+func (r *TenderRepository) IsInvited(tenderID, contractorID int) (bool, error) {
+	query := `SELECT EXISTS(SELECT 1 FROM tender_invitations WHERE tender_id = $1 AND contractor_id = $2)`
+
+	var invited bool
+	if err := r.db.QueryRow(query, tenderID, contractorID).Scan(&invited); err != nil {
+		return false, err
+	}
+	return invited, nil
+}
+
+// OpenRFPStage moves a two-stage tender to its second stage. The first-stage
+// deadline is kept in eoi_deadline and the tender reopens until deadline.
+func (r *TenderRepository) OpenRFPStage(tenderID int, deadline time.Time) error {
+	query := `
+        UPDATE tenders
+        SET stage = 'rfp', eoi_deadline = deadline, deadline = $1, status = 'open', updated_at = CURRENT_TIMESTAMP
+        WHERE id = $2 AND stage = 'eoi'`
+
+	result, err := r.db.Exec(query, deadline, tenderID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return errors.New("tender is not collecting expressions of interest")
+	}
+
+	return nil
+}
+
+// StartStandstill makes the tender's award provisional until endsAt.
+func (r *TenderRepository) StartStandstill(tenderID int, endsAt time.Time) error {
+	query := `
+        UPDATE tenders
+        SET standstill_ends_at = $2, award_finalized_at = NULL, updated_at = CURRENT_TIMESTAMP
+        WHERE id = $1`
+
+	_, err := r.db.Exec(query, tenderID, endsAt)
+	return err
+}
+
+// awardFinalizable matches awarded tenders whose standstill has elapsed with
+// no protest left pending.
+const awardFinalizable = `status = 'awarded' AND award_finalized_at IS NULL
+          AND standstill_ends_at IS NOT NULL AND standstill_ends_at <= CURRENT_TIMESTAMP
+          AND NOT EXISTS (SELECT 1 FROM award_protests p WHERE p.tender_id = tenders.id AND p.status = 'pending')`
+
+// FinalizeAward makes the tender's award final when nothing holds it back. It
+// returns false when the award is still provisional or already final.
+func (r *TenderRepository) FinalizeAward(tenderID int) (bool, error) {
+	query := `
+        UPDATE tenders
+        SET award_finalized_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+        WHERE id = $1 AND ` + awardFinalizable
+
+	result, err := r.db.Exec(query, tenderID)
+	if err != nil {
+		return false, err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rows > 0, nil
+}
+
+func (r *TenderRepository) FinalizeDueAwards() ([]model.Tender, error) {
+	query := `
+        UPDATE tenders
+        SET award_finalized_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+        WHERE ` + awardFinalizable + `
+        RETURNING ` + tenderColumns
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to finalize awards: %w", err)
+	}
+	defer rows.Close()
+
+	var tenders []model.Tender
+	for rows.Next() {
+		var tender model.Tender
+		if err := scanTender(rows, &tender); err != nil {
+			return nil, fmt.Errorf("failed to scan tender row: %w", err)
+		}
+		tenders = append(tenders, tender)
+	}
+
+	return tenders, rows.Err()
+}
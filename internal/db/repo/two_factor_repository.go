@@ -0,0 +1,182 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+type TwoFactorRepository struct {
+	db *sql.DB
+}
+
+func NewTwoFactorRepository(db *sql.DB) *TwoFactorRepository {
+	return &TwoFactorRepository{db: db}
+}
+
+// GetSecret returns the user's TOTP secret, when it was enabled (nil while
+// enrollment is pending) and the last time step a code was accepted for.
+func (r *TwoFactorRepository) GetSecret(userID int) (string, *time.Time, int64, error) {
+	var secret sql.NullString
+	var enabledAt *time.Time
+	var lastStep int64
+	err := r.db.QueryRow(`SELECT totp_secret, totp_enabled_at, totp_last_step FROM users WHERE id = $1`, userID).
+		Scan(&secret, &enabledAt, &lastStep)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil, 0, errors.New("user not found")
+	}
+	if err != nil {
+		return "", nil, 0, fmt.Errorf("failed to fetch two-factor secret: %w", err)
+	}
+	if !secret.Valid {
+		return "", nil, 0, errors.New("two-factor authentication is not set up")
+	}
+	return secret.String, enabledAt, lastStep, nil
+}
+
+// SetPendingSecret starts enrollment with a new secret. It replaces an
+// earlier secret that was never confirmed.
+func (r *TwoFactorRepository) SetPendingSecret(userID int, secret string) error {
+	result, err := r.db.Exec(`
+        UPDATE users SET totp_secret = $2, totp_last_step = 0, updated_at = CURRENT_TIMESTAMP
+        WHERE id = $1 AND totp_enabled_at IS NULL`, userID, secret)
+	if err != nil {
+		return fmt.Errorf("failed to store two-factor secret: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return errors.New("two-factor authentication is already enabled")
+	}
+	return nil
+}
+
+// Enable switches two-factor authentication on and stores the recovery codes.
+func (r *TwoFactorRepository) Enable(userID int, step int64, codeHashes []string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to enable two-factor authentication: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+        UPDATE users SET totp_enabled_at = CURRENT_TIMESTAMP, totp_last_step = $2, updated_at = CURRENT_TIMESTAMP
+        WHERE id = $1 AND totp_secret IS NOT NULL AND totp_enabled_at IS NULL`, userID, step)
+	if err != nil {
+		return fmt.Errorf("failed to enable two-factor authentication: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return errors.New("two-factor authentication is already enabled")
+	}
+
+	if err := replaceRecoveryCodes(tx, userID, codeHashes); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to enable two-factor authentication: %w", err)
+	}
+	return nil
+}
+
+// Disable switches two-factor authentication off and drops the secret and
+// the recovery codes.
+func (r *TwoFactorRepository) Disable(userID int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to disable two-factor authentication: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+        UPDATE users SET totp_secret = NULL, totp_enabled_at = NULL, totp_last_step = 0, updated_at = CURRENT_TIMESTAMP
+        WHERE id = $1`, userID)
+	if err != nil {
+		return fmt.Errorf("failed to disable two-factor authentication: %w", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return fmt.Errorf("failed to disable two-factor authentication: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to disable two-factor authentication: %w", err)
+	}
+	return nil
+}
+
+// UseStep records that a code for the time step was accepted. It reports
+// false if that step or a later one was used already, so every code works
+// once.
+func (r *TwoFactorRepository) UseStep(userID int, step int64) (bool, error) {
+	result, err := r.db.Exec(`UPDATE users SET totp_last_step = $2 WHERE id = $1 AND totp_last_step < $2`, userID, step)
+	if err != nil {
+		return false, fmt.Errorf("failed to record two-factor code: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
+
+// UseRecoveryCode marks an unused recovery code as used. It reports false if
+// the user has no such code.
+func (r *TwoFactorRepository) UseRecoveryCode(userID int, codeHash string) (bool, error) {
+	result, err := r.db.Exec(`
+        UPDATE recovery_codes SET used_at = CURRENT_TIMESTAMP
+        WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL`, userID, codeHash)
+	if err != nil {
+		return false, fmt.Errorf("failed to use recovery code: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
+
+func (r *TwoFactorRepository) CountRecoveryCodes(userID int) (int, error) {
+	var count int
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM recovery_codes WHERE user_id = $1 AND used_at IS NULL`, userID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count recovery codes: %w", err)
+	}
+	return count, nil
+}
+
+// ReplaceRecoveryCodes swaps the user's recovery codes for new ones.
+func (r *TwoFactorRepository) ReplaceRecoveryCodes(userID int, codeHashes []string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to replace recovery codes: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := replaceRecoveryCodes(tx, userID, codeHashes); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to replace recovery codes: %w", err)
+	}
+	return nil
+}
+
+func replaceRecoveryCodes(tx *sql.Tx, userID int, codeHashes []string) error {
+	if _, err := tx.Exec(`DELETE FROM recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return fmt.Errorf("failed to replace recovery codes: %w", err)
+	}
+	for _, hash := range codeHashes {
+		if _, err := tx.Exec(`INSERT INTO recovery_codes (user_id, code_hash) VALUES ($1, $2)`, userID, hash); err != nil {
+			return fmt.Errorf("failed to replace recovery codes: %w", err)
+		}
+	}
+	return nil
+}
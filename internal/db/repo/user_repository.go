@@ -4,6 +4,8 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"tender-managment/internal/model"
+	"time"
 )
 
 type UserRepository struct {
@@ -20,13 +22,25 @@ type User struct {
 	Email    string `json:"email"`
 	Password string `json:"password"`
 	Role     string `json:"role"`
+	OrgID    int    `json:"org_id"`
+	OrgRole  string `json:"org_role"`
+	// SuspendedAt is set while an administrator has suspended the account.
+	SuspendedAt *time.Time `json:"suspended_at"`
+	// EmailVerifiedAt is set once the user has confirmed their email address.
+	EmailVerifiedAt *time.Time `json:"email_verified_at"`
+	// TOTPEnabledAt is set while two-factor authentication is on.
+	TOTPEnabledAt *time.Time `json:"totp_enabled_at"`
+	// OrgRequiresTwoFactor is set when the user's organization only lets
+	// members with two-factor authentication approve awards.
+	OrgRequiresTwoFactor bool `json:"org_requires_two_factor"`
 }
 
 func (ur *UserRepository) GetUserByEmail(email string) (*User, error) {
-	query := `SELECT id, username, email, password, role FROM users WHERE email = $1`
+	query := `SELECT id, username, email, password, role, COALESCE(org_id, 0), COALESCE(org_role, ''), suspended_at, email_verified_at, totp_enabled_at,
+		COALESCE((SELECT require_2fa_for_awards FROM organizations WHERE id = users.org_id), FALSE) FROM users WHERE email = $1`
 	var user User
 	row := ur.db.QueryRow(query, email)
-	err := row.Scan(&user.ID, &user.Username, &user.Email, &user.Password, &user.Role)
+	err := row.Scan(&user.ID, &user.Username, &user.Email, &user.Password, &user.Role, &user.OrgID, &user.OrgRole, &user.SuspendedAt, &user.EmailVerifiedAt, &user.TOTPEnabledAt, &user.OrgRequiresTwoFactor)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
@@ -37,10 +51,11 @@ func (ur *UserRepository) GetUserByEmail(email string) (*User, error) {
 }
 
 func (ur *UserRepository) GetUserByUsername(username string) (*User, error) {
-	query := `SELECT id, username, email, password, role FROM users WHERE username = $1`
+	query := `SELECT id, username, email, password, role, COALESCE(org_id, 0), COALESCE(org_role, ''), suspended_at, email_verified_at, totp_enabled_at,
+		COALESCE((SELECT require_2fa_for_awards FROM organizations WHERE id = users.org_id), FALSE) FROM users WHERE username = $1`
 	var user User
 	row := ur.db.QueryRow(query, username)
-	err := row.Scan(&user.ID, &user.Username, &user.Email, &user.Password, &user.Role)
+	err := row.Scan(&user.ID, &user.Username, &user.Email, &user.Password, &user.Role, &user.OrgID, &user.OrgRole, &user.SuspendedAt, &user.EmailVerifiedAt, &user.TOTPEnabledAt, &user.OrgRequiresTwoFactor)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, fmt.Errorf("error fetching user: %v", err)
@@ -63,13 +78,214 @@ func (ur *UserRepository) CreateUser(username, email, password, role string) (in
 func (ur *UserRepository) GetUserByID(id int) (*User, error) {
 	var user User
 	query := `
-		SELECT id, username, password, email, role, created_at, updated_at
+		SELECT id, username, password, email, role, COALESCE(org_id, 0), COALESCE(org_role, ''), suspended_at, email_verified_at, totp_enabled_at,
+			COALESCE((SELECT require_2fa_for_awards FROM organizations WHERE id = users.org_id), FALSE)
 		FROM users
 		WHERE id = $1;
 	`
-	err := ur.db.QueryRow(query, id).Scan(&user.ID, &user.Username, &user.Password, &user.Email, &user.Role)
+	err := ur.db.QueryRow(query, id).Scan(&user.ID, &user.Username, &user.Password, &user.Email, &user.Role, &user.OrgID, &user.OrgRole, &user.SuspendedAt, &user.EmailVerifiedAt, &user.TOTPEnabledAt, &user.OrgRequiresTwoFactor)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch user with ID %d: %w", id, err)
 	}
 	return &user, nil
 }
+
+func (ur *UserRepository) GetUserIDsByRole(role string) ([]int, error) {
+	rows, err := ur.db.Query(`SELECT id FROM users WHERE role = $1`, role)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching users: %v", err)
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("error scanning user: %v", err)
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+const profileColumns = `id, username, email, role, full_name, phone, job_title, COALESCE(org_id, 0), COALESCE(org_role, ''), suspended_at, email_verified_at, created_at`
+
+func scanProfile(row rowScanner, p *model.UserProfile) error {
+	return row.Scan(&p.ID, &p.Username, &p.Email, &p.Role, &p.FullName, &p.Phone, &p.JobTitle, &p.OrgID, &p.OrgRole, &p.SuspendedAt, &p.EmailVerifiedAt, &p.CreatedAt)
+}
+
+func (ur *UserRepository) GetProfile(id int) (*model.UserProfile, error) {
+	var profile model.UserProfile
+	err := scanProfile(ur.db.QueryRow(`SELECT `+profileColumns+` FROM users WHERE id = $1`, id), &profile)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("user not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error fetching user: %v", err)
+	}
+	return &profile, nil
+}
+
+func (ur *UserRepository) UpdateProfile(id int, update model.UpdateProfile) (*model.UserProfile, error) {
+	query := `
+		UPDATE users SET full_name = $2, phone = $3, job_title = $4, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+		RETURNING ` + profileColumns
+
+	var profile model.UserProfile
+	err := scanProfile(ur.db.QueryRow(query, id, update.FullName, update.Phone, update.JobTitle), &profile)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("user not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error updating user: %v", err)
+	}
+	return &profile, nil
+}
+
+// GetProfilesByOrgID lists the members of an organization.
+func (ur *UserRepository) GetProfilesByOrgID(orgID int) ([]model.UserProfile, error) {
+	rows, err := ur.db.Query(`SELECT `+profileColumns+` FROM users WHERE org_id = $1 ORDER BY id`, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching users: %v", err)
+	}
+	defer rows.Close()
+
+	profiles := []model.UserProfile{}
+	for rows.Next() {
+		var profile model.UserProfile
+		if err := scanProfile(rows, &profile); err != nil {
+			return nil, fmt.Errorf("error scanning user: %v", err)
+		}
+		profiles = append(profiles, profile)
+	}
+
+	return profiles, rows.Err()
+}
+
+// GetColleagueIDs returns the user and the other members of their
+// organization.
+func (ur *UserRepository) GetColleagueIDs(userID int) ([]int, error) {
+	rows, err := ur.db.Query(`
+		SELECT id FROM users
+		WHERE id = $1 OR org_id = (SELECT org_id FROM users WHERE id = $1)`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching users: %v", err)
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("error scanning user: %v", err)
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+// SearchUsers lists users for administrators, newest first.
+func (ur *UserRepository) SearchUsers(filter model.UserSearch) ([]model.UserProfile, error) {
+	query := `SELECT ` + profileColumns + ` FROM users WHERE 1 = 1`
+	var args []interface{}
+
+	if filter.Query != "" {
+		args = append(args, "%"+filter.Query+"%")
+		n := len(args)
+		query += fmt.Sprintf(" AND (username ILIKE $%d OR email ILIKE $%d OR full_name ILIKE $%d)", n, n, n)
+	}
+	if filter.Role != "" {
+		args = append(args, filter.Role)
+		query += fmt.Sprintf(" AND role = $%d", len(args))
+	}
+	if filter.Suspended != nil {
+		if *filter.Suspended {
+			query += " AND suspended_at IS NOT NULL"
+		} else {
+			query += " AND suspended_at IS NULL"
+		}
+	}
+	args = append(args, filter.Limit, filter.Offset)
+	query += fmt.Sprintf(" ORDER BY id DESC LIMIT $%d OFFSET $%d", len(args)-1, len(args))
+
+	rows, err := ur.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching users: %v", err)
+	}
+	defer rows.Close()
+
+	profiles := []model.UserProfile{}
+	for rows.Next() {
+		var profile model.UserProfile
+		if err := scanProfile(rows, &profile); err != nil {
+			return nil, fmt.Errorf("error scanning user: %v", err)
+		}
+		profiles = append(profiles, profile)
+	}
+
+	return profiles, rows.Err()
+}
+
+// Suspend blocks the account. Administrators cannot be suspended.
+func (ur *UserRepository) Suspend(id int, reason string) error {
+	res, err := ur.db.Exec(`
+		UPDATE users SET suspended_at = CURRENT_TIMESTAMP, suspension_reason = $2, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND role <> 'admin' AND suspended_at IS NULL`, id, reason)
+	if err != nil {
+		return fmt.Errorf("error suspending user: %v", err)
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return errors.New("user cannot be suspended")
+	}
+	return nil
+}
+
+func (ur *UserRepository) Reactivate(id int) error {
+	res, err := ur.db.Exec(`
+		UPDATE users SET suspended_at = NULL, suspension_reason = NULL, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND suspended_at IS NOT NULL`, id)
+	if err != nil {
+		return fmt.Errorf("error reactivating user: %v", err)
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return errors.New("user is not suspended")
+	}
+	return nil
+}
+
+// MarkEmailVerified records that the user confirmed their email address.
+func (ur *UserRepository) MarkEmailVerified(id int) error {
+	_, err := ur.db.Exec(`
+		UPDATE users SET email_verified_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND email_verified_at IS NULL`, id)
+	if err != nil {
+		return fmt.Errorf("error verifying email: %v", err)
+	}
+	return nil
+}
+
+func (ur *UserRepository) UpdatePassword(id int, hashedPassword string) error {
+	result, err := ur.db.Exec(`UPDATE users SET password = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $1`, id, hashedPassword)
+	if err != nil {
+		return fmt.Errorf("error updating password: %v", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return errors.New("user not found")
+	}
+	return nil
+}
@@ -0,0 +1,16 @@
+package mailer
+
+import "context"
+
+// Message is a plain text email.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer delivers outgoing email. Implementations decide how: the file
+// mailer writes messages to disk for local development.
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
@@ -0,0 +1,48 @@
+package model
+
+import "time"
+
+// AdminAction is an entry in the audit trail of moderation work.
+type AdminAction struct {
+	ID         int       `json:"id"`
+	AdminID    int       `json:"admin_id"`
+	Action     string    `json:"action"`
+	TargetType string    `json:"target_type"`
+	TargetID   int       `json:"target_id"`
+	Details    string    `json:"details"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+const (
+	AdminActionSuspendUser    = "suspend_user"
+	AdminActionReactivateUser = "reactivate_user"
+	AdminActionCloseTender    = "close_tender"
+	AdminActionRemoveBid      = "remove_bid"
+	AdminActionSetRate        = "set_exchange_rate"
+)
+
+// UserSearch filters the admin user list. Query matches the username, email
+// or full name.
+type UserSearch struct {
+	Query     string
+	Role      string
+	Suspended *bool
+	Limit     int
+	Offset    int
+}
+
+type ModerationReason struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+// PlatformStats counts users, tenders, bids and contracts across the
+// platform, broken down by role or status.
+type PlatformStats struct {
+	Users          map[string]int `json:"users"`
+	SuspendedUsers int            `json:"suspended_users"`
+	Organizations  int            `json:"organizations"`
+	Tenders        map[string]int `json:"tenders"`
+	Bids           map[string]int `json:"bids"`
+	Contracts      map[string]int `json:"contracts"`
+	Invoices       map[string]int `json:"invoices"`
+}
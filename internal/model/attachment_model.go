@@ -0,0 +1,16 @@
+package model
+
+import "time"
+
+type Attachment struct {
+	ID         int       `json:"id"`
+	TenderID   int       `json:"tender_id"`
+	BidID      *int      `json:"bid_id,omitempty"`
+	UploadedBy int       `json:"uploaded_by"`
+	FileName   string    `json:"file_name"`
+	StorageKey string    `json:"-"`
+	MimeType   string    `json:"mime_type"`
+	Size       int64     `json:"size"`
+	SHA256     string    `json:"sha256"`
+	CreatedAt  time.Time `json:"created_at"`
+}
@@ -1,13 +1,102 @@
 package model
 
+import "time"
+
 type RegisterModel struct {
-	Username string `json:"username"`
-	Email    string `json:"email"`
-	Password string `json:"password"`
-	Role     string `json:"role"`
+	Username string `json:"username" binding:"required,max=100"`
+	Email    string `json:"email" binding:"required,email,max=150"`
+	Password string `json:"password" binding:"required"`
+	Role     string `json:"role" binding:"required,oneof=client contractor"`
 }
 
 type LoginModel struct {
-	Username string `json:"username"`
-	Password string `json:"password"`
+	Username string `json:"username" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+type ChangePasswordModel struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required"`
+}
+
+// TokenPair is handed out on login, registration and refresh. The refresh
+// token can be used once to obtain the next pair.
+type TokenPair struct {
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int    `json:"expires_in"`
+}
+
+type RefreshModel struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// RefreshToken is a stored refresh token. Only its SHA-256 hash is kept.
+// AccessJTI identifies the access token issued alongside it, so the session
+// can be cut off before that token expires.
+type RefreshToken struct {
+	ID        int        `json:"id"`
+	UserID    int        `json:"user_id"`
+	TokenHash string     `json:"-"`
+	AccessJTI string     `json:"-"`
+	TwoFactor bool       `json:"two_factor"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// Purposes of the single-use tokens sent by email.
+const (
+	EmailTokenVerify = "verify_email"
+	EmailTokenReset  = "reset_password"
+)
+
+type VerifyEmailModel struct {
+	Token string `json:"token" binding:"required"`
+}
+
+type PasswordResetRequestModel struct {
+	Email string `json:"email" binding:"required"`
+}
+
+type PasswordResetModel struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+// LoginChallenge is returned instead of tokens when the account has
+// two-factor authentication. The challenge token and a code complete the
+// login.
+type LoginChallenge struct {
+	ChallengeToken string `json:"challenge_token"`
+	ExpiresIn      int    `json:"expires_in"`
+}
+
+type TwoFactorLoginModel struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	// Code is a code from the authenticator app or a recovery code.
+	Code string `json:"code" binding:"required"`
+}
+
+type TwoFactorCodeModel struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// TwoFactorSetup holds a new secret. ProvisioningURI is meant to be shown as
+// a QR code for authenticator apps to scan.
+type TwoFactorSetup struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+type TwoFactorStatus struct {
+	Enabled           bool       `json:"enabled"`
+	EnabledAt         *time.Time `json:"enabled_at,omitempty"`
+	RecoveryCodesLeft int        `json:"recovery_codes_left"`
+}
+
+// RecoveryCodes are shown once. Each can replace an authenticator code a
+// single time.
+type RecoveryCodes struct {
+	Codes []string `json:"recovery_codes"`
 }
@@ -3,21 +3,40 @@ package model
 import "time"
 
 type Bid struct {
-	ID           string    `json:"id" bson:"_id"`
-	Price        float64   `json:"price"`
-	DeliveryTime int       `json:"delivery_time"`
-	Comments     string    `json:"comments"`
-	ContractorID int       `json:"contractor_id"`
-	TenderID     int       `json:"tender_id"`
-	Status       string    `json:"status"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	ID           string        `json:"id" bson:"_id"`
+	Price        Money         `json:"price"`
+	DeliveryTime int           `json:"delivery_time"`
+	Comments     string        `json:"comments"`
+	ContractorID int           `json:"contractor_id"`
+	OrgID        int           `json:"org_id,omitempty"`
+	TenderID     int           `json:"tender_id"`
+	Type         string        `json:"type"`
+	Status       string        `json:"status"`
+	Lots         []BidLot      `json:"lots,omitempty"`
+	LineItems    []BidLineItem `json:"line_items,omitempty"`
+	CreatedAt    time.Time     `json:"created_at"`
+	UpdatedAt    time.Time     `json:"updated_at"`
+	// ContractorReputation is only filled in when explicitly requested.
+	ContractorReputation *Reputation `json:"contractor_reputation,omitempty"`
 }
 
+// CreateBid carries either a single price or, for tenders split into lots,
+// a price per lot. Tenders with a bill of quantities also need a unit price
+// for every line. Lot and line item bids have their total calculated
+// server-side.
 type CreateBid struct {
-	Price        float64 `json:"price"`
-	DeliveryTime int     `json:"delivery_time"`
-	Comments     string  `json:"comments"`
+	Price        Money         `json:"price"`
+	DeliveryTime int           `json:"delivery_time"`
+	Comments     string        `json:"comments"`
+	Lots         []BidLot      `json:"lots,omitempty"`
+	LineItems    []BidLineItem `json:"line_items,omitempty"`
+}
+
+// CreateEOI is a non-priced response to the first stage of a two-stage
+// tender. The delivery time is indicative.
+type CreateEOI struct {
+	DeliveryTime int    `json:"delivery_time" binding:"required"`
+	Comments     string `json:"comments" binding:"required"`
 }
 
 type UpdateBid struct {
@@ -25,7 +44,13 @@ type UpdateBid struct {
 }
 
 const (
-	BidStatusPending  = "pending"
-	BidStatusAwarded  = "awarded"
-	BidStatusRejected = "rejected"
+	BidStatusPending     = "pending"
+	BidStatusShortlisted = "shortlisted"
+	BidStatusAwarded     = "awarded"
+	BidStatusRejected    = "rejected"
+)
+
+const (
+	BidTypeBid = "bid"
+	BidTypeEOI = "eoi"
 )
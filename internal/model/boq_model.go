@@ -0,0 +1,57 @@
+package model
+
+// BoQItem is a single line of a tender's bill of quantities.
+type BoQItem struct {
+	ID          int     `json:"id"`
+	TenderID    int     `json:"tender_id"`
+	LotID       *int    `json:"lot_id,omitempty"`
+	ItemNumber  int     `json:"item_number"`
+	Description string  `json:"description"`
+	Unit        string  `json:"unit"`
+	Quantity    float64 `json:"quantity"`
+}
+
+type CreateBoQItem struct {
+	LotID       *int    `json:"lot_id,omitempty"`
+	ItemNumber  int     `json:"item_number" binding:"required"`
+	Description string  `json:"description" binding:"required"`
+	Unit        string  `json:"unit" binding:"required"`
+	Quantity    float64 `json:"quantity" binding:"required"`
+}
+
+type BidLineItem struct {
+	BoQItemID int    `json:"boq_item_id"`
+	UnitPrice Amount `json:"unit_price"`
+	Total     Amount `json:"total"`
+}
+
+// BoQComparison lists all amounts in Currency. When the client asked for a
+// different currency than the tender's, ExchangeRate is the rate applied.
+type BoQComparison struct {
+	TenderID     int                 `json:"tender_id"`
+	Currency     string              `json:"currency"`
+	ExchangeRate string              `json:"exchange_rate,omitempty"`
+	Items        []BoQItemComparison `json:"items"`
+	Bids         []BidTotal          `json:"bids"`
+}
+
+type BoQItemComparison struct {
+	Item             BoQItem    `json:"item"`
+	Offers           []BoQOffer `json:"offers"`
+	MinUnitPrice     Amount     `json:"min_unit_price"`
+	MaxUnitPrice     Amount     `json:"max_unit_price"`
+	AverageUnitPrice Amount     `json:"average_unit_price"`
+}
+
+type BoQOffer struct {
+	BidID        string `json:"bid_id"`
+	ContractorID int    `json:"contractor_id"`
+	UnitPrice    Amount `json:"unit_price"`
+	Total        Amount `json:"total"`
+}
+
+type BidTotal struct {
+	BidID        string `json:"bid_id"`
+	ContractorID int    `json:"contractor_id"`
+	Total        Amount `json:"total"`
+}
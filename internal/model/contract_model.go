@@ -0,0 +1,44 @@
+package model
+
+import "time"
+
+// Contract is drafted from a winning bid once its award is final. It becomes
+// active when both the client and the contractor have accepted it.
+type Contract struct {
+	ID                   int        `json:"id"`
+	TenderID             int        `json:"tender_id"`
+	BidID                int        `json:"bid_id"`
+	ClientID             int        `json:"client_id"`
+	ContractorID         int        `json:"contractor_id"`
+	Price                Money      `json:"price"`
+	DeliveryTime         int        `json:"delivery_time"`
+	Terms                string     `json:"terms"`
+	StartDate            time.Time  `json:"start_date"`
+	EndDate              time.Time  `json:"end_date"`
+	Status               string     `json:"status"`
+	ClientAcceptedAt     *time.Time `json:"client_accepted_at,omitempty"`
+	ContractorAcceptedAt *time.Time `json:"contractor_accepted_at,omitempty"`
+	TerminationReason    string     `json:"termination_reason,omitempty"`
+	CreatedAt            time.Time  `json:"created_at"`
+	UpdatedAt            time.Time  `json:"updated_at"`
+}
+
+// CreateContract drafts a contract for an awarded bid. Without an end date
+// the contract runs for the bid's delivery time.
+type CreateContract struct {
+	BidID     int    `json:"bid_id" binding:"required"`
+	StartDate string `json:"start_date" binding:"required"`
+	EndDate   string `json:"end_date,omitempty"`
+	Terms     string `json:"terms,omitempty"`
+}
+
+type TerminateContract struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+const (
+	ContractStatusPending    = "pending"
+	ContractStatusActive     = "active"
+	ContractStatusCompleted  = "completed"
+	ContractStatusTerminated = "terminated"
+)
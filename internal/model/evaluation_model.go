@@ -0,0 +1,78 @@
+package model
+
+import "time"
+
+// Evaluator is a committee member appointed to score the bids of a tender.
+type Evaluator struct {
+	TenderID    int        `json:"tender_id"`
+	EvaluatorID int        `json:"evaluator_id"`
+	Username    string     `json:"username"`
+	AppointedAt time.Time  `json:"appointed_at"`
+	SubmittedAt *time.Time `json:"submitted_at,omitempty"`
+	SignedOffAt *time.Time `json:"signed_off_at,omitempty"`
+}
+
+type AppointEvaluators struct {
+	EvaluatorIDs []int `json:"evaluator_ids" binding:"required"`
+}
+
+// BidScore is one evaluator's score for one bid, from 0 to 100.
+type BidScore struct {
+	BidID       int       `json:"bid_id" binding:"required"`
+	EvaluatorID int       `json:"evaluator_id"`
+	Score       float64   `json:"score"`
+	Comment     string    `json:"comment,omitempty"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// EvaluationSheet is what an evaluator works on: the bids to score and their
+// own scores so far.
+type EvaluationSheet struct {
+	TenderID    int        `json:"tender_id"`
+	Bids        []Bid      `json:"bids"`
+	Scores      []BidScore `json:"scores"`
+	SubmittedAt *time.Time `json:"submitted_at,omitempty"`
+}
+
+type BidScoreSummary struct {
+	BidID         int        `json:"bid_id"`
+	ContractorID  int        `json:"contractor_id"`
+	Scores        []BidScore `json:"scores"`
+	Average       float64    `json:"average"`
+	Min           float64    `json:"min"`
+	Max           float64    `json:"max"`
+	StdDev        float64    `json:"std_dev"`
+	LargeVariance bool       `json:"large_variance"`
+}
+
+// EvaluationResults is only produced once every evaluator has submitted.
+type EvaluationResults struct {
+	TenderID  int               `json:"tender_id"`
+	SignedOff bool              `json:"signed_off"`
+	Bids      []BidScoreSummary `json:"bids"`
+}
+
+// DeclaredBidder is a bidding company listed on a conflict-of-interest
+// declaration.
+type DeclaredBidder struct {
+	ContractorID int    `json:"contractor_id"`
+	Username     string `json:"username"`
+	Conflict     bool   `json:"conflict"`
+}
+
+// COIDeclaration is a signed conflict-of-interest declaration covering the
+// bidders of a tender at the time of signing.
+type COIDeclaration struct {
+	ID          int              `json:"id"`
+	TenderID    int              `json:"tender_id"`
+	UserID      int              `json:"user_id"`
+	Statement   string           `json:"statement,omitempty"`
+	HasConflict bool             `json:"has_conflict"`
+	SignedAt    time.Time        `json:"signed_at"`
+	Bidders     []DeclaredBidder `json:"bidders"`
+}
+
+type SignDeclaration struct {
+	ConflictContractorIDs []int  `json:"conflict_contractor_ids"`
+	Statement             string `json:"statement"`
+}
@@ -0,0 +1,16 @@
+package model
+
+import "time"
+
+type ExchangeRate struct {
+	BaseCurrency  string    `json:"base_currency"`
+	QuoteCurrency string    `json:"quote_currency"`
+	Rate          string    `json:"rate"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+type SetExchangeRate struct {
+	BaseCurrency  string `json:"base_currency" binding:"required"`
+	QuoteCurrency string `json:"quote_currency" binding:"required"`
+	Rate          string `json:"rate" binding:"required"`
+}
@@ -0,0 +1,81 @@
+package model
+
+import "time"
+
+// Invoice bills work done under a contract, optionally for one accepted
+// milestone. Numbers run sequentially per contractor.
+type Invoice struct {
+	ID               int           `json:"id"`
+	Number           string        `json:"number"`
+	ContractID       int           `json:"contract_id"`
+	MilestoneID      *int          `json:"milestone_id,omitempty"`
+	ContractorID     int           `json:"contractor_id"`
+	ClientID         int           `json:"client_id"`
+	Currency         string        `json:"currency"`
+	Subtotal         Amount        `json:"subtotal"`
+	TaxTotal         Amount        `json:"tax_total"`
+	Total            Amount        `json:"total"`
+	Status           string        `json:"status"`
+	Notes            string        `json:"notes,omitempty"`
+	DisputeReason    string        `json:"dispute_reason,omitempty"`
+	PaymentReference string        `json:"payment_reference,omitempty"`
+	ApprovedAt       *time.Time    `json:"approved_at,omitempty"`
+	PaidAt           *time.Time    `json:"paid_at,omitempty"`
+	CreatedAt        time.Time     `json:"created_at"`
+	UpdatedAt        time.Time     `json:"updated_at"`
+	Lines            []InvoiceLine `json:"lines"`
+	Taxes            []InvoiceTax  `json:"taxes"`
+	Warnings         []string      `json:"warnings,omitempty"`
+}
+
+type InvoiceLine struct {
+	Description string  `json:"description" binding:"required"`
+	Quantity    float64 `json:"quantity" binding:"required"`
+	UnitPrice   Amount  `json:"unit_price"`
+	Amount      Amount  `json:"amount"`
+}
+
+// InvoiceTax is charged on the invoice subtotal. Rate is a percentage, e.g.
+// "20.00".
+type InvoiceTax struct {
+	Name   string `json:"name" binding:"required"`
+	Rate   Amount `json:"rate"`
+	Amount Amount `json:"amount"`
+}
+
+type CreateInvoice struct {
+	MilestoneID *int          `json:"milestone_id,omitempty"`
+	Lines       []InvoiceLine `json:"lines" binding:"required"`
+	Taxes       []InvoiceTax  `json:"taxes,omitempty"`
+	Notes       string        `json:"notes,omitempty"`
+}
+
+type DisputeInvoice struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+type RecordPayment struct {
+	Reference string `json:"reference" binding:"required"`
+	PaidAt    string `json:"paid_at,omitempty"`
+}
+
+// InvoiceReconciliation compares what has been invoiced under a contract,
+// net of tax, with its awarded price. Disputed invoices are left out of the
+// invoiced total.
+type InvoiceReconciliation struct {
+	ContractID   int    `json:"contract_id"`
+	Price        Money  `json:"price"`
+	Invoiced     Amount `json:"invoiced"`
+	Approved     Amount `json:"approved"`
+	Paid         Amount `json:"paid"`
+	Disputed     Amount `json:"disputed"`
+	Remaining    Amount `json:"remaining"`
+	OverInvoiced bool   `json:"over_invoiced"`
+}
+
+const (
+	InvoiceStatusSubmitted = "submitted"
+	InvoiceStatusApproved  = "approved"
+	InvoiceStatusPaid      = "paid"
+	InvoiceStatusDisputed  = "disputed"
+)
@@ -0,0 +1,35 @@
+package model
+
+import "time"
+
+type Lot struct {
+	ID           int       `json:"id"`
+	TenderID     int       `json:"tender_id"`
+	LotNumber    int       `json:"lot_number"`
+	Title        string    `json:"title"`
+	Description  string    `json:"description"`
+	Budget       Amount    `json:"budget"`
+	Status       string    `json:"status"`
+	AwardedBidID *int      `json:"awarded_bid_id,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+type CreateLot struct {
+	LotNumber   int    `json:"lot_number" binding:"required"`
+	Title       string `json:"title" binding:"required"`
+	Description string `json:"description"`
+	Budget      Amount `json:"budget"`
+}
+
+type BidLot struct {
+	LotID  int    `json:"lot_id"`
+	Price  Amount `json:"price"`
+	Status string `json:"status,omitempty"`
+}
+
+const (
+	LotStatusOpen      = "open"
+	LotStatusAwarded   = "awarded"
+	LotStatusCancelled = "cancelled"
+)
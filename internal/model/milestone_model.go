@@ -0,0 +1,58 @@
+package model
+
+import "time"
+
+// Milestone is a scheduled part of a contract's delivery, paid with its share
+// of the contract price once the client accepts it.
+type Milestone struct {
+	ID            int                 `json:"id"`
+	ContractID    int                 `json:"contract_id"`
+	Sequence      int                 `json:"sequence"`
+	Title         string              `json:"title"`
+	Description   string              `json:"description"`
+	DueDate       time.Time           `json:"due_date"`
+	Amount        Amount              `json:"amount"`
+	Status        string              `json:"status"`
+	DeliveryNote  string              `json:"delivery_note,omitempty"`
+	DeliveredAt   *time.Time          `json:"delivered_at,omitempty"`
+	ReviewComment string              `json:"review_comment,omitempty"`
+	ReviewedAt    *time.Time          `json:"reviewed_at,omitempty"`
+	CreatedAt     time.Time           `json:"created_at"`
+	UpdatedAt     time.Time           `json:"updated_at"`
+	Documents     []MilestoneDocument `json:"documents,omitempty"`
+}
+
+type MilestoneDocument struct {
+	ID          int       `json:"id"`
+	MilestoneID int       `json:"milestone_id"`
+	FileName    string    `json:"file_name"`
+	StorageKey  string    `json:"-"`
+	MimeType    string    `json:"mime_type"`
+	Size        int64     `json:"size"`
+	SHA256      string    `json:"sha256"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// SetMilestone is one entry of a contract's schedule. Amounts must add up to
+// the contract price.
+type SetMilestone struct {
+	Title       string `json:"title" binding:"required"`
+	Description string `json:"description"`
+	DueDate     string `json:"due_date" binding:"required"`
+	Amount      Amount `json:"amount"`
+}
+
+type DeliverMilestone struct {
+	Note string `json:"note"`
+}
+
+type ReviewMilestone struct {
+	Comment string `json:"comment"`
+}
+
+const (
+	MilestoneStatusPending   = "pending"
+	MilestoneStatusDelivered = "delivered"
+	MilestoneStatusAccepted  = "accepted"
+	MilestoneStatusRejected  = "rejected"
+)
@@ -0,0 +1,210 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+const DefaultCurrency = "USD"
+
+// Amount is an exact monetary value stored in minor units (cents), matching
+// the NUMERIC(15, 2) columns. It is encoded as a decimal string in JSON and
+// SQL so no value ever passes through a float.
+type Amount int64
+
+// Money is an amount together with its ISO 4217 currency code.
+type Money struct {
+	Amount   Amount `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+// ParseAmount parses a decimal string such as "1250.5" and rejects values with
+// more than two fractional digits.
+func ParseAmount(s string) (Amount, error) {
+	return parseAmount(s, true)
+}
+
+// parseAmount parses a decimal string. In non-strict mode extra fractional
+// digits, as returned by NUMERIC arithmetic, are rounded half away from zero.
+func parseAmount(s string, strict bool) (Amount, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, errors.New("empty amount")
+	}
+
+	negative := false
+	if s[0] == '-' || s[0] == '+' {
+		negative = s[0] == '-'
+		s = s[1:]
+	}
+
+	whole, frac, _ := strings.Cut(s, ".")
+	if whole == "" && frac == "" {
+		return 0, fmt.Errorf("invalid amount %q", s)
+	}
+	if whole == "" {
+		whole = "0"
+	}
+	if !isDigits(whole) || !isDigits(frac) {
+		return 0, fmt.Errorf("invalid amount %q", s)
+	}
+
+	roundUp := false
+	if len(frac) > 2 {
+		if strict {
+			return 0, fmt.Errorf("amount %q has more than two decimal places", s)
+		}
+		roundUp = frac[2] >= '5'
+		frac = frac[:2]
+	}
+	frac += strings.Repeat("0", 2-len(frac))
+
+	units, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil || units > math.MaxInt64/100-1 {
+		return 0, fmt.Errorf("amount %q is out of range", s)
+	}
+	cents, _ := strconv.ParseInt(frac, 10, 64)
+
+	value := units*100 + cents
+	if roundUp {
+		value++
+	}
+	if negative {
+		value = -value
+	}
+
+	return Amount(value), nil
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func (a Amount) String() string {
+	value := int64(a)
+	sign := ""
+	if value < 0 {
+		sign = "-"
+		value = -value
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, value/100, value%100)
+}
+
+// MulQuantity multiplies a unit price by a quantity with up to three decimal
+// places, as stored in the bill of quantities, and rounds to whole cents.
+func (a Amount) MulQuantity(quantity float64) Amount {
+	thousandths := big.NewInt(int64(math.Round(quantity * 1000)))
+	product := new(big.Rat).SetFrac(new(big.Int).Mul(big.NewInt(int64(a)), thousandths), big.NewInt(1000))
+	return roundRat(product)
+}
+
+// Convert applies an exchange rate and rounds to whole cents.
+func (a Amount) Convert(rate *big.Rat) Amount {
+	return roundRat(new(big.Rat).Mul(new(big.Rat).SetInt64(int64(a)), rate))
+}
+
+// DivInt divides the amount, rounding to whole cents. It is used for averages.
+func (a Amount) DivInt(n int) Amount {
+	if n == 0 {
+		return 0
+	}
+	return roundRat(big.NewRat(int64(a), int64(n)))
+}
+
+// Percent returns rate percent of the amount, where rate is itself written
+// with two decimals ("20.00" for 20%), rounded to whole cents.
+func (a Amount) Percent(rate Amount) Amount {
+	product := new(big.Int).Mul(big.NewInt(int64(a)), big.NewInt(int64(rate)))
+	return roundRat(new(big.Rat).SetFrac(product, big.NewInt(10000)))
+}
+
+func roundRat(r *big.Rat) Amount {
+	num := new(big.Int).Set(r.Num())
+	den := r.Denom()
+	negative := num.Sign() < 0
+	num.Abs(num)
+
+	quo, rem := new(big.Int).QuoRem(num, den, new(big.Int))
+	if new(big.Int).Mul(rem, big.NewInt(2)).Cmp(den) >= 0 {
+		quo.Add(quo, big.NewInt(1))
+	}
+	if negative {
+		quo.Neg(quo)
+	}
+	return Amount(quo.Int64())
+}
+
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return json.Marshal(a.String())
+}
+
+// UnmarshalJSON accepts both "12.50" and 12.50. Numbers are read from their
+// literal text, so they are never rounded through a float.
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	text := strings.Trim(string(data), `"`)
+	if text == "null" {
+		*a = 0
+		return nil
+	}
+
+	value, err := ParseAmount(text)
+	if err != nil {
+		return err
+	}
+	*a = value
+	return nil
+}
+
+func (a *Amount) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*a = 0
+	case []byte:
+		value, err := parseAmount(string(v), false)
+		if err != nil {
+			return err
+		}
+		*a = value
+	case string:
+		value, err := parseAmount(v, false)
+		if err != nil {
+			return err
+		}
+		*a = value
+	case int64:
+		*a = Amount(v * 100)
+	case float64:
+		*a = Amount(math.Round(v * 100))
+	default:
+		return fmt.Errorf("cannot scan %T into Amount", src)
+	}
+	return nil
+}
+
+func (a Amount) Value() (driver.Value, error) {
+	return a.String(), nil
+}
+
+// ValidCurrency reports whether code looks like an ISO 4217 currency code.
+func ValidCurrency(code string) bool {
+	if len(code) != 3 {
+		return false
+	}
+	for _, r := range code {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,233 @@
+package model
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+)
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Amount
+	}{
+		{"0", 0},
+		{"1250.5", 125050},
+		{"1250.50", 125050},
+		{"0.01", 1},
+		{".5", 50},
+		{"7.", 700},
+		{"+7", 700},
+		{"-3.2", -320},
+		{" 12.34 ", 1234},
+		{"92233720368547756.99", 9223372036854775699},
+	}
+	for _, tt := range tests {
+		got, err := ParseAmount(tt.in)
+		if err != nil {
+			t.Errorf("ParseAmount(%q) returned error: %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseAmount(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseAmountRejects(t *testing.T) {
+	for _, in := range []string{
+		"", " ", "-", ".", "abc", "1..2", "1.2.3", "1,50", "1e5", "--1", "1.234",
+		"92233720368547758", "99999999999999999999",
+	} {
+		if got, err := ParseAmount(in); err == nil {
+			t.Errorf("ParseAmount(%q) = %d, want error", in, got)
+		}
+	}
+}
+
+func TestParseAmountRounding(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Amount
+	}{
+		{"1.004", 100},
+		{"1.005", 101},
+		{"1.0049999", 100},
+		{"2.999", 300},
+		{"-1.005", -101},
+		{"-1.004", -100},
+	}
+	for _, tt := range tests {
+		got, err := parseAmount(tt.in, false)
+		if err != nil {
+			t.Errorf("parseAmount(%q) returned error: %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseAmount(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestAmountString(t *testing.T) {
+	tests := []struct {
+		in   Amount
+		want string
+	}{
+		{0, "0.00"},
+		{5, "0.05"},
+		{125050, "1250.50"},
+		{-5, "-0.05"},
+		{-320, "-3.20"},
+	}
+	for _, tt := range tests {
+		if got := tt.in.String(); got != tt.want {
+			t.Errorf("Amount(%d).String() = %q, want %q", int64(tt.in), got, tt.want)
+		}
+	}
+}
+
+func TestAmountJSON(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Amount
+	}{
+		{`"12.50"`, 1250},
+		{`12.5`, 1250},
+		{`0.1`, 10},
+		{`null`, 0},
+	}
+	for _, tt := range tests {
+		var got Amount
+		if err := json.Unmarshal([]byte(tt.in), &got); err != nil {
+			t.Errorf("Unmarshal(%s) returned error: %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Unmarshal(%s) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+
+	var a Amount
+	if err := json.Unmarshal([]byte(`"1.234"`), &a); err == nil {
+		t.Error("Unmarshal accepted more than two decimal places")
+	}
+
+	data, err := json.Marshal(Money{Amount: 100001, Currency: "EUR"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"amount":"1000.01","currency":"EUR"}`; string(data) != want {
+		t.Errorf("Marshal = %s, want %s", data, want)
+	}
+}
+
+func TestAmountScan(t *testing.T) {
+	tests := []struct {
+		src  interface{}
+		want Amount
+	}{
+		{nil, 0},
+		{[]byte("1250.50"), 125050},
+		{"12.345", 1235},
+		{int64(3), 300},
+		{float64(19.99), 1999},
+	}
+	for _, tt := range tests {
+		var got Amount
+		if err := got.Scan(tt.src); err != nil {
+			t.Errorf("Scan(%#v) returned error: %v", tt.src, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Scan(%#v) = %d, want %d", tt.src, got, tt.want)
+		}
+	}
+
+	var a Amount
+	if err := a.Scan(true); err == nil {
+		t.Error("Scan accepted a bool")
+	}
+}
+
+func TestAmountMulQuantity(t *testing.T) {
+	tests := []struct {
+		price    Amount
+		quantity float64
+		want     Amount
+	}{
+		{1999, 1, 1999},
+		{1999, 1.5, 2999},
+		{333, 0.333, 111},
+		{100, 0.001, 0},
+		{100, 0.005, 1},
+		{250, 12.125, 3031},
+	}
+	for _, tt := range tests {
+		if got := tt.price.MulQuantity(tt.quantity); got != tt.want {
+			t.Errorf("Amount(%d).MulQuantity(%v) = %d, want %d", int64(tt.price), tt.quantity, got, tt.want)
+		}
+	}
+}
+
+func TestAmountConvert(t *testing.T) {
+	tests := []struct {
+		amount Amount
+		rate   *big.Rat
+		want   Amount
+	}{
+		{1000, big.NewRat(1, 1), 1000},
+		{1000, big.NewRat(1, 3), 333},
+		{1000, big.NewRat(2, 3), 667},
+		{1, big.NewRat(1, 2), 1},
+		{-1, big.NewRat(1, 2), -1},
+		{10000, big.NewRat(10856, 10000), 10856},
+	}
+	for _, tt := range tests {
+		if got := tt.amount.Convert(tt.rate); got != tt.want {
+			t.Errorf("Amount(%d).Convert(%s) = %d, want %d", int64(tt.amount), tt.rate, got, tt.want)
+		}
+	}
+}
+
+func TestAmountDivInt(t *testing.T) {
+	tests := []struct {
+		amount Amount
+		n      int
+		want   Amount
+	}{
+		{1000, 3, 333},
+		{2000, 3, 667},
+		{5, 2, 3},
+		{-5, 2, -3},
+		{1000, 0, 0},
+	}
+	for _, tt := range tests {
+		if got := tt.amount.DivInt(tt.n); got != tt.want {
+			t.Errorf("Amount(%d).DivInt(%d) = %d, want %d", int64(tt.amount), tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestAmountPercent(t *testing.T) {
+	tests := []struct {
+		amount Amount
+		rate   Amount
+		want   Amount
+	}{
+		{10000, 2000, 2000},
+		{10000, 0, 0},
+		{10000, 10000, 10000},
+		{333, 750, 25},
+		{333, 725, 24},
+		{19999, 1750, 3500},
+		{12345, 1250, 1543},
+		{-10000, 2000, -2000},
+		{-333, 750, -25},
+	}
+	for _, tt := range tests {
+		if got := tt.amount.Percent(tt.rate); got != tt.want {
+			t.Errorf("Amount(%s).Percent(%s) = %s, want %s", tt.amount, tt.rate, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package model
+
+import "time"
+
+// Organization is the company a user works for. Tenders and bids created by
+// its members belong to the organization, so colleagues can manage them.
+type Organization struct {
+	ID        int                   `json:"id"`
+	LegalName string                `json:"legal_name"`
+	TaxID     string                `json:"tax_id"`
+	Address   string                `json:"address"`
+	Contacts  []OrganizationContact `json:"contacts"`
+	// RequireTwoFactorForAwards keeps members without two-factor
+	// authentication from approving awards.
+	RequireTwoFactorForAwards bool      `json:"require_two_factor_for_awards"`
+	CreatedAt                 time.Time `json:"created_at"`
+	UpdatedAt                 time.Time `json:"updated_at"`
+}
+
+// OrganizationContact is a person to reach at the organization, who does not
+// need an account.
+type OrganizationContact struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name" binding:"required"`
+	Email    string `json:"email"`
+	Phone    string `json:"phone"`
+	Position string `json:"position"`
+}
+
+type SaveOrganization struct {
+	LegalName                 string                `json:"legal_name" binding:"required"`
+	TaxID                     string                `json:"tax_id" binding:"required"`
+	Address                   string                `json:"address"`
+	Contacts                  []OrganizationContact `json:"contacts"`
+	RequireTwoFactorForAwards bool                  `json:"require_two_factor_for_awards"`
+}
+
+// AddMember adds a colleague with one of the roles owner,
+// procurement_officer, evaluator, viewer or bid_manager.
+type AddMember struct {
+	Email string `json:"email" binding:"required,email"`
+	Role  string `json:"role" binding:"required"`
+}
+
+type SetMemberRole struct {
+	Role string `json:"role" binding:"required"`
+}
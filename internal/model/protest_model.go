@@ -0,0 +1,50 @@
+package model
+
+import "time"
+
+// Protest is a losing bidder's challenge of a provisional award. The client
+// either upholds the award or reverses it.
+type Protest struct {
+	ID             int               `json:"id"`
+	TenderID       int               `json:"tender_id"`
+	ContractorID   int               `json:"contractor_id"`
+	Reasons        string            `json:"reasons"`
+	Status         string            `json:"status"`
+	ResolutionNote string            `json:"resolution_note,omitempty"`
+	ResolvedAt     *time.Time        `json:"resolved_at,omitempty"`
+	CreatedAt      time.Time         `json:"created_at"`
+	Documents      []ProtestDocument `json:"documents,omitempty"`
+}
+
+type ProtestDocument struct {
+	ID         int       `json:"id"`
+	ProtestID  int       `json:"protest_id"`
+	FileName   string    `json:"file_name"`
+	StorageKey string    `json:"-"`
+	MimeType   string    `json:"mime_type"`
+	Size       int64     `json:"size"`
+	SHA256     string    `json:"sha256"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type FileProtest struct {
+	Reasons string `json:"reasons" binding:"required"`
+}
+
+// ResolveProtest decides a protest. Decision is "uphold" to keep the award or
+// "reverse" to withdraw it.
+type ResolveProtest struct {
+	Decision string `json:"decision" binding:"required"`
+	Note     string `json:"note" binding:"required"`
+}
+
+const (
+	ProtestStatusPending  = "pending"
+	ProtestStatusUpheld   = "award_upheld"
+	ProtestStatusReversed = "award_reversed"
+)
+
+const (
+	ProtestDecisionUphold  = "uphold"
+	ProtestDecisionReverse = "reverse"
+)
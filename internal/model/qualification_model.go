@@ -0,0 +1,60 @@
+package model
+
+import "time"
+
+const (
+	QualificationTypeLicense       = "license"
+	QualificationTypeCertification = "certification"
+	QualificationTypeTurnover      = "turnover"
+)
+
+// TurnoverCode is the code of every turnover qualification and requirement;
+// turnover is compared by amount rather than matched by code.
+const TurnoverCode = "annual"
+
+const (
+	QualificationStatusPending  = "pending"
+	QualificationStatusApproved = "approved"
+	QualificationStatusRejected = "rejected"
+)
+
+// Qualification is a document a contractor submits to prove a license,
+// certification or annual turnover. Only approved, unexpired qualifications
+// count towards tender requirements.
+type Qualification struct {
+	ID           int        `json:"id"`
+	ContractorID int        `json:"contractor_id"`
+	Type         string     `json:"type"`
+	Code         string     `json:"code"`
+	Turnover     *Money     `json:"turnover,omitempty"`
+	ExpiresAt    *time.Time `json:"expires_at,omitempty"`
+	Status       string     `json:"status"`
+	ReviewNote   string     `json:"review_note,omitempty"`
+	ReviewedBy   *int       `json:"reviewed_by,omitempty"`
+	ReviewedAt   *time.Time `json:"reviewed_at,omitempty"`
+	FileName     string     `json:"file_name"`
+	StorageKey   string     `json:"-"`
+	MimeType     string     `json:"mime_type"`
+	Size         int64      `json:"size"`
+	SHA256       string     `json:"sha256"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// Requirement is a qualification a tender asks for. Turnover requirements
+// carry a minimum amount in the tender currency.
+type Requirement struct {
+	ID        int     `json:"id"`
+	TenderID  int     `json:"tender_id"`
+	Type      string  `json:"type" binding:"required"`
+	Code      string  `json:"code"`
+	MinAmount *Amount `json:"min_amount,omitempty"`
+}
+
+type ReviewQualification struct {
+	Note string `json:"note"`
+}
+
+type Eligibility struct {
+	Eligible bool     `json:"eligible"`
+	Missing  []string `json:"missing"`
+}
@@ -0,0 +1,36 @@
+package model
+
+import "time"
+
+// Review rates the other party of a completed contract from 1 to 5 on each
+// criterion. Clients rate the contractor's work; contractors rate the
+// client's brief, timely payment and communication.
+type Review struct {
+	ID            int       `json:"id"`
+	ContractID    int       `json:"contract_id"`
+	ReviewerID    int       `json:"reviewer_id"`
+	RevieweeID    int       `json:"reviewee_id"`
+	Quality       int       `json:"quality"`
+	Timeliness    int       `json:"timeliness"`
+	Communication int       `json:"communication"`
+	Comment       string    `json:"comment"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+type SubmitReview struct {
+	Quality       int    `json:"quality" binding:"required"`
+	Timeliness    int    `json:"timeliness" binding:"required"`
+	Communication int    `json:"communication" binding:"required"`
+	Comment       string `json:"comment"`
+}
+
+// Reputation aggregates the reviews a user has received.
+type Reputation struct {
+	UserID        int     `json:"user_id"`
+	Reviews       int     `json:"reviews"`
+	Quality       float64 `json:"quality"`
+	Timeliness    float64 `json:"timeliness"`
+	Communication float64 `json:"communication"`
+	Overall       float64 `json:"overall"`
+}
@@ -0,0 +1,36 @@
+package model
+
+import "time"
+
+type TenderTemplate struct {
+	ID                 int                  `json:"id"`
+	ClientID           int                  `json:"client_id"`
+	Name               string               `json:"name"`
+	Title              string               `json:"title"`
+	Description        string               `json:"description"`
+	EvaluationCriteria string               `json:"evaluation_criteria"`
+	Budget             Money                `json:"budget"`
+	Attachments        []TemplateAttachment `json:"attachments"`
+	CreatedAt          time.Time            `json:"created_at"`
+}
+
+type TemplateAttachment struct {
+	ID       int    `json:"id"`
+	FileName string `json:"file_name"`
+	MimeType string `json:"mime_type"`
+	Size     int64  `json:"size"`
+	SHA256   string `json:"sha256"`
+}
+
+type CreateTemplate struct {
+	Name string `json:"name" binding:"required"`
+}
+
+// CreateTenderFrom is the payload for starting a new draft from a template or
+// an existing tender. Empty fields keep the source values.
+type CreateTenderFrom struct {
+	Title     string `json:"title,omitempty"`
+	Deadline  string `json:"deadline" binding:"required"`
+	Budget    Amount `json:"budget,omitempty"`
+	PublishAt string `json:"publish_at,omitempty"`
+}
@@ -3,24 +3,46 @@ package model
 import "time"
 
 type Tender struct {
-	ID          int       `json:"id"`
-	ClientID    int       `json:"client_id"`
-	Title       string    `json:"title"`
-	Description string    `json:"description"`
-	Deadline    time.Time `json:"deadline"`
-	Budget      float64   `json:"budget"`
-	Status      string    `json:"status"`
-	Attachment  string    `json:"attachment"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID                 int        `json:"id"`
+	ClientID           int        `json:"client_id"`
+	OrgID              int        `json:"org_id,omitempty"`
+	Title              string     `json:"title"`
+	Description        string     `json:"description"`
+	EvaluationCriteria string     `json:"evaluation_criteria"`
+	Deadline           time.Time  `json:"deadline"`
+	Budget             Money      `json:"budget"`
+	Status             string     `json:"status"`
+	Visibility         string     `json:"visibility"`
+	Stage              string     `json:"stage"`
+	EOIDeadline        *time.Time `json:"eoi_deadline,omitempty"`
+	PublishAt          *time.Time `json:"publish_at,omitempty"`
+	StandstillEndsAt   *time.Time `json:"standstill_ends_at,omitempty"`
+	AwardFinalizedAt   *time.Time `json:"award_finalized_at,omitempty"`
+	CreatedAt          time.Time  `json:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at"`
 }
 
 type CreateTender struct {
-	Title       string  `json:"title" binding:"required"`
-	Description string  `json:"description" binding:"required"`
-	Deadline    string  `json:"deadline" binding:"required"`
-	Budget      float64 `json:"budget" binding:"required"`
-	Attachment  string  `json:"attachment,omitempty"`
+	Title              string `json:"title" binding:"required"`
+	Description        string `json:"description" binding:"required"`
+	EvaluationCriteria string `json:"evaluation_criteria,omitempty"`
+	Deadline           string `json:"deadline" binding:"required"`
+	Budget             Money  `json:"budget"`
+	Draft              bool   `json:"draft,omitempty"`
+	PublishAt          string `json:"publish_at,omitempty"`
+	Visibility         string `json:"visibility,omitempty"`
+	InvitedIDs         []int  `json:"invited_contractor_ids,omitempty"`
+	TwoStage           bool   `json:"two_stage,omitempty"`
+}
+
+type UpdateTender struct {
+	Title              string `json:"title" binding:"required"`
+	Description        string `json:"description" binding:"required"`
+	EvaluationCriteria string `json:"evaluation_criteria,omitempty"`
+	Deadline           string `json:"deadline" binding:"required"`
+	Budget             Money  `json:"budget"`
+	PublishAt          string `json:"publish_at,omitempty"`
+	Visibility         string `json:"visibility,omitempty"`
 }
 
 type GetTender struct {
@@ -28,12 +50,49 @@ type GetTender struct {
 	Title       string    `json:"title"`
 	Description string    `json:"description"`
 	Deadline    time.Time `json:"deadline"`
-	Budget      float64   `json:"budget"`
+	Budget      Money     `json:"budget"`
 	Status      string    `json:"status"`
 	CreatedAt   time.Time `json:"created_at"`
 	BidsCount   int       `json:"bids_count"`
 }
 
+type TenderInvitation struct {
+	TenderID     int       `json:"tender_id"`
+	ContractorID int       `json:"contractor_id"`
+	InvitedAt    time.Time `json:"invited_at"`
+}
+
+type InviteContractors struct {
+	ContractorIDs []int `json:"contractor_ids" binding:"required"`
+}
+
+type ShortlistRequest struct {
+	BidIDs []int `json:"bid_ids" binding:"required"`
+}
+
+type OpenRFPRequest struct {
+	Deadline string `json:"deadline" binding:"required"`
+}
+
 type UpdateTenderStatusRequest struct {
 	Status string `json:"status"`
 }
+
+const (
+	TenderStatusDraft   = "draft"
+	TenderStatusOpen    = "open"
+	TenderStatusClosed  = "closed"
+	TenderStatusAwarded = "awarded"
+)
+
+// Two-stage tenders collect expressions of interest first and priced bids
+// from the shortlist second. Single-stage tenders are always in the RFP stage.
+const (
+	TenderStageEOI = "eoi"
+	TenderStageRFP = "rfp"
+)
+
+const (
+	TenderVisibilityPublic     = "public"
+	TenderVisibilityInviteOnly = "invite_only"
+)
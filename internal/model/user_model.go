@@ -0,0 +1,25 @@
+package model
+
+import "time"
+
+// UserProfile is the public view of a user account.
+type UserProfile struct {
+	ID              int        `json:"id"`
+	Username        string     `json:"username"`
+	Email           string     `json:"email"`
+	Role            string     `json:"role"`
+	FullName        string     `json:"full_name"`
+	Phone           string     `json:"phone"`
+	JobTitle        string     `json:"job_title"`
+	OrgID           int        `json:"org_id,omitempty"`
+	OrgRole         string     `json:"org_role,omitempty"`
+	SuspendedAt     *time.Time `json:"suspended_at,omitempty"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
+
+type UpdateProfile struct {
+	FullName string `json:"full_name"`
+	Phone    string `json:"phone"`
+	JobTitle string `json:"job_title"`
+}
@@ -0,0 +1,145 @@
+// Package pdf writes simple text-only PDF documents. It covers the documents
+// the platform generates, lines of text laid out top to bottom in Helvetica,
+// without depending on a PDF library.
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	pageWidth  = 595.0 // A4 in points
+	pageHeight = 842.0
+	margin     = 50.0
+)
+
+type item struct {
+	x, y float64
+	size float64
+	bold bool
+	text string
+}
+
+// Document collects text page by page. Content that no longer fits on the
+// current page continues on a new one.
+type Document struct {
+	pages [][]item
+	y     float64
+}
+
+func New() *Document {
+	return &Document{pages: [][]item{nil}, y: pageHeight - margin}
+}
+
+// Heading writes a line of bold text.
+func (d *Document) Heading(text string, size float64) {
+	d.advance(size * 1.4)
+	d.add(item{x: margin, y: d.y, size: size, bold: true, text: text})
+}
+
+// Line writes a line of regular text.
+func (d *Document) Line(text string) {
+	d.advance(14)
+	d.add(item{x: margin, y: d.y, size: 10, text: text})
+}
+
+// Row writes cells on one line, each starting at the matching offset from the
+// left margin.
+func (d *Document) Row(bold bool, offsets []float64, cells ...string) {
+	d.advance(14)
+	for i, cell := range cells {
+		if i >= len(offsets) {
+			break
+		}
+		d.add(item{x: margin + offsets[i], y: d.y, size: 10, bold: bold, text: cell})
+	}
+}
+
+// Gap leaves vertical space.
+func (d *Document) Gap() {
+	d.advance(10)
+}
+
+func (d *Document) advance(height float64) {
+	d.y -= height
+	if d.y < margin {
+		d.pages = append(d.pages, nil)
+		d.y = pageHeight - margin - height
+	}
+}
+
+func (d *Document) add(it item) {
+	last := len(d.pages) - 1
+	d.pages[last] = append(d.pages[last], it)
+}
+
+// WriteTo renders the document.
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	var buf bytes.Buffer
+	var offsets []int
+
+	object := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	buf.WriteString("%PDF-1.4\n")
+
+	// Objects 1-4 are the catalog, the page tree and the two fonts; each page
+	// then takes a page object followed by its content stream.
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+2*i)
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+
+	for i, page := range d.pages {
+		var content bytes.Buffer
+		for _, it := range page {
+			font := "F1"
+			if it.bold {
+				font = "F2"
+			}
+			fmt.Fprintf(&content, "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n", font, it.size, it.x, it.y, escape(it.text))
+		}
+
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			pageWidth, pageHeight, 6+2*i))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.String()))
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	return buf.WriteTo(w)
+}
+
+// escape encodes text as a PDF string literal body. Characters outside
+// Latin-1 have no glyph in the standard fonts and are replaced.
+func escape(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r < 32:
+			b.WriteByte(' ')
+		case r < 256:
+			b.WriteByte(byte(r))
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
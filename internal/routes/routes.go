@@ -12,25 +12,157 @@ func SetupRoutes(r *gin.Engine) {
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 	r.POST("/register", controller.Register)
 	r.POST("/login", controller.Login)
+	r.POST("/login/2fa", controller.LoginTwoFactor)
+	r.POST("/refresh", controller.Refresh)
+	r.GET("/.well-known/jwks.json", utils.JWKSHandler)
+	r.POST("/verify-email", controller.VerifyEmail)
+	r.POST("/password-reset", controller.RequestPasswordReset)
+	r.POST("/password-reset/confirm", controller.ResetPassword)
+	r.POST("/api/logout", utils.AuthMiddleware(utils.AnyUser), controller.Logout)
+	r.POST("/api/logout-all", utils.AuthMiddleware(utils.AnyUser), controller.LogoutAll)
 
 	client := r.Group("/api/client")
-	client.POST("/tenders", utils.AuthMiddleware([]string{"client"}), controller.CreateTenderHandler)
-	client.GET("/tenders", utils.AuthMiddleware([]string{"client", "contractor"}), controller.ListTendersHandler)
-	client.PUT("/tenders/:id", utils.AuthMiddleware([]string{"client"}), controller.UpdateTenderStatusHandler)
-	client.DELETE("/tenders/:id", utils.AuthMiddleware([]string{"client"}), controller.DeleteTenderHandler)
-	client.GET("/tenders/:id/bids", utils.AuthMiddleware([]string{"client"}), controller.GetBidsByTenderID)
-	client.POST("/tenders/:id/award/:bidId", utils.AuthMiddleware([]string{"client"}), controller.AwardBidHandler)
+	client.POST("/tenders", utils.AuthMiddleware(utils.PermTenderCreate), controller.CreateTenderHandler)
+	client.GET("/tenders", utils.AuthMiddleware(utils.PermTenderView), controller.ListTendersHandler)
+	client.PUT("/tenders/:id", utils.AuthMiddleware(utils.PermTenderEdit), controller.UpdateTenderStatusHandler)
+	client.DELETE("/tenders/:id", utils.AuthMiddleware(utils.PermTenderEdit), controller.DeleteTenderHandler)
+	client.PUT("/tenders/:id/draft", utils.AuthMiddleware(utils.PermTenderEdit), controller.UpdateDraftTenderHandler)
+	client.POST("/tenders/:id/publish", utils.AuthMiddleware(utils.PermTenderEdit), controller.PublishTenderHandler)
+	client.GET("/tenders/:id/bids", utils.AuthMiddleware(utils.PermBidView), controller.GetBidsByTenderID)
+	client.GET("/tenders/:id/bids/comparison", utils.AuthMiddleware(utils.PermBidView), controller.CompareBidsHandler)
+	client.PUT("/tenders/:id/boq", utils.AuthMiddleware(utils.PermTenderEdit), controller.SetBoQHandler)
+	client.GET("/tenders/:id/boq", utils.AuthMiddleware(utils.PermTenderView), controller.GetBoQHandler)
+	client.POST("/tenders/:id/award/:bidId", utils.AuthMiddleware(utils.PermAwardApprove), controller.AwardBidHandler)
+	client.POST("/tenders/:id/contracts", utils.AuthMiddleware(utils.PermContractManage), controller.CreateContractHandler)
+	client.GET("/tenders/:id/protests", utils.AuthMiddleware(utils.PermTenderView), controller.ListTenderProtestsHandler)
+	client.POST("/protests/:id/resolve", utils.AuthMiddleware(utils.PermAwardApprove), controller.ResolveProtestHandler)
+	client.POST("/tenders/:id/shortlist", utils.AuthMiddleware(utils.PermTenderEdit), controller.ShortlistHandler)
+	client.POST("/tenders/:id/rfp", utils.AuthMiddleware(utils.PermTenderEdit), controller.OpenRFPHandler)
+	client.GET("/tenders/:id/declaration", utils.AuthMiddleware(utils.PermBidView), controller.GetDeclarationFormHandler)
+	client.POST("/tenders/:id/declaration", utils.AuthMiddleware(utils.PermBidView), controller.SignDeclarationHandler)
+	client.GET("/tenders/:id/declarations", utils.AuthMiddleware(utils.PermEvaluationManage), controller.ListDeclarationsHandler)
+	client.POST("/tenders/:id/evaluators", utils.AuthMiddleware(utils.PermEvaluationManage), controller.AppointEvaluatorsHandler)
+	client.GET("/tenders/:id/evaluators", utils.AuthMiddleware(utils.PermEvaluationManage), controller.ListEvaluatorsHandler)
+	client.DELETE("/tenders/:id/evaluators/:evaluatorId", utils.AuthMiddleware(utils.PermEvaluationManage), controller.RemoveEvaluatorHandler)
+	client.PUT("/tenders/:id/requirements", utils.AuthMiddleware(utils.PermTenderEdit), controller.SetRequirementsHandler)
+	client.GET("/tenders/:id/requirements", utils.AuthMiddleware(utils.PermTenderView), controller.GetRequirementsHandler)
+	client.POST("/tenders/:id/lots", utils.AuthMiddleware(utils.PermTenderEdit), controller.CreateLotHandler)
+	client.GET("/tenders/:id/lots", utils.AuthMiddleware(utils.PermTenderView), controller.ListLotsHandler)
+	client.DELETE("/tenders/:id/lots/:lotId", utils.AuthMiddleware(utils.PermTenderEdit), controller.DeleteLotHandler)
+	client.POST("/tenders/:id/lots/:lotId/award/:bidId", utils.AuthMiddleware(utils.PermAwardApprove), controller.AwardLotHandler)
+	client.POST("/tenders/:id/lots/:lotId/cancel", utils.AuthMiddleware(utils.PermAwardApprove), controller.CancelLotHandler)
+	client.POST("/tenders/:id/attachments", utils.AuthMiddleware(utils.PermTenderEdit), controller.UploadTenderAttachmentHandler)
+	client.GET("/tenders/:id/attachments", utils.AuthMiddleware(utils.PermTenderView), controller.ListTenderAttachmentsHandler)
+	client.POST("/tenders/:id/invitations", utils.AuthMiddleware(utils.PermTenderEdit), controller.InviteContractorsHandler)
+	client.GET("/tenders/:id/invitations", utils.AuthMiddleware(utils.PermTenderEdit), controller.ListInvitationsHandler)
+	client.POST("/tenders/:id/template", utils.AuthMiddleware(utils.PermTenderCreate), controller.SaveTenderTemplateHandler)
+	client.POST("/tenders/:id/clone", utils.AuthMiddleware(utils.PermTenderCreate), controller.CloneTenderHandler)
+	client.GET("/qualifications/review", utils.AuthMiddleware(utils.PermQualificationReview), controller.QualificationReviewQueueHandler)
+	client.POST("/qualifications/:id/approve", utils.AuthMiddleware(utils.PermQualificationReview), controller.ApproveQualificationHandler)
+	client.POST("/qualifications/:id/reject", utils.AuthMiddleware(utils.PermQualificationReview), controller.RejectQualificationHandler)
+	client.GET("/templates", utils.AuthMiddleware(utils.PermTenderCreate), controller.ListTemplatesHandler)
+	client.DELETE("/templates/:id", utils.AuthMiddleware(utils.PermTenderCreate), controller.DeleteTemplateHandler)
+	client.POST("/templates/:id/tenders", utils.AuthMiddleware(utils.PermTenderCreate), controller.CreateTenderFromTemplateHandler)
 
 	contractor := r.Group("/api/contractor")
-	contractor.POST("/tenders/:id/bid", utils.AuthMiddleware([]string{"contractor"}), controller.CreateBidHandler)
-	contractor.GET("/bids", utils.AuthMiddleware([]string{"contractor"}), controller.GetBidsByContractor)
-	contractor.GET("/bids/:id", utils.AuthMiddleware([]string{"contractor"}), controller.GetBidByIDHandler)
-	contractor.DELETE("/bids/:id", utils.AuthMiddleware([]string{"contractor"}), controller.DeleteBidHandler)
-	contractor.PUT("/bids/:id", utils.AuthMiddleware([]string{"contractor"}), controller.UpdateBidStatusHandler)
+	contractor.GET("/tenders", utils.AuthMiddleware(utils.PermTenderBrowse), controller.ListOpenTendersHandler)
+	contractor.GET("/tenders/:id/eligibility", utils.AuthMiddleware(utils.PermTenderBrowse), controller.TenderEligibilityHandler)
+	contractor.POST("/qualifications", utils.AuthMiddleware(utils.PermBidManage), controller.SubmitQualificationHandler)
+	contractor.GET("/qualifications", utils.AuthMiddleware(utils.PermBidView), controller.ListQualificationsHandler)
+	contractor.DELETE("/qualifications/:id", utils.AuthMiddleware(utils.PermBidManage), controller.DeleteQualificationHandler)
+	contractor.POST("/tenders/:id/eoi", utils.AuthMiddleware(utils.PermBidCreate), controller.SubmitEOIHandler)
+	contractor.POST("/tenders/:id/bid", utils.AuthMiddleware(utils.PermBidCreate), controller.CreateBidHandler)
+	contractor.POST("/tenders/:id/protests", utils.AuthMiddleware(utils.PermBidManage), controller.FileProtestHandler)
+	contractor.GET("/protests", utils.AuthMiddleware(utils.PermBidView), controller.ListOwnProtestsHandler)
+	contractor.POST("/protests/:id/documents", utils.AuthMiddleware(utils.PermBidManage), controller.UploadProtestDocumentHandler)
+	contractor.GET("/bids", utils.AuthMiddleware(utils.PermBidView), controller.GetBidsByContractor)
+	contractor.GET("/bids/:id", utils.AuthMiddleware(utils.PermBidView), controller.GetBidByIDHandler)
+	contractor.DELETE("/bids/:id", utils.AuthMiddleware(utils.PermBidManage), controller.DeleteBidHandler)
+	contractor.PUT("/bids/:id", utils.AuthMiddleware(utils.PermBidManage), controller.UpdateBidStatusHandler)
+	contractor.POST("/bids/:id/attachments", utils.AuthMiddleware(utils.PermBidManage), controller.UploadBidAttachmentHandler)
+
+	evaluation := r.Group("/api/evaluations")
+	evaluation.GET("/tenders/:id", utils.AuthMiddleware(utils.PermEvaluationScore), controller.GetEvaluationSheetHandler)
+	evaluation.PUT("/tenders/:id/scores", utils.AuthMiddleware(utils.PermEvaluationScore), controller.SaveScoresHandler)
+	evaluation.POST("/tenders/:id/submit", utils.AuthMiddleware(utils.PermEvaluationScore), controller.SubmitScoresHandler)
+	evaluation.GET("/tenders/:id/results", utils.AuthMiddleware(utils.PermEvaluationScore), controller.GetEvaluationResultsHandler)
+	evaluation.POST("/tenders/:id/signoff", utils.AuthMiddleware(utils.PermEvaluationScore), controller.SignOffEvaluationHandler)
+
+	attachment := r.Group("/api/attachments")
+	attachment.GET("/:id", utils.AuthMiddleware(utils.PermTenderView), controller.DownloadAttachmentHandler)
+	attachment.DELETE("/:id", utils.AuthMiddleware(utils.AnyUser), controller.DeleteAttachmentHandler)
+	attachment.GET("/bids/:id", utils.AuthMiddleware(utils.PermBidView), controller.ListBidAttachmentsHandler)
+
+	r.GET("/api/qualifications/:id/document", utils.AuthMiddleware(utils.PermBidView), controller.DownloadQualificationHandler)
+
+	contract := r.Group("/api/contracts")
+	contract.GET("", utils.AuthMiddleware(utils.PermContractView), controller.ListContractsHandler)
+	contract.GET("/:id", utils.AuthMiddleware(utils.PermContractView), controller.GetContractHandler)
+	contract.POST("/:id/accept", utils.AuthMiddleware(utils.PermContractManage), controller.AcceptContractHandler)
+	contract.POST("/:id/complete", utils.AuthMiddleware(utils.PermContractManage), controller.CompleteContractHandler)
+	contract.POST("/:id/terminate", utils.AuthMiddleware(utils.PermContractManage), controller.TerminateContractHandler)
+	contract.PUT("/:id/milestones", utils.AuthMiddleware(utils.PermContractManage), controller.SetMilestonesHandler)
+	contract.GET("/:id/milestones", utils.AuthMiddleware(utils.PermContractView), controller.ListMilestonesHandler)
+	contract.POST("/:id/milestones/:milestoneId/deliver", utils.AuthMiddleware(utils.PermContractManage), controller.DeliverMilestoneHandler)
+	contract.POST("/:id/milestones/:milestoneId/documents", utils.AuthMiddleware(utils.PermContractManage), controller.UploadMilestoneDocumentHandler)
+	contract.POST("/:id/milestones/:milestoneId/accept", utils.AuthMiddleware(utils.PermContractManage), controller.AcceptMilestoneHandler)
+	contract.POST("/:id/milestones/:milestoneId/reject", utils.AuthMiddleware(utils.PermContractManage), controller.RejectMilestoneHandler)
+
+	contract.POST("/:id/reviews", utils.AuthMiddleware(utils.PermContractManage), controller.SubmitReviewHandler)
+
+	contract.POST("/:id/invoices", utils.AuthMiddleware(utils.PermInvoiceCreate), controller.CreateInvoiceHandler)
+	contract.GET("/:id/invoices", utils.AuthMiddleware(utils.PermContractView), controller.ListInvoicesHandler)
+	contract.GET("/:id/invoices/reconciliation", utils.AuthMiddleware(utils.PermContractView), controller.ReconcileInvoicesHandler)
+
+	invoice := r.Group("/api/invoices")
+	invoice.GET("/:id", utils.AuthMiddleware(utils.PermContractView), controller.GetInvoiceHandler)
+	invoice.GET("/:id/pdf", utils.AuthMiddleware(utils.PermContractView), controller.DownloadInvoicePDFHandler)
+	invoice.POST("/:id/approve", utils.AuthMiddleware(utils.PermInvoiceApprove), controller.ApproveInvoiceHandler)
+	invoice.POST("/:id/dispute", utils.AuthMiddleware(utils.PermInvoiceApprove), controller.DisputeInvoiceHandler)
+	invoice.POST("/:id/payment", utils.AuthMiddleware(utils.PermInvoiceApprove), controller.RecordPaymentHandler)
+
+	r.PUT("/api/reviews/:id", utils.AuthMiddleware(utils.PermContractManage), controller.UpdateReviewHandler)
+
+	r.GET("/api/milestones/documents/:id", utils.AuthMiddleware(utils.PermContractView), controller.DownloadMilestoneDocumentHandler)
+
+	r.GET("/api/protests/documents/:id", utils.AuthMiddleware(utils.PermBidView), controller.DownloadProtestDocumentHandler)
+
+	r.GET("/api/exchange-rates", utils.AuthMiddleware(utils.AnyUser), controller.ListExchangeRatesHandler)
+	r.PUT("/api/exchange-rates", utils.AuthMiddleware(utils.PermPlatformAdmin), controller.SetExchangeRateHandler)
 
 	user := r.Group("/api/users")
-	user.GET("/:id/bids", utils.AuthMiddleware([]string{"client", "contractor"}), controller.GetContractorBidHistory)
-	user.GET("/:id/tenders", utils.AuthMiddleware([]string{"client", "contractor"}), controller.GetClientTenderHistory)
+	user.GET("/me", utils.AuthMiddleware(utils.AnyUser), controller.GetMyProfileHandler)
+	user.PUT("/me", utils.AuthMiddleware(utils.AnyUser), controller.UpdateMyProfileHandler)
+	user.PUT("/me/password", utils.AuthMiddleware(utils.AnyUser), controller.ChangePassword)
+	user.POST("/me/verify-email", utils.AuthMiddleware(utils.AnyUser), controller.ResendVerification)
+	user.GET("/me/2fa", utils.AuthMiddleware(utils.AnyUser), controller.GetTwoFactorStatusHandler)
+	user.POST("/me/2fa/setup", utils.AuthMiddleware(utils.AnyUser), controller.SetupTwoFactorHandler)
+	user.POST("/me/2fa/enable", utils.AuthMiddleware(utils.AnyUser), controller.EnableTwoFactorHandler)
+	user.POST("/me/2fa/disable", utils.AuthMiddleware(utils.AnyUser), controller.DisableTwoFactorHandler)
+	user.POST("/me/2fa/recovery-codes", utils.AuthMiddleware(utils.AnyUser), controller.RegenerateRecoveryCodesHandler)
+	user.GET("/:id", utils.AuthMiddleware(utils.AnyUser), controller.GetUserProfileHandler)
+	user.GET("/:id/bids", utils.AuthMiddleware(utils.AnyUser), controller.GetContractorBidHistory)
+	user.GET("/:id/tenders", utils.AuthMiddleware(utils.AnyUser), controller.GetClientTenderHistory)
+	user.GET("/:id/reviews", utils.AuthMiddleware(utils.AnyUser), controller.ListUserReviewsHandler)
+	user.GET("/:id/reputation", utils.AuthMiddleware(utils.AnyUser), controller.GetUserReputationHandler)
 
 	user.GET("/notification/ws", utils.WebSocketHandler)
+
+	admin := r.Group("/api/admin")
+	admin.GET("/users", utils.AuthMiddleware(utils.PermPlatformAdmin), controller.AdminListUsersHandler)
+	admin.POST("/users/:id/suspend", utils.AuthMiddleware(utils.PermPlatformAdmin), controller.AdminSuspendUserHandler)
+	admin.POST("/users/:id/reactivate", utils.AuthMiddleware(utils.PermPlatformAdmin), controller.AdminReactivateUserHandler)
+	admin.POST("/tenders/:id/close", utils.AuthMiddleware(utils.PermPlatformAdmin), controller.AdminCloseTenderHandler)
+	admin.POST("/bids/:id/remove", utils.AuthMiddleware(utils.PermPlatformAdmin), controller.AdminRemoveBidHandler)
+	admin.GET("/stats", utils.AuthMiddleware(utils.PermPlatformAdmin), controller.AdminStatsHandler)
+	admin.GET("/audit", utils.AuthMiddleware(utils.PermPlatformAdmin), controller.AdminAuditTrailHandler)
+
+	org := r.Group("/api/orgs")
+	org.POST("", utils.AuthMiddleware(utils.AnyUser), controller.CreateOrganizationHandler)
+	org.GET("/:id", utils.AuthMiddleware(utils.AnyUser), controller.GetOrganizationHandler)
+	org.PUT("/:id", utils.AuthMiddleware(utils.PermOrgManage), controller.UpdateOrganizationHandler)
+	org.GET("/:id/members", utils.AuthMiddleware(utils.AnyUser), controller.ListMembersHandler)
+	org.POST("/:id/members", utils.AuthMiddleware(utils.PermOrgManage), controller.AddMemberHandler)
+	org.DELETE("/:id/members/:userId", utils.AuthMiddleware(utils.AnyUser), controller.RemoveMemberHandler)
+	org.PUT("/:id/members/:userId/role", utils.AuthMiddleware(utils.PermOrgManage), controller.SetMemberRoleHandler)
 }
@@ -0,0 +1,166 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	repository "tender-managment/internal/db/repo"
+	"tender-managment/internal/model"
+	"tender-managment/internal/utils"
+)
+
+type AdminService struct {
+	repo       *repository.AdminRepository
+	userRepo   *repository.UserRepository
+	tenderRepo *repository.TenderRepository
+	bidRepo    *repository.BidRepository
+	rates      *ExchangeRateService
+}
+
+func NewAdminService(repo *repository.AdminRepository, userRepo *repository.UserRepository, tenderRepo *repository.TenderRepository, bidRepo *repository.BidRepository, rates *ExchangeRateService) *AdminService {
+	return &AdminService{
+		repo:       repo,
+		userRepo:   userRepo,
+		tenderRepo: tenderRepo,
+		bidRepo:    bidRepo,
+		rates:      rates,
+	}
+}
+
+func (s *AdminService) SearchUsers(filter model.UserSearch) ([]model.UserProfile, error) {
+	return s.userRepo.SearchUsers(filter)
+}
+
+// SuspendUser blocks an account from signing in and using the API.
+func (s *AdminService) SuspendUser(adminID, userID int, reason string) error {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return errors.New("a reason is required")
+	}
+	if _, err := s.userRepo.GetUserByID(userID); err != nil {
+		return errors.New("user not found")
+	}
+
+	if err := s.userRepo.Suspend(userID, reason); err != nil {
+		return err
+	}
+
+	s.record(adminID, model.AdminActionSuspendUser, "user", userID, reason)
+	utils.CloseUserSocket(userID, "Account suspended")
+	return nil
+}
+
+func (s *AdminService) ReactivateUser(adminID, userID int) error {
+	if _, err := s.userRepo.GetUserByID(userID); err != nil {
+		return errors.New("user not found")
+	}
+
+	if err := s.userRepo.Reactivate(userID); err != nil {
+		return err
+	}
+
+	s.record(adminID, model.AdminActionReactivateUser, "user", userID, "")
+	return nil
+}
+
+// CloseTender force-closes a fraudulent tender before it is awarded and tells
+// its owner and bidders why.
+func (s *AdminService) CloseTender(adminID, tenderID int, reason string) (*model.Tender, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, errors.New("a reason is required")
+	}
+	tender, err := s.tenderRepo.GetTenderByID(tenderID)
+	if err != nil {
+		return nil, errors.New("tender not found")
+	}
+	if tender.Status != model.TenderStatusDraft && tender.Status != model.TenderStatusOpen {
+		return nil, errors.New("only draft or open tenders can be closed")
+	}
+
+	if err := s.tenderRepo.UpdateTenderStatus(tenderID, model.TenderStatusClosed); err != nil {
+		return nil, err
+	}
+	s.record(adminID, model.AdminActionCloseTender, "tender", tenderID, reason)
+
+	message := fmt.Sprintf("Tender %s has been closed by the platform administrators: %s", tender.Title, reason)
+	utils.SendNotification(*s.bidRepo, tender.ClientID, message, strconv.Itoa(tenderID), "tender_closed_by_admin")
+	bids, err := s.bidRepo.GetBidsByTenderID(tenderID)
+	if err != nil {
+		log.Println("Error fetching bids for notification:", err)
+		return tender, nil
+	}
+	notified := make(map[int]bool)
+	for _, bid := range bids {
+		if notified[bid.ContractorID] {
+			continue
+		}
+		notified[bid.ContractorID] = true
+		utils.SendNotification(*s.bidRepo, bid.ContractorID, message, strconv.Itoa(tenderID), "tender_closed_by_admin")
+	}
+
+	return tender, nil
+}
+
+// RemoveBid deletes an abusive bid. Awarded bids may already be under
+// contract and cannot be removed.
+func (s *AdminService) RemoveBid(adminID, bidID int, reason string) (*model.Bid, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, errors.New("a reason is required")
+	}
+	bid, err := s.bidRepo.GetBidByID(bidID)
+	if err != nil {
+		return nil, errors.New("bid not found")
+	}
+	if bid.Status == model.BidStatusAwarded {
+		return nil, errors.New("awarded bids cannot be removed")
+	}
+
+	if err := s.bidRepo.DeleteBid(bidID); err != nil {
+		return nil, err
+	}
+	details := fmt.Sprintf("tender %d, contractor %d: %s", bid.TenderID, bid.ContractorID, reason)
+	s.record(adminID, model.AdminActionRemoveBid, "bid", bidID, details)
+
+	message := fmt.Sprintf("Your bid #%d has been removed by the platform administrators: %s", bidID, reason)
+	utils.SendNotification(*s.bidRepo, bid.ContractorID, message, strconv.Itoa(bid.TenderID), "bid_removed_by_admin")
+
+	return bid, nil
+}
+
+func (s *AdminService) GetStats() (*model.PlatformStats, error) {
+	return s.repo.GetStats()
+}
+
+func (s *AdminService) ListActions(limit, offset int) ([]model.AdminAction, error) {
+	return s.repo.ListActions(limit, offset)
+}
+
+func (s *AdminService) SetExchangeRate(adminID int, payload model.SetExchangeRate) (*model.ExchangeRate, error) {
+	rate, err := s.rates.SetRate(payload.BaseCurrency, payload.QuoteCurrency, payload.Rate)
+	if err != nil {
+		return nil, err
+	}
+
+	details := fmt.Sprintf("%s/%s = %s", rate.BaseCurrency, rate.QuoteCurrency, rate.Rate)
+	s.record(adminID, model.AdminActionSetRate, "exchange_rate", 0, details)
+	return rate, nil
+}
+
+// record appends to the audit trail. The action itself has already happened,
+// so a failure is logged rather than returned.
+func (s *AdminService) record(adminID int, action, targetType string, targetID int, details string) {
+	err := s.repo.RecordAction(&model.AdminAction{
+		AdminID:    adminID,
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetID,
+		Details:    details,
+	})
+	if err != nil {
+		log.Println("Error recording admin action:", err)
+	}
+}
@@ -0,0 +1,273 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	repository "tender-managment/internal/db/repo"
+	"tender-managment/internal/model"
+	"tender-managment/internal/storage"
+)
+
+type AttachmentService struct {
+	repo         *repository.AttachmentRepository
+	tenderRepo   *repository.TenderRepository
+	bidRepo      *repository.BidRepository
+	evaluations  *EvaluationService
+	storage      storage.Storage
+	maxFileSize  int64
+	allowedTypes []string
+}
+
+func NewAttachmentService(repo *repository.AttachmentRepository, tenderRepo *repository.TenderRepository, bidRepo *repository.BidRepository, evaluations *EvaluationService, store storage.Storage, maxFileSize int64, allowedTypes []string) *AttachmentService {
+	return &AttachmentService{
+		repo:         repo,
+		tenderRepo:   tenderRepo,
+		bidRepo:      bidRepo,
+		evaluations:  evaluations,
+		storage:      store,
+		maxFileSize:  maxFileSize,
+		allowedTypes: allowedTypes,
+	}
+}
+
+func (s *AttachmentService) UploadTenderAttachment(ctx context.Context, clientID, tenderID int, fileName string, r io.Reader) (*model.Attachment, int, error) {
+	tender, err := s.tenderRepo.GetTenderByID(tenderID)
+	if err != nil || !managesTender(s.tenderRepo, tender, clientID) {
+		return nil, http.StatusNotFound, errors.New("Tender not found or access denied")
+	}
+
+	attachment := &model.Attachment{
+		TenderID:   tenderID,
+		UploadedBy: clientID,
+		FileName:   fileName,
+	}
+	return s.store(ctx, attachment, r)
+}
+
+func (s *AttachmentService) UploadBidAttachment(ctx context.Context, contractorID, bidID int, fileName string, r io.Reader) (*model.Attachment, int, error) {
+	bid, err := s.bidRepo.GetBidByID(bidID)
+	if err != nil || !managesBid(s.bidRepo, bid, contractorID) {
+		return nil, http.StatusNotFound, errors.New("Bid not found or access denied")
+	}
+	if bid.Status != model.BidStatusPending {
+		return nil, http.StatusBadRequest, errors.New("attachments can only be added to pending bids")
+	}
+
+	id, _ := strconv.Atoi(bid.ID)
+	attachment := &model.Attachment{
+		TenderID:   bid.TenderID,
+		BidID:      &id,
+		UploadedBy: contractorID,
+		FileName:   fileName,
+	}
+	return s.store(ctx, attachment, r)
+}
+
+func (s *AttachmentService) store(ctx context.Context, attachment *model.Attachment, r io.Reader) (*model.Attachment, int, error) {
+	attachment.FileName = filepath.Base(attachment.FileName)
+	if attachment.FileName == "." || attachment.FileName == string(filepath.Separator) {
+		return nil, http.StatusBadRequest, errors.New("invalid file name")
+	}
+
+	key, err := newStorageKey(fmt.Sprintf("tenders/%d", attachment.TenderID))
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	blob, status, err := saveBlob(ctx, s.storage, key, r, s.maxFileSize, s.allowedTypes)
+	if err != nil {
+		return nil, status, err
+	}
+
+	attachment.StorageKey = key
+	attachment.MimeType = blob.MimeType
+	attachment.Size = blob.Size
+	attachment.SHA256 = blob.SHA256
+
+	created, err := s.repo.CreateAttachment(attachment)
+	if err != nil {
+		_ = s.storage.Delete(ctx, key)
+		return nil, http.StatusInternalServerError, err
+	}
+
+	return created, http.StatusCreated, nil
+}
+
+func (s *AttachmentService) ListTenderAttachments(userID int, role string, tenderID int) ([]model.Attachment, error) {
+	if err := s.canAccess(userID, role, tenderID, nil); err != nil {
+		return nil, errors.New("Tender not found or access denied")
+	}
+	return s.repo.GetAttachmentsByTenderID(tenderID)
+}
+
+func (s *AttachmentService) ListBidAttachments(userID int, role string, bidID int) ([]model.Attachment, error) {
+	bid, err := s.bidRepo.GetBidByID(bidID)
+	if err != nil {
+		return nil, errors.New("Bid not found or access denied")
+	}
+	if err := s.canAccess(userID, role, bid.TenderID, &bidID); err != nil {
+		if isDeclarationError(err) {
+			return nil, err
+		}
+		return nil, errors.New("Bid not found or access denied")
+	}
+	return s.repo.GetAttachmentsByBidID(bidID)
+}
+
+// OpenAttachment returns the attachment metadata together with its contents.
+// The caller must close the returned reader.
+func (s *AttachmentService) OpenAttachment(ctx context.Context, userID int, role string, attachmentID int) (*model.Attachment, io.ReadCloser, error) {
+	attachment, err := s.repo.GetAttachmentByID(attachmentID)
+	if err != nil {
+		return nil, nil, errors.New("attachment not found")
+	}
+	if err := s.canAccess(userID, role, attachment.TenderID, attachment.BidID); err != nil {
+		if isDeclarationError(err) {
+			return nil, nil, err
+		}
+		return nil, nil, errors.New("attachment not found")
+	}
+
+	file, err := s.storage.Open(ctx, attachment.StorageKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open attachment: %w", err)
+	}
+
+	return attachment, file, nil
+}
+
+func (s *AttachmentService) DeleteAttachment(ctx context.Context, userID, attachmentID int) error {
+	attachment, err := s.repo.GetAttachmentByID(attachmentID)
+	if err != nil || attachment.UploadedBy != userID {
+		return errors.New("attachment not found")
+	}
+
+	if err := s.repo.DeleteAttachment(attachmentID); err != nil {
+		return err
+	}
+
+	count, err := s.repo.CountByStorageKey(attachment.StorageKey)
+	if err == nil && count == 0 {
+		_ = s.storage.Delete(ctx, attachment.StorageKey)
+	}
+
+	return nil
+}
+
+// canAccess lets the tender owner, their organization and the appointed
+// evaluators see the tender files. Bid files are visible to the contractor who
+// submitted the bid and, once they have declared no conflict of interest, to
+// the tender owner and the evaluators. Tender files are also visible to any
+// contractor while the tender is open or who has already bid on it.
+func (s *AttachmentService) canAccess(userID int, role string, tenderID int, bidID *int) error {
+	denied := errors.New("access denied")
+
+	tender, err := s.tenderRepo.GetTenderByID(tenderID)
+	if err != nil {
+		return denied
+	}
+
+	if bidID != nil {
+		if role == "contractor" {
+			bid, err := s.bidRepo.GetBidByID(*bidID)
+			if err != nil || !managesBid(s.bidRepo, bid, userID) {
+				return denied
+			}
+			return nil
+		}
+		if err := s.evaluations.CheckBidAccess(userID, tenderID); err != nil {
+			if isDeclarationError(err) {
+				return err
+			}
+			return denied
+		}
+		return nil
+	}
+
+	if _, err := s.evaluations.checkBidViewer(userID, tenderID); err == nil {
+		return nil
+	}
+	if role != "contractor" {
+		return denied
+	}
+
+	if tender.Visibility == model.TenderVisibilityInviteOnly {
+		invited, err := s.tenderRepo.IsInvited(tenderID, userID)
+		if err != nil || !invited {
+			return denied
+		}
+	}
+
+	if tender.Status == "open" {
+		return nil
+	}
+	hasBid, err := s.bidRepo.HasContractorBid(tenderID, userID)
+	if err != nil || !hasBid {
+		return denied
+	}
+	return nil
+}
+
+func newStorageKey(prefix string) (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate storage key: %w", err)
+	}
+	return prefix + "/" + hex.EncodeToString(buf), nil
+}
+
+type storedBlob struct {
+	MimeType string
+	Size     int64
+	SHA256   string
+}
+
+// saveBlob streams an upload into storage under key. The content type is
+// sniffed from the first bytes and checked against allowedTypes, and uploads
+// larger than maxFileSize are removed again.
+func saveBlob(ctx context.Context, store storage.Storage, key string, r io.Reader, maxFileSize int64, allowedTypes []string) (*storedBlob, int, error) {
+	reader := bufio.NewReaderSize(r, 512)
+	head, err := reader.Peek(512)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, http.StatusBadRequest, fmt.Errorf("failed to read file: %w", err)
+	}
+	if len(head) == 0 {
+		return nil, http.StatusBadRequest, errors.New("file is empty")
+	}
+
+	mimeType := http.DetectContentType(head)
+	if !contains(allowedTypes, mimeType) {
+		return nil, http.StatusUnsupportedMediaType, fmt.Errorf("file type %s is not allowed", mimeType)
+	}
+
+	hash := sha256.New()
+	counter := &countingWriter{}
+	limited := io.LimitReader(reader, maxFileSize+1)
+	if err := store.Save(ctx, key, io.TeeReader(limited, io.MultiWriter(hash, counter))); err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to store file: %w", err)
+	}
+	if counter.n > maxFileSize {
+		_ = store.Delete(ctx, key)
+		return nil, http.StatusRequestEntityTooLarge, fmt.Errorf("file exceeds the maximum size of %d bytes", maxFileSize)
+	}
+
+	return &storedBlob{MimeType: mimeType, Size: counter.n, SHA256: hex.EncodeToString(hash.Sum(nil))}, http.StatusOK, nil
+}
+
+type countingWriter struct {
+	n int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.n += int64(len(p))
+	return len(p), nil
+}
@@ -1,76 +1,454 @@
 package service
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
+	"fmt"
+	"log"
 	"net/http"
+	"net/mail"
 	"strings"
 	repository "tender-managment/internal/db/repo"
+	"tender-managment/internal/mailer"
+	"tender-managment/internal/model"
 	"tender-managment/internal/utils"
+	"time"
 )
 
+const (
+	verifyEmailTTL   = 48 * time.Hour
+	resetPasswordTTL = time.Hour
+)
+
+// dummyPasswordHash is compared against when the username does not exist, so
+// unknown and known usernames take equally long to reject.
+var dummyPasswordHash, _ = utils.EncodePassword("dummy password for unknown users")
+
 type AuthService struct {
-	repo *repository.UserRepository
+	repo        *repository.UserRepository
+	tokens      *repository.RefreshTokenRepository
+	emailTokens *repository.EmailTokenRepository
+	mailer      mailer.Mailer
+	twoFactor   *TwoFactorService
+	throttle    *LoginThrottle
+	appURL      string
+	refreshTTL  time.Duration
 }
 
-func NewAuthService(repo *repository.UserRepository) *AuthService {
-	return &AuthService{repo: repo}
+func NewAuthService(repo *repository.UserRepository, tokens *repository.RefreshTokenRepository, emailTokens *repository.EmailTokenRepository, sender mailer.Mailer, twoFactor *TwoFactorService, throttle *LoginThrottle, appURL string, refreshTTL time.Duration) *AuthService {
+	return &AuthService{
+		repo:        repo,
+		tokens:      tokens,
+		emailTokens: emailTokens,
+		mailer:      sender,
+		twoFactor:   twoFactor,
+		throttle:    throttle,
+		appURL:      strings.TrimRight(appURL, "/"),
+		refreshTTL:  refreshTTL,
+	}
 }
 
-func (as *AuthService) RegisterUser(username, email, password, role string) (string, error) {
+// RegisterUser creates the account and mails a link to verify the email
+// address. Tenders and bids can only be created once it is verified.
+func (as *AuthService) RegisterUser(ctx context.Context, username, email, password, role string) (*model.TokenPair, error) {
 	if role != "client" && role != "contractor" {
-		return "", errors.New("invalid role")
+		return nil, errors.New("invalid role")
 	}
 	if username == "" || email == "" {
-		return "", errors.New("username or email cannot be empty")
+		return nil, errors.New("username or email cannot be empty")
 	}
 
-	if strings.Contains(email, "-") && !strings.Contains(email, "@") {
-		return "", errors.New("invalid email format")
+	if address, err := mail.ParseAddress(email); err != nil || address.Address != email {
+		return nil, errors.New("invalid email format")
+	}
+	if err := utils.ValidatePassword(password, username, email); err != nil {
+		return nil, err
 	}
 
 	user, _ := as.repo.GetUserByEmail(email)
 
 	if user != nil {
-		return "", errors.New("Email already exists")
+		return nil, errors.New("Email already exists")
 	}
 
 	hashedPassword, err := utils.EncodePassword(password)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	userId, err := as.repo.CreateUser(username, email, hashedPassword, role)
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+
+	if err := as.sendVerification(ctx, userId, email); err != nil {
+		log.Println("Error sending verification email:", err)
 	}
 
-	token, err := utils.GenerateToken(userId, role)
+	return as.issueTokens(userId, role, false, 0)
+}
+
+// ResendVerification mails a new verification link. Links sent earlier stop
+// working.
+func (as *AuthService) ResendVerification(ctx context.Context, userID int) error {
+	user, err := as.repo.GetUserByID(userID)
 	if err != nil {
-		return "", err
+		return errors.New("user not found")
+	}
+	if user.EmailVerifiedAt != nil {
+		return errors.New("email already verified")
+	}
+	return as.sendVerification(ctx, user.ID, user.Email)
+}
+
+func (as *AuthService) VerifyEmail(token string) error {
+	userID, err := as.emailTokens.ConsumeEmailToken(model.EmailTokenVerify, hashToken(token))
+	if err != nil {
+		return err
+	}
+	return as.repo.MarkEmailVerified(userID)
+}
+
+// RequestPasswordReset mails a reset link if an account uses the address.
+// It succeeds either way, so the response does not reveal which addresses
+// are registered.
+func (as *AuthService) RequestPasswordReset(ctx context.Context, email string) {
+	user, err := as.repo.GetUserByEmail(email)
+	if err != nil {
+		log.Println("Error fetching user for password reset:", err)
+		return
+	}
+	if user == nil {
+		return
+	}
+
+	token, err := as.newEmailToken(user.ID, model.EmailTokenReset, resetPasswordTTL)
+	if err != nil {
+		log.Println("Error creating password reset token:", err)
+		return
+	}
+
+	err = as.mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Someone asked to reset the password of your account %s.\n\n"+
+			"Open the link below within an hour to choose a new password:\n%s/reset-password?token=%s\n\n"+
+			"If this was not you, ignore this email and your password stays unchanged.\n",
+			user.Username, as.appURL, token),
+	})
+	if err != nil {
+		log.Println("Error sending password reset email:", err)
+	}
+}
+
+// ResetPassword sets a new password with a token from a reset email and ends
+// every session, in case the account was taken over. Receiving the email
+// proves the address, so it also counts as verified.
+func (as *AuthService) ResetPassword(ctx context.Context, token, password string) error {
+	userID, err := as.emailTokens.GetEmailTokenUser(model.EmailTokenReset, hashToken(token))
+	if err != nil {
+		return err
+	}
+	user, err := as.repo.GetUserByID(userID)
+	if err != nil {
+		return errors.New("invalid or expired token")
 	}
+	// The password is checked before the token is used up, so a rejected
+	// password does not cost the user their reset link.
+	if err := utils.ValidatePassword(password, user.Username, user.Email); err != nil {
+		return err
+	}
+
+	if _, err := as.emailTokens.ConsumeEmailToken(model.EmailTokenReset, hashToken(token)); err != nil {
+		return err
+	}
+
+	hashedPassword, err := utils.EncodePassword(password)
+	if err != nil {
+		return err
+	}
+	if err := as.repo.UpdatePassword(userID, hashedPassword); err != nil {
+		return err
+	}
+	if err := as.repo.MarkEmailVerified(userID); err != nil {
+		log.Println("Error verifying email:", err)
+	}
+
+	return as.revokeSessions(ctx, userID, "")
+}
+
+// ChangePassword replaces the password after checking the current one. Every
+// other session is ended; the one making the change stays logged in.
+func (as *AuthService) ChangePassword(ctx context.Context, userID int, jti, currentPassword, newPassword string) (int, error) {
+	user, err := as.repo.GetUserByID(userID)
+	if err != nil {
+		return http.StatusNotFound, errors.New("User not found")
+	}
+
+	if err := utils.ComparePasswords(user.Password, currentPassword); err != nil {
+		utils.LogSecurityEvent("password_change_failed", "user_id", userID)
+		return http.StatusUnauthorized, errors.New("Current password is incorrect")
+	}
+	if currentPassword == newPassword {
+		return http.StatusBadRequest, errors.New("new password must differ from the current one")
+	}
+	if err := utils.ValidatePassword(newPassword, user.Username, user.Email); err != nil {
+		return http.StatusBadRequest, err
+	}
+
+	hashedPassword, err := utils.EncodePassword(newPassword)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	if err := as.repo.UpdatePassword(userID, hashedPassword); err != nil {
+		return http.StatusInternalServerError, err
+	}
+	utils.LogSecurityEvent("password_changed", "user_id", userID)
 
+	if err := as.revokeSessions(ctx, userID, jti); err != nil {
+		return http.StatusInternalServerError, err
+	}
+	return http.StatusOK, nil
+}
+
+func (as *AuthService) sendVerification(ctx context.Context, userID int, email string) error {
+	token, err := as.newEmailToken(userID, model.EmailTokenVerify, verifyEmailTTL)
+	if err != nil {
+		return err
+	}
+
+	return as.mailer.Send(ctx, mailer.Message{
+		To:      email,
+		Subject: "Verify your email address",
+		Body: fmt.Sprintf("Welcome to Tender Managment.\n\n"+
+			"Open the link below within two days to verify your email address:\n%s/verify-email?token=%s\n\n"+
+			"You can create tenders and submit bids once it is verified.\n",
+			as.appURL, token),
+	})
+}
+
+// newEmailToken creates a single-use token for the user. Only its hash is
+// stored.
+func (as *AuthService) newEmailToken(userID int, purpose string, ttl time.Duration) (string, error) {
+	token, err := utils.NewRandomToken(32)
+	if err != nil {
+		return "", err
+	}
+	if err := as.emailTokens.CreateEmailToken(userID, purpose, hashToken(token), time.Now().Add(ttl)); err != nil {
+		return "", err
+	}
 	return token, nil
 }
 
-func (as *AuthService) AuthenticateUser(username, password string) (string, int, error) {
+// AuthenticateUser checks the password. Accounts with two-factor
+// authentication get a challenge to complete with CompleteLogin instead of
+// tokens. Unknown usernames and wrong passwords get the same answer, and
+// repeated failures are throttled per account and per IP.
+func (as *AuthService) AuthenticateUser(ctx context.Context, username, password, ip string) (*model.TokenPair, *model.LoginChallenge, int, error) {
 	if username == "" || password == "" {
-		return "", http.StatusBadRequest, errors.New("Username and password are required")
+		return nil, nil, http.StatusBadRequest, errors.New("Username and password are required")
+	}
+
+	if wait := as.throttle.Check(ctx, username, ip); wait > 0 {
+		return nil, nil, http.StatusTooManyRequests, tooManyAttempts(wait)
 	}
 
 	user, err := as.repo.GetUserByUsername(username)
 	if err != nil {
-		return "", http.StatusNotFound, errors.New("User not found")
+		_ = utils.ComparePasswords(dummyPasswordHash, password)
+		as.throttle.Fail(ctx, username, ip, "unknown_user")
+		return nil, nil, http.StatusUnauthorized, errors.New("Invalid username or password")
 	}
 
 	err = utils.ComparePasswords(user.Password, password)
 	if err != nil {
-		return "", http.StatusUnauthorized, errors.New("Invalid username or password")
+		as.throttle.Fail(ctx, username, ip, "wrong_password")
+		return nil, nil, http.StatusUnauthorized, errors.New("Invalid username or password")
+	}
+
+	if user.SuspendedAt != nil {
+		return nil, nil, http.StatusForbidden, errors.New("Account suspended")
+	}
+
+	if user.TOTPEnabledAt != nil {
+		challenge, err := utils.GenerateChallengeToken(user.ID)
+		if err != nil {
+			return nil, nil, http.StatusInternalServerError, err
+		}
+		return nil, &model.LoginChallenge{
+			ChallengeToken: challenge,
+			ExpiresIn:      int(utils.ChallengeTTL.Seconds()),
+		}, http.StatusOK, nil
+	}
+
+	tokens, err := as.issueTokens(user.ID, user.Role, false, 0)
+	if err != nil {
+		return nil, nil, http.StatusInternalServerError, err
+	}
+	as.throttle.Succeed(ctx, username, ip)
+
+	return tokens, nil, http.StatusOK, nil
+}
+
+// CompleteLogin finishes a two-step login with the challenge token and a
+// code from the authenticator app or a recovery code. Wrong codes count as
+// failed logins.
+func (as *AuthService) CompleteLogin(ctx context.Context, challengeToken, code, ip string) (*model.TokenPair, int, error) {
+	userID, err := utils.ParseChallengeToken(challengeToken)
+	if err != nil {
+		return nil, http.StatusUnauthorized, errors.New("Invalid or expired challenge")
+	}
+
+	user, err := as.repo.GetUserByID(userID)
+	if err != nil {
+		return nil, http.StatusUnauthorized, errors.New("Invalid or expired challenge")
+	}
+	if user.SuspendedAt != nil {
+		return nil, http.StatusForbidden, errors.New("Account suspended")
+	}
+
+	if wait := as.throttle.Check(ctx, user.Username, ip); wait > 0 {
+		return nil, http.StatusTooManyRequests, tooManyAttempts(wait)
+	}
+
+	if err := as.twoFactor.Verify(userID, code); err != nil {
+		as.throttle.Fail(ctx, user.Username, ip, "wrong_two_factor_code")
+		return nil, http.StatusUnauthorized, errors.New("Invalid code")
+	}
+
+	tokens, err := as.issueTokens(user.ID, user.Role, true, 0)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	as.throttle.Succeed(ctx, user.Username, ip)
+
+	return tokens, http.StatusOK, nil
+}
+
+// Refresh trades a refresh token for a new token pair. Each refresh token
+// works once. Presenting one that was already used means it has leaked, so
+// every session of the user is ended.
+func (as *AuthService) Refresh(ctx context.Context, refreshToken string) (*model.TokenPair, int, error) {
+	stored, err := as.tokens.GetRefreshTokenByHash(hashToken(refreshToken))
+	if err != nil {
+		return nil, http.StatusUnauthorized, errors.New("Invalid refresh token")
+	}
+	if stored.RevokedAt != nil {
+		utils.LogSecurityEvent("refresh_token_reuse", "user_id", stored.UserID)
+		if err := as.revokeSessions(ctx, stored.UserID, ""); err != nil {
+			log.Println("Error revoking sessions:", err)
+		}
+		return nil, http.StatusUnauthorized, errors.New("Invalid refresh token")
+	}
+	if !time.Now().Before(stored.ExpiresAt) {
+		return nil, http.StatusUnauthorized, errors.New("Refresh token expired")
+	}
+
+	user, err := as.repo.GetUserByID(stored.UserID)
+	if err != nil {
+		return nil, http.StatusUnauthorized, errors.New("Invalid refresh token")
+	}
+	if user.SuspendedAt != nil {
+		return nil, http.StatusForbidden, errors.New("Account suspended")
+	}
+
+	tokens, err := as.issueTokens(user.ID, user.Role, stored.TwoFactor, stored.ID)
+	if err != nil {
+		if err.Error() == "refresh token already used" {
+			return nil, http.StatusUnauthorized, errors.New("Invalid refresh token")
+		}
+		return nil, http.StatusInternalServerError, err
+	}
+
+	if err := utils.RevokeToken(ctx, stored.AccessJTI, utils.AccessTokenTTL); err != nil {
+		log.Println("Error revoking access token:", err)
+	}
+
+	return tokens, http.StatusOK, nil
+}
+
+// Logout ends the session the access token belongs to.
+func (as *AuthService) Logout(ctx context.Context, userID int, jti string, expiresAt time.Time) error {
+	if err := utils.RevokeToken(ctx, jti, time.Until(expiresAt)); err != nil {
+		return err
+	}
+	return as.tokens.RevokeSession(userID, jti)
+}
+
+// LogoutAll ends every session of the user, on all devices.
+func (as *AuthService) LogoutAll(ctx context.Context, userID int, jti string, expiresAt time.Time) error {
+	if err := utils.RevokeToken(ctx, jti, time.Until(expiresAt)); err != nil {
+		return err
+	}
+	if err := as.revokeSessions(ctx, userID, ""); err != nil {
+		return err
+	}
+	utils.CloseUserSocket(userID, "Token revoked")
+	return nil
+}
+
+// revokeSessions revokes the user's refresh tokens and the access tokens
+// issued with them, except for the session of exceptJTI.
+func (as *AuthService) revokeSessions(ctx context.Context, userID int, exceptJTI string) error {
+	revoked, err := as.tokens.RevokeAll(userID, exceptJTI)
+	if err != nil {
+		return err
 	}
+	for _, token := range revoked {
+		if err := utils.RevokeToken(ctx, token.AccessJTI, utils.AccessTokenTTL); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-	token, err := utils.GenerateToken(user.ID, user.Role)
+// issueTokens creates an access token and a refresh token for the user. When
+// replacing is set, the refresh token with that ID is rotated out. twoFactor
+// carries over to every token refreshed from this pair.
+func (as *AuthService) issueTokens(userID int, role string, twoFactor bool, replacing int) (*model.TokenPair, error) {
+	accessToken, jti, err := utils.GenerateToken(userID, role, twoFactor)
 	if err != nil {
-		return "", http.StatusBadRequest, err
+		return nil, err
+	}
+	refreshToken, err := utils.NewRandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+
+	stored := &model.RefreshToken{
+		UserID:    userID,
+		TokenHash: hashToken(refreshToken),
+		AccessJTI: jti,
+		TwoFactor: twoFactor,
+		ExpiresAt: time.Now().Add(as.refreshTTL),
 	}
+	if replacing != 0 {
+		err = as.tokens.Rotate(replacing, stored)
+	} else {
+		err = as.tokens.CreateRefreshToken(stored)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.TokenPair{
+		Token:        accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int(utils.AccessTokenTTL.Seconds()),
+	}, nil
+}
+
+func tooManyAttempts(wait time.Duration) error {
+	seconds := int(wait.Round(time.Second).Seconds())
+	if seconds < 1 {
+		seconds = 1
+	}
+	return fmt.Errorf("Too many failed login attempts, try again in %d seconds", seconds)
+}
 
-	return token, http.StatusOK, nil
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
 }
@@ -8,19 +8,28 @@ import (
 	repository "tender-managment/internal/db/repo"
 	"tender-managment/internal/model"
 	"tender-managment/internal/utils"
+	"time"
 )
 
 type BidService struct {
 	bidRepo        repository.BidRepository
 	tenderRepo     repository.TenderRepository
 	contractorRepo repository.UserRepository
+	rateService    *ExchangeRateService
+	qualifications *QualificationService
+	evaluations    *EvaluationService
+	protests       *ProtestService
 }
 
-func NewBidService(bidRepo repository.BidRepository, tenderRepo repository.TenderRepository, contractorRepo repository.UserRepository) *BidService {
+func NewBidService(bidRepo repository.BidRepository, tenderRepo repository.TenderRepository, contractorRepo repository.UserRepository, rateService *ExchangeRateService, qualifications *QualificationService, evaluations *EvaluationService, protests *ProtestService) *BidService {
 	return &BidService{
 		bidRepo:        bidRepo,
 		tenderRepo:     tenderRepo,
 		contractorRepo: contractorRepo,
+		rateService:    rateService,
+		qualifications: qualifications,
+		evaluations:    evaluations,
+		protests:       protests,
 	}
 }
 
@@ -30,7 +39,7 @@ func (s *BidService) GetBidByID(contractorID int, bidID int) (*model.Bid, error)
 		return nil, fmt.Errorf("bid not found: %w", err)
 	}
 
-	if bid.ContractorID != contractorID {
+	if !managesBid(&s.bidRepo, bid, contractorID) {
 		return nil, errors.New("you do not have access to this bid")
 	}
 
@@ -46,6 +55,21 @@ func (s *BidService) GetBidsByContractor(contractorID int) ([]model.Bid, error)
 	return bids, nil
 }
 
+// GetBidHistory returns a contractor's bid history. Contractors see all of
+// their own bids; everyone else only sees bids that won a finalized award.
+func (s *BidService) GetBidHistory(viewerID, contractorID int) ([]model.Bid, error) {
+	if viewerID == contractorID {
+		return s.GetBidsByContractor(contractorID)
+	}
+
+	bids, err := s.bidRepo.GetAwardedBidsByContractorID(contractorID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch bids: %w", err)
+	}
+
+	return bids, nil
+}
+
 func (s *BidService) CreateBid(contractorID int, tenderID int, bid model.CreateBid) (*model.Bid, int, error) {
 	tender, err := s.tenderRepo.GetTenderByID(tenderID)
 	if err != nil {
@@ -56,15 +80,73 @@ func (s *BidService) CreateBid(contractorID int, tenderID int, bid model.CreateB
 		return nil, http.StatusBadRequest, fmt.Errorf("Tender is not open for bids")
 	}
 
-	if bid.Price <= 0 || bid.DeliveryTime <= 0 || bid.Comments == "" {
+	if tender.Stage == model.TenderStageEOI {
+		return nil, http.StatusBadRequest, fmt.Errorf("Tender is collecting expressions of interest")
+	}
+	if tender.EOIDeadline != nil {
+		shortlisted, err := s.bidRepo.IsShortlisted(tenderID, contractorID)
+		if err != nil {
+			return nil, http.StatusInternalServerError, err
+		}
+		if !shortlisted {
+			return nil, http.StatusForbidden, fmt.Errorf("Only shortlisted contractors can bid on this tender")
+		}
+	}
+
+	if status, err := s.checkCanRespond(contractorID, tender); err != nil {
+		return nil, status, err
+	}
+
+	if bid.Price.Currency == "" {
+		bid.Price.Currency = tender.Budget.Currency
+	}
+	if bid.Price.Currency != tender.Budget.Currency {
+		return nil, http.StatusBadRequest, fmt.Errorf("bid currency must be %s", tender.Budget.Currency)
+	}
+
+	boq, err := s.tenderRepo.GetBoQItems(tenderID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if len(boq) > 0 {
+		if err := priceLineItems(boq, &bid); err != nil {
+			return nil, http.StatusBadRequest, err
+		}
+	} else if len(bid.LineItems) > 0 {
+		return nil, http.StatusBadRequest, errors.New("tender has no bill of quantities")
+	}
+
+	lots, err := s.tenderRepo.GetLotsByTenderID(tenderID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if len(lots) > 0 {
+		bid.Price.Amount, err = priceLots(lots, bid.Lots)
+		if err != nil {
+			return nil, http.StatusBadRequest, err
+		}
+	} else if len(bid.Lots) > 0 {
+		return nil, http.StatusBadRequest, errors.New("tender is not split into lots")
+	}
+
+	if bid.Price.Amount <= 0 || bid.DeliveryTime <= 0 || bid.Comments == "" {
 		return nil, http.StatusBadRequest, errors.New("invalid bid data")
 	}
+	contractor, err := s.contractorRepo.GetUserByID(contractorID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
 	var newBid model.Bid
+	newBid.OrgID = contractor.OrgID
+	newBid.Lots = bid.Lots
+	newBid.LineItems = bid.LineItems
 	newBid.Price = bid.Price
 	newBid.Comments = bid.Comments
 	newBid.DeliveryTime = bid.DeliveryTime
 	newBid.ContractorID = contractorID
 	newBid.TenderID = tenderID
+	newBid.Type = model.BidTypeBid
 	newBid.Status = model.BidStatusPending
 	createdBid, err := s.bidRepo.CreateBid(newBid)
 	if err != nil {
@@ -81,13 +163,96 @@ func (s *BidService) CreateBid(contractorID int, tenderID int, bid model.CreateB
 
 	return createdBid, http.StatusCreated, nil
 }
-func (s *BidService) GetBidsByTenderID(tenderID, userId int, priceFilter float64, deliveryTimeFilter, sortBy string) ([]model.Bid, error) {
+
+// SubmitEOI records a non-priced expression of interest for the first stage
+// of a two-stage tender. It is stored as a bid of type eoi.
+func (s *BidService) SubmitEOI(contractorID int, tenderID int, eoi model.CreateEOI) (*model.Bid, int, error) {
 	tender, err := s.tenderRepo.GetTenderByID(tenderID)
-	if err != nil || tender.ClientID != userId {
-		return nil, fmt.Errorf("Tender not found or access denied")
+	if err != nil {
+		return nil, http.StatusNotFound, fmt.Errorf("Tender not found")
 	}
 
-	bids, err := s.bidRepo.GetBidsByTenderIDWithFilters(tenderID, priceFilter, deliveryTimeFilter, sortBy)
+	if tender.Status != model.TenderStatusOpen || tender.Stage != model.TenderStageEOI {
+		return nil, http.StatusBadRequest, fmt.Errorf("Tender is not collecting expressions of interest")
+	}
+
+	if status, err := s.checkCanRespond(contractorID, tender); err != nil {
+		return nil, status, err
+	}
+
+	submitted, err := s.bidRepo.HasContractorBidOfType(tenderID, contractorID, model.BidTypeEOI)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if submitted {
+		return nil, http.StatusBadRequest, fmt.Errorf("expression of interest already submitted")
+	}
+
+	if eoi.DeliveryTime <= 0 || eoi.Comments == "" {
+		return nil, http.StatusBadRequest, errors.New("invalid expression of interest")
+	}
+
+	contractor, err := s.contractorRepo.GetUserByID(contractorID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	created, err := s.bidRepo.CreateBid(model.Bid{
+		Price:        model.Money{Currency: tender.Budget.Currency},
+		DeliveryTime: eoi.DeliveryTime,
+		Comments:     eoi.Comments,
+		ContractorID: contractorID,
+		OrgID:        contractor.OrgID,
+		TenderID:     tenderID,
+		Type:         model.BidTypeEOI,
+		Status:       model.BidStatusPending,
+	})
+	if err != nil {
+		return nil, http.StatusBadRequest, fmt.Errorf("failed to submit expression of interest: %w", err)
+	}
+
+	message := "A contractor has expressed interest in your tender: " + tender.Title
+	utils.SendNotification(s.bidRepo, tender.ClientID, message, created.ID, "eoi_create")
+
+	return created, http.StatusCreated, nil
+}
+
+// checkCanRespond applies the invitation and prequalification rules shared by
+// bids and expressions of interest.
+func (s *BidService) checkCanRespond(contractorID int, tender *model.Tender) (int, error) {
+	if tender.Visibility == model.TenderVisibilityInviteOnly {
+		invited, err := s.tenderRepo.IsInvited(tender.ID, contractorID)
+		if err != nil {
+			return http.StatusInternalServerError, err
+		}
+		if !invited {
+			return http.StatusForbidden, fmt.Errorf("Tender is open to invited contractors only")
+		}
+	}
+
+	if err := s.qualifications.CheckEligibility(contractorID, tender); err != nil {
+		var ineligible *IneligibleError
+		if errors.As(err, &ineligible) {
+			return http.StatusForbidden, err
+		}
+		return http.StatusInternalServerError, err
+	}
+
+	return 0, nil
+}
+
+// GetBidsByTenderID lists the bids of a tender for its owner and evaluators.
+// Both need a conflict-of-interest declaration first. bidType selects priced
+// bids or expressions of interest, which are never listed together.
+func (s *BidService) GetBidsByTenderID(tenderID, userId int, bidType string, priceFilter model.Amount, deliveryTimeFilter, sortBy string) ([]model.Bid, error) {
+	if bidType != model.BidTypeBid && bidType != model.BidTypeEOI {
+		return nil, errors.New("invalid bid type")
+	}
+	if err := s.CheckBidAccess(tenderID, userId); err != nil {
+		return nil, err
+	}
+
+	bids, err := s.bidRepo.GetBidsByTenderIDWithFilters(tenderID, bidType, priceFilter, deliveryTimeFilter, sortBy)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch bids: %w", err)
 	}
@@ -95,13 +260,23 @@ func (s *BidService) GetBidsByTenderID(tenderID, userId int, priceFilter float64
 	return bids, nil
 }
 
+// CheckBidAccess reports whether the user may view the tender's bids. Cached
+// bid lists must only be served after this check.
+func (s *BidService) CheckBidAccess(tenderID, userID int) error {
+	err := s.evaluations.CheckBidAccess(userID, tenderID)
+	if err != nil && err.Error() == "tender not found" {
+		return fmt.Errorf("Tender not found or access denied")
+	}
+	return err
+}
+
 func (s *BidService) DeleteBid(contractorID int, bidID int) error {
 	bid, err := s.bidRepo.GetBidByID(bidID)
 	if err != nil {
 		return fmt.Errorf("Bid not found or access denied")
 	}
 
-	if bid.ContractorID != contractorID {
+	if !managesBid(&s.bidRepo, bid, contractorID) {
 		return errors.New("Bid not found or access denied")
 	}
 
@@ -113,9 +288,10 @@ func (s *BidService) DeleteBid(contractorID int, bidID int) error {
 	return nil
 }
 
+// UpdateBidStatus lets a contractor withdraw a pending bid by marking it
+// rejected. Awards are only ever made by AwardBid and AwardLot.
 func (s *BidService) UpdateBidStatus(contractorID int, bidID int, newStatus string) error {
-	validStatuses := []string{model.BidStatusAwarded, model.BidStatusRejected, model.BidStatusRejected}
-	if !contains(validStatuses, newStatus) {
+	if newStatus != model.BidStatusRejected {
 		return errors.New("invalid status")
 	}
 
@@ -124,9 +300,12 @@ func (s *BidService) UpdateBidStatus(contractorID int, bidID int, newStatus stri
 		return fmt.Errorf("bid not found: %w", err)
 	}
 
-	if bid.ContractorID != contractorID {
+	if !managesBid(&s.bidRepo, bid, contractorID) {
 		return errors.New("you do not have access to this bid")
 	}
+	if bid.Status != model.BidStatusPending {
+		return errors.New("only pending bids can be withdrawn")
+	}
 
 	bid.Status = newStatus
 	err = s.bidRepo.UpdateBidStatus(bid)
@@ -148,14 +327,32 @@ func contains(slice []string, item string) bool {
 
 func (s *BidService) AwardBid(clientID int, tenderID int, bidID int) error {
 	tender, err := s.tenderRepo.GetTenderByID(tenderID)
-	if err != nil || tender.ClientID != clientID {
+	if err != nil || !managesTender(&s.tenderRepo, tender, clientID) {
 		return fmt.Errorf("Tender not found or access denied")
 	}
+	if err := checkAwardable(tender); err != nil {
+		return err
+	}
 
 	bid, err := s.bidRepo.GetBidByID(bidID)
-	if err != nil {
+	if err != nil || bid.TenderID != tenderID {
 		return fmt.Errorf("Bid not found")
 	}
+	if bid.Type == model.BidTypeEOI {
+		return errors.New("expressions of interest cannot be awarded")
+	}
+	if err := s.evaluations.CheckSignedOff(tenderID); err != nil {
+		return err
+	}
+
+	lots, err := s.tenderRepo.GetLotsByTenderID(tenderID)
+	if err != nil {
+		return err
+	}
+	if len(lots) > 0 {
+		return errors.New("tender is split into lots, award each lot separately")
+	}
+
 	err = s.bidRepo.AwardBid(bidID)
 	if err != nil {
 		return fmt.Errorf("failed to award bid: %w", err)
@@ -166,7 +363,277 @@ func (s *BidService) AwardBid(clientID int, tenderID int, bidID int) error {
 		return fmt.Errorf("failed to update tender status: %w", err)
 	}
 
-	message := "Your bid has been awarded for tender: " + tender.Title
+	message := "Your bid has been provisionally awarded for tender: " + tender.Title
+	utils.SendNotification(s.bidRepo, bid.ContractorID, message, strconv.Itoa(bidID), "bid_award")
+	return s.protests.StartStandstill(tender)
+}
+
+func (s *BidService) AwardLot(clientID, tenderID, lotID, bidID int) error {
+	tender, err := s.tenderRepo.GetTenderByID(tenderID)
+	if err != nil || !managesTender(&s.tenderRepo, tender, clientID) {
+		return fmt.Errorf("Tender not found or access denied")
+	}
+	if err := checkAwardable(tender); err != nil {
+		return err
+	}
+
+	bid, err := s.bidRepo.GetBidByID(bidID)
+	if err != nil || bid.TenderID != tenderID {
+		return fmt.Errorf("Bid not found")
+	}
+
+	var lotPrice model.Amount
+	covered := false
+	for _, lot := range bid.Lots {
+		if lot.LotID == lotID {
+			covered = true
+			lotPrice = lot.Price
+			break
+		}
+	}
+	if !covered {
+		return errors.New("bid does not include this lot")
+	}
+	if err := s.evaluations.CheckSignedOff(tenderID); err != nil {
+		return err
+	}
+
+	if err := s.bidRepo.AwardLot(lotID, bidID); err != nil {
+		return err
+	}
+
+	message := fmt.Sprintf("Your bid has been provisionally awarded a lot for tender: %s (%s %s)", tender.Title, lotPrice, tender.Budget.Currency)
 	utils.SendNotification(s.bidRepo, bid.ContractorID, message, strconv.Itoa(bidID), "bid_award")
+
+	return s.completeLotDecisions(tenderID)
+}
+
+// checkAwardable rejects awards before the bidding deadline and outside the
+// closed state the committee evaluates in. Drafts and open tenders still take
+// bids, and an awarded tender can only change through a protest.
+func checkAwardable(tender *model.Tender) error {
+	if tender.Status == model.TenderStatusAwarded {
+		return errors.New("tender has already been awarded")
+	}
+	if tender.Status != model.TenderStatusClosed || time.Now().Before(tender.Deadline) {
+		return errors.New("tender can only be awarded after the deadline once bidding is closed")
+	}
 	return nil
 }
+
+// CancelLot closes a lot without awarding it to anyone.
+func (s *BidService) CancelLot(clientID, tenderID, lotID int) error {
+	tender, err := s.tenderRepo.GetTenderByID(tenderID)
+	if err != nil || !managesTender(&s.tenderRepo, tender, clientID) {
+		return fmt.Errorf("Tender not found or access denied")
+	}
+
+	if err := s.tenderRepo.CancelLot(tenderID, lotID); err != nil {
+		return err
+	}
+
+	return s.completeLotDecisions(tenderID)
+}
+
+// completeLotDecisions marks the tender awarded once every lot has been
+// awarded or cancelled, which starts the standstill period. A tender whose
+// lots were all cancelled is closed.
+func (s *BidService) completeLotDecisions(tenderID int) error {
+	decided, awarded, err := s.tenderRepo.LotsDecided(tenderID)
+	if err != nil || !decided {
+		return err
+	}
+
+	status := model.TenderStatusClosed
+	if awarded {
+		status = model.TenderStatusAwarded
+	}
+	if err := s.tenderRepo.UpdateTenderStatus(tenderID, status); err != nil {
+		return fmt.Errorf("failed to update tender status: %w", err)
+	}
+	if !awarded {
+		return nil
+	}
+
+	tender, err := s.tenderRepo.GetTenderByID(tenderID)
+	if err != nil {
+		return err
+	}
+	return s.protests.StartStandstill(tender)
+}
+
+func priceLots(lots []model.Lot, bidLots []model.BidLot) (model.Amount, error) {
+	if len(bidLots) == 0 {
+		return 0, errors.New("bid must include at least one lot")
+	}
+
+	open := make(map[int]bool, len(lots))
+	for _, lot := range lots {
+		open[lot.ID] = lot.Status == model.LotStatusOpen
+	}
+
+	var total model.Amount
+	seen := make(map[int]bool, len(bidLots))
+	for _, bidLot := range bidLots {
+		isOpen, ok := open[bidLot.LotID]
+		if !ok {
+			return 0, fmt.Errorf("lot %d does not belong to this tender", bidLot.LotID)
+		}
+		if !isOpen {
+			return 0, fmt.Errorf("lot %d is no longer open for bids", bidLot.LotID)
+		}
+		if seen[bidLot.LotID] {
+			return 0, fmt.Errorf("lot %d is listed more than once", bidLot.LotID)
+		}
+		if bidLot.Price <= 0 {
+			return 0, fmt.Errorf("invalid price for lot %d", bidLot.LotID)
+		}
+		seen[bidLot.LotID] = true
+		total += bidLot.Price
+	}
+
+	return total, nil
+}
+
+// priceLineItems checks that every required line of the bill of quantities is
+// priced and works out the line, lot and bid totals. Totals sent by the
+// contractor must match the calculated ones.
+func priceLineItems(boq []model.BoQItem, bid *model.CreateBid) error {
+	bidLots := make(map[int]bool, len(bid.Lots))
+	for _, lot := range bid.Lots {
+		bidLots[lot.LotID] = true
+	}
+
+	required := make(map[int]model.BoQItem, len(boq))
+	for _, item := range boq {
+		if item.LotID == nil || bidLots[*item.LotID] {
+			required[item.ID] = item
+		}
+	}
+
+	var total model.Amount
+	lotTotals := make(map[int]model.Amount, len(bid.Lots))
+	priced := make(map[int]bool, len(bid.LineItems))
+	for i, line := range bid.LineItems {
+		item, ok := required[line.BoQItemID]
+		if !ok {
+			return fmt.Errorf("item %d is not part of this bid", line.BoQItemID)
+		}
+		if priced[line.BoQItemID] {
+			return fmt.Errorf("item %d is priced more than once", item.ItemNumber)
+		}
+		if line.UnitPrice < 0 {
+			return fmt.Errorf("invalid unit price for item %d", item.ItemNumber)
+		}
+		priced[line.BoQItemID] = true
+
+		lineTotal := line.UnitPrice.MulQuantity(item.Quantity)
+		bid.LineItems[i].Total = lineTotal
+		total += lineTotal
+		if item.LotID != nil {
+			lotTotals[*item.LotID] += lineTotal
+		}
+	}
+
+	for _, item := range required {
+		if !priced[item.ID] {
+			return fmt.Errorf("item %d is not priced", item.ItemNumber)
+		}
+	}
+
+	for i, lot := range bid.Lots {
+		lotTotal := lotTotals[lot.LotID]
+		if lot.Price != 0 && lot.Price != lotTotal {
+			return fmt.Errorf("price for lot %d does not match its line items (%s)", lot.LotID, lotTotal)
+		}
+		bid.Lots[i].Price = lotTotal
+	}
+
+	if bid.Price.Amount != 0 && bid.Price.Amount != total {
+		return fmt.Errorf("bid price does not match its line items (%s)", total)
+	}
+	bid.Price.Amount = total
+
+	return nil
+}
+
+// CompareBids lays out the line items of every bid on the tender side by side.
+// When currency differs from the tender's, every amount is converted through
+// the exchange-rate table.
+func (s *BidService) CompareBids(clientID, tenderID int, currency string) (*model.BoQComparison, error) {
+	tender, err := s.tenderRepo.GetTenderByID(tenderID)
+	if err != nil || !managesTender(&s.tenderRepo, tender, clientID) {
+		return nil, fmt.Errorf("Tender not found or access denied")
+	}
+	if err := s.evaluations.CheckBidAccess(clientID, tenderID); err != nil {
+		return nil, err
+	}
+
+	if currency == "" {
+		currency = tender.Budget.Currency
+	}
+	rate, err := s.rateService.Rate(tender.Budget.Currency, currency)
+	if err != nil {
+		return nil, err
+	}
+	convert := func(amount model.Amount) model.Amount {
+		return amount.Convert(rate)
+	}
+
+	items, err := s.tenderRepo.GetBoQItems(tenderID)
+	if err != nil {
+		return nil, err
+	}
+
+	offers, err := s.bidRepo.GetBoQOffersByTenderID(tenderID)
+	if err != nil {
+		return nil, err
+	}
+
+	bids, err := s.bidRepo.GetBidsByTenderIDWithFilters(tenderID, model.BidTypeBid, 0, "", "price")
+	if err != nil {
+		return nil, err
+	}
+
+	comparison := &model.BoQComparison{
+		TenderID: tenderID,
+		Currency: currency,
+		Items:    make([]model.BoQItemComparison, 0, len(items)),
+		Bids:     make([]model.BidTotal, 0, len(bids)),
+	}
+	if currency != tender.Budget.Currency {
+		comparison.ExchangeRate = rate.FloatString(8)
+	}
+
+	for _, item := range items {
+		row := model.BoQItemComparison{Item: item, Offers: offers[item.ID]}
+		if row.Offers == nil {
+			row.Offers = []model.BoQOffer{}
+		}
+		var sum model.Amount
+		for i := range row.Offers {
+			offer := &row.Offers[i]
+			offer.UnitPrice = convert(offer.UnitPrice)
+			offer.Total = convert(offer.Total)
+			if i == 0 || offer.UnitPrice < row.MinUnitPrice {
+				row.MinUnitPrice = offer.UnitPrice
+			}
+			if i == 0 || offer.UnitPrice > row.MaxUnitPrice {
+				row.MaxUnitPrice = offer.UnitPrice
+			}
+			sum += offer.UnitPrice
+		}
+		row.AverageUnitPrice = sum.DivInt(len(row.Offers))
+		comparison.Items = append(comparison.Items, row)
+	}
+
+	for _, bid := range bids {
+		comparison.Bids = append(comparison.Bids, model.BidTotal{
+			BidID:        bid.ID,
+			ContractorID: bid.ContractorID,
+			Total:        convert(bid.Price.Amount),
+		})
+	}
+
+	return comparison, nil
+}
@@ -0,0 +1,185 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	repository "tender-managment/internal/db/repo"
+	"tender-managment/internal/model"
+	"tender-managment/internal/utils"
+	"time"
+)
+
+type ContractService struct {
+	repo       *repository.ContractRepository
+	tenderRepo *repository.TenderRepository
+	bidRepo    *repository.BidRepository
+	protests   *ProtestService
+	milestones *MilestoneService
+}
+
+func NewContractService(repo *repository.ContractRepository, tenderRepo *repository.TenderRepository, bidRepo *repository.BidRepository, protests *ProtestService, milestones *MilestoneService) *ContractService {
+	return &ContractService{
+		repo:       repo,
+		tenderRepo: tenderRepo,
+		bidRepo:    bidRepo,
+		protests:   protests,
+		milestones: milestones,
+	}
+}
+
+// CreateContract drafts the contract for a winning bid once the tender's
+// award is final. Bids that won lots are contracted at the price of the lots
+// they won. The contract starts with a single milestone for the whole price.
+func (s *ContractService) CreateContract(clientID, tenderID int, payload model.CreateContract, startDate time.Time, endDate *time.Time) (*model.Contract, error) {
+	tender, err := s.tenderRepo.GetTenderByID(tenderID)
+	if err != nil || !managesTender(s.tenderRepo, tender, clientID) {
+		return nil, errors.New("tender not found")
+	}
+	if err := s.protests.CheckAwardFinal(tenderID); err != nil {
+		return nil, err
+	}
+
+	bid, err := s.bidRepo.GetBidByID(payload.BidID)
+	if err != nil || bid.TenderID != tenderID {
+		return nil, errors.New("bid not found")
+	}
+	if bid.Status != model.BidStatusAwarded {
+		return nil, errors.New("only awarded bids can be contracted")
+	}
+
+	price := bid.Price.Amount
+	if len(bid.Lots) > 0 {
+		price = 0
+		for _, lot := range bid.Lots {
+			if lot.Status == model.LotStatusAwarded {
+				price += lot.Price
+			}
+		}
+	}
+
+	end := startDate.AddDate(0, 0, bid.DeliveryTime)
+	if endDate != nil {
+		end = *endDate
+	}
+	if end.Before(startDate) {
+		return nil, errors.New("end date must not be before the start date")
+	}
+
+	bidID, _ := strconv.Atoi(bid.ID)
+	contract, err := s.repo.CreateContract(&model.Contract{
+		TenderID:     tenderID,
+		BidID:        bidID,
+		ClientID:     clientID,
+		ContractorID: bid.ContractorID,
+		Price:        model.Money{Amount: price, Currency: tender.Budget.Currency},
+		DeliveryTime: bid.DeliveryTime,
+		Terms:        strings.TrimSpace(payload.Terms),
+		StartDate:    startDate,
+		EndDate:      end,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.milestones.SeedSchedule(contract); err != nil {
+		return nil, fmt.Errorf("failed to create milestone schedule: %w", err)
+	}
+
+	message := "A contract for tender " + tender.Title + " is awaiting your acceptance"
+	utils.SendNotification(*s.bidRepo, contract.ContractorID, message, strconv.Itoa(contract.ID), "contract_created")
+
+	return contract, nil
+}
+
+// ListContracts lists the user's contracts for their dashboard.
+func (s *ContractService) ListContracts(userID int, status string) ([]model.Contract, error) {
+	switch status {
+	case "", model.ContractStatusPending, model.ContractStatusActive, model.ContractStatusCompleted, model.ContractStatusTerminated:
+	default:
+		return nil, fmt.Errorf("unknown contract status %q", status)
+	}
+	return s.repo.ListContractsByUserID(userID, status)
+}
+
+func (s *ContractService) GetContract(userID, contractID int) (*model.Contract, error) {
+	contract, err := s.repo.GetContractByID(contractID)
+	if err != nil || (contract.ClientID != userID && contract.ContractorID != userID) {
+		return nil, errors.New("contract not found")
+	}
+	return contract, nil
+}
+
+// AcceptContract records the user's acceptance. The contract becomes active
+// once both parties have accepted it.
+func (s *ContractService) AcceptContract(userID, contractID int) (*model.Contract, error) {
+	contract, err := s.GetContract(userID, contractID)
+	if err != nil {
+		return nil, err
+	}
+
+	accepted, err := s.repo.Accept(contractID, contract.ClientID == userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if accepted.Status == model.ContractStatusActive && contract.Status != model.ContractStatusActive {
+		message := fmt.Sprintf("Contract #%d has been accepted by both parties and is now active", contractID)
+		utils.SendNotification(*s.bidRepo, accepted.ClientID, message, strconv.Itoa(contractID), "contract_active")
+		utils.SendNotification(*s.bidRepo, accepted.ContractorID, message, strconv.Itoa(contractID), "contract_active")
+	} else {
+		message := fmt.Sprintf("Contract #%d has been accepted by the other party", contractID)
+		utils.SendNotification(*s.bidRepo, counterparty(accepted, userID), message, strconv.Itoa(contractID), "contract_accepted")
+	}
+
+	return accepted, nil
+}
+
+// CompleteContract lets the client confirm that an active contract has been
+// fulfilled. Every milestone has to be accepted first.
+func (s *ContractService) CompleteContract(clientID, contractID int) error {
+	contract, err := s.GetContract(clientID, contractID)
+	if err != nil || contract.ClientID != clientID {
+		return errors.New("contract not found")
+	}
+	if err := s.milestones.CheckAllAccepted(contractID); err != nil {
+		return err
+	}
+
+	if err := s.repo.Complete(contractID); err != nil {
+		return err
+	}
+
+	message := fmt.Sprintf("Contract #%d has been marked as completed", contractID)
+	utils.SendNotification(*s.bidRepo, contract.ContractorID, message, strconv.Itoa(contractID), "contract_completed")
+	return nil
+}
+
+// TerminateContract lets either party end a contract that is pending or
+// active. A reason is required and passed on to the other party.
+func (s *ContractService) TerminateContract(userID, contractID int, reason string) error {
+	contract, err := s.GetContract(userID, contractID)
+	if err != nil {
+		return err
+	}
+
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return errors.New("a reason is required to terminate a contract")
+	}
+
+	if err := s.repo.Terminate(contractID, reason); err != nil {
+		return err
+	}
+
+	message := fmt.Sprintf("Contract #%d has been terminated: %s", contractID, reason)
+	utils.SendNotification(*s.bidRepo, counterparty(contract, userID), message, strconv.Itoa(contractID), "contract_terminated")
+	return nil
+}
+
+func counterparty(contract *model.Contract, userID int) int {
+	if contract.ClientID == userID {
+		return contract.ContractorID
+	}
+	return contract.ClientID
+}
@@ -0,0 +1,484 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	repository "tender-managment/internal/db/repo"
+	"tender-managment/internal/model"
+	"tender-managment/internal/utils"
+)
+
+var (
+	ErrDeclarationRequired = errors.New("a conflict-of-interest declaration covering all bidders is required")
+	ErrDeclaredConflict    = errors.New("a declared conflict of interest prevents access to bids")
+)
+
+// largeVarianceSpread is the gap in points between the highest and lowest
+// score for one bid above which the bid is flagged for discussion.
+const largeVarianceSpread = 20
+
+type EvaluationService struct {
+	repo            *repository.EvaluationRepository
+	declarationRepo *repository.DeclarationRepository
+	tenderRepo      *repository.TenderRepository
+	bidRepo         *repository.BidRepository
+	userRepo        *repository.UserRepository
+}
+
+func NewEvaluationService(repo *repository.EvaluationRepository, declarationRepo *repository.DeclarationRepository, tenderRepo *repository.TenderRepository, bidRepo *repository.BidRepository, userRepo *repository.UserRepository) *EvaluationService {
+	return &EvaluationService{
+		repo:            repo,
+		declarationRepo: declarationRepo,
+		tenderRepo:      tenderRepo,
+		bidRepo:         bidRepo,
+		userRepo:        userRepo,
+	}
+}
+
+// AppointEvaluators adds client users to the tender's evaluation committee.
+// The committee is fixed once any member has signed off.
+func (s *EvaluationService) AppointEvaluators(clientID, tenderID int, evaluatorIDs []int) ([]model.Evaluator, error) {
+	if err := s.checkCommitteeEditable(clientID, tenderID); err != nil {
+		return nil, err
+	}
+	if len(evaluatorIDs) == 0 {
+		return nil, errors.New("no evaluators to appoint")
+	}
+
+	for _, id := range evaluatorIDs {
+		user, err := s.userRepo.GetUserByID(id)
+		if err != nil || user.Role != "client" {
+			return nil, fmt.Errorf("user %d cannot be an evaluator", id)
+		}
+	}
+
+	if err := s.repo.AddEvaluators(tenderID, evaluatorIDs); err != nil {
+		return nil, err
+	}
+
+	tender, err := s.tenderRepo.GetTenderByID(tenderID)
+	if err == nil {
+		message := "You have been appointed to evaluate tender: " + tender.Title
+		for _, id := range evaluatorIDs {
+			utils.SendNotification(*s.bidRepo, id, message, strconv.Itoa(tenderID), "evaluator_appointed")
+		}
+	}
+
+	return s.repo.GetEvaluators(tenderID)
+}
+
+func (s *EvaluationService) RemoveEvaluator(clientID, tenderID, evaluatorID int) error {
+	if err := s.checkCommitteeEditable(clientID, tenderID); err != nil {
+		return err
+	}
+
+	return s.repo.RemoveEvaluator(tenderID, evaluatorID)
+}
+
+func (s *EvaluationService) ListEvaluators(clientID, tenderID int) ([]model.Evaluator, error) {
+	tender, err := s.tenderRepo.GetTenderByID(tenderID)
+	if err != nil || !managesTender(s.tenderRepo, tender, clientID) {
+		return nil, errors.New("tender not found")
+	}
+
+	return s.repo.GetEvaluators(tenderID)
+}
+
+// GetSheet returns the bids an evaluator has to score together with their own
+// scores. Other evaluators' scores are never included.
+func (s *EvaluationService) GetSheet(evaluatorID, tenderID int) (*model.EvaluationSheet, error) {
+	evaluator, err := s.repo.GetEvaluator(tenderID, evaluatorID)
+	if err != nil {
+		return nil, errors.New("tender not found")
+	}
+	if err := s.checkDeclaration(evaluatorID, tenderID); err != nil {
+		return nil, err
+	}
+
+	bids, err := s.scorableBids(tenderID)
+	if err != nil {
+		return nil, err
+	}
+
+	scores, err := s.repo.GetScoresByEvaluator(tenderID, evaluatorID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.EvaluationSheet{
+		TenderID:    tenderID,
+		Bids:        bids,
+		Scores:      scores,
+		SubmittedAt: evaluator.SubmittedAt,
+	}, nil
+}
+
+// SaveScores stores draft scores. They can be changed until submitted.
+func (s *EvaluationService) SaveScores(evaluatorID, tenderID int, scores []model.BidScore) error {
+	if _, err := s.repo.GetEvaluator(tenderID, evaluatorID); err != nil {
+		return errors.New("tender not found")
+	}
+	if err := s.checkDeclaration(evaluatorID, tenderID); err != nil {
+		return err
+	}
+
+	bids, err := s.scorableBids(tenderID)
+	if err != nil {
+		return err
+	}
+	valid := make(map[int]bool, len(bids))
+	for _, bid := range bids {
+		id, _ := strconv.Atoi(bid.ID)
+		valid[id] = true
+	}
+
+	for _, score := range scores {
+		if !valid[score.BidID] {
+			return fmt.Errorf("bid %d is not part of this evaluation", score.BidID)
+		}
+		if score.Score < 0 || score.Score > 100 {
+			return fmt.Errorf("score for bid %d must be between 0 and 100", score.BidID)
+		}
+	}
+
+	return s.repo.SaveScores(tenderID, evaluatorID, scores)
+}
+
+// SubmitScores locks the evaluator's scores. Every bid has to be scored and
+// the tender must be closed, so no bid can arrive after submission.
+func (s *EvaluationService) SubmitScores(evaluatorID, tenderID int) error {
+	if _, err := s.repo.GetEvaluator(tenderID, evaluatorID); err != nil {
+		return errors.New("tender not found")
+	}
+
+	tender, err := s.tenderRepo.GetTenderByID(tenderID)
+	if err != nil {
+		return errors.New("tender not found")
+	}
+	if tender.Status != model.TenderStatusClosed {
+		return errors.New("scores can only be submitted once the tender is closed")
+	}
+
+	bids, err := s.scorableBids(tenderID)
+	if err != nil {
+		return err
+	}
+	scores, err := s.repo.GetScoresByEvaluator(tenderID, evaluatorID)
+	if err != nil {
+		return err
+	}
+	scored := make(map[int]bool, len(scores))
+	for _, score := range scores {
+		scored[score.BidID] = true
+	}
+	for _, bid := range bids {
+		id, _ := strconv.Atoi(bid.ID)
+		if !scored[id] {
+			return fmt.Errorf("bid %d has not been scored", id)
+		}
+	}
+
+	return s.repo.MarkSubmitted(tenderID, evaluatorID)
+}
+
+// GetResults returns every evaluator's scores with averages and variance
+// flags. Until all evaluators have submitted, nobody sees the scores.
+func (s *EvaluationService) GetResults(userID, tenderID int) (*model.EvaluationResults, error) {
+	tender, err := s.tenderRepo.GetTenderByID(tenderID)
+	if err != nil {
+		return nil, errors.New("tender not found")
+	}
+	if !managesTender(s.tenderRepo, tender, userID) {
+		if _, err := s.repo.GetEvaluator(tenderID, userID); err != nil {
+			return nil, errors.New("tender not found")
+		}
+	}
+
+	evaluators, err := s.repo.GetEvaluators(tenderID)
+	if err != nil {
+		return nil, err
+	}
+	if len(evaluators) == 0 {
+		return nil, errors.New("tender has no evaluation committee")
+	}
+	signedOff := true
+	for _, evaluator := range evaluators {
+		if evaluator.SubmittedAt == nil {
+			return nil, errors.New("not every evaluator has submitted their scores")
+		}
+		if evaluator.SignedOffAt == nil {
+			signedOff = false
+		}
+	}
+
+	bids, err := s.scorableBids(tenderID)
+	if err != nil {
+		return nil, err
+	}
+	scores, err := s.repo.GetScores(tenderID)
+	if err != nil {
+		return nil, err
+	}
+	byBid := make(map[int][]model.BidScore)
+	for _, score := range scores {
+		byBid[score.BidID] = append(byBid[score.BidID], score)
+	}
+
+	results := &model.EvaluationResults{TenderID: tenderID, SignedOff: signedOff}
+	for _, bid := range bids {
+		id, _ := strconv.Atoi(bid.ID)
+		results.Bids = append(results.Bids, summarizeScores(id, bid.ContractorID, byBid[id]))
+	}
+
+	return results, nil
+}
+
+// SignOff records an evaluator's approval of the committee results. Awarding
+// is blocked until every evaluator has signed off. Only appointed evaluators
+// may sign off, whatever their role in the owner's organization.
+func (s *EvaluationService) SignOff(evaluatorID, tenderID int) error {
+	if _, err := s.repo.GetEvaluator(tenderID, evaluatorID); err != nil {
+		return errors.New("tender not found")
+	}
+
+	if _, err := s.GetResults(evaluatorID, tenderID); err != nil {
+		return err
+	}
+
+	if err := s.repo.MarkSignedOff(tenderID, evaluatorID); err != nil {
+		return err
+	}
+
+	evaluators, err := s.repo.GetEvaluators(tenderID)
+	if err != nil {
+		return err
+	}
+	for _, evaluator := range evaluators {
+		if evaluator.SignedOffAt == nil {
+			return nil
+		}
+	}
+
+	tender, err := s.tenderRepo.GetTenderByID(tenderID)
+	if err == nil {
+		message := "The evaluation committee has signed off tender: " + tender.Title
+		utils.SendNotification(*s.bidRepo, tender.ClientID, message, strconv.Itoa(tenderID), "evaluation_signed_off")
+	}
+
+	return nil
+}
+
+// CheckSignedOff returns an error when the tender has a committee that has
+// not fully signed off. Tenders without a committee are not affected.
+func (s *EvaluationService) CheckSignedOff(tenderID int) error {
+	evaluators, err := s.repo.GetEvaluators(tenderID)
+	if err != nil {
+		return err
+	}
+
+	for _, evaluator := range evaluators {
+		if evaluator.SignedOffAt == nil {
+			return errors.New("evaluation committee has not signed off")
+		}
+	}
+
+	return nil
+}
+
+// DeclarationForm lists the bidding companies the owner or an evaluator has
+// to declare against before seeing bids.
+func (s *EvaluationService) DeclarationForm(userID, tenderID int) ([]model.DeclaredBidder, error) {
+	if _, err := s.checkBidViewer(userID, tenderID); err != nil {
+		return nil, err
+	}
+
+	return s.declarationRepo.GetBidders(tenderID)
+}
+
+// SignDeclaration stores a conflict-of-interest declaration covering every
+// current bidder. An evaluator who declares a conflict is removed from the
+// committee, together with their scores, and the tender owner is notified.
+func (s *EvaluationService) SignDeclaration(userID, tenderID int, payload model.SignDeclaration) (*model.COIDeclaration, error) {
+	tender, err := s.checkBidViewer(userID, tenderID)
+	if err != nil {
+		return nil, err
+	}
+
+	bidders, err := s.declarationRepo.GetBidders(tenderID)
+	if err != nil {
+		return nil, err
+	}
+
+	conflicts := make(map[int]bool, len(payload.ConflictContractorIDs))
+	for _, id := range payload.ConflictContractorIDs {
+		conflicts[id] = true
+	}
+	for i := range bidders {
+		if conflicts[bidders[i].ContractorID] {
+			bidders[i].Conflict = true
+			delete(conflicts, bidders[i].ContractorID)
+		}
+	}
+	for id := range conflicts {
+		return nil, fmt.Errorf("contractor %d has not bid on this tender", id)
+	}
+
+	declaration, err := s.declarationRepo.CreateDeclaration(&model.COIDeclaration{
+		TenderID:    tenderID,
+		UserID:      userID,
+		Statement:   payload.Statement,
+		HasConflict: len(payload.ConflictContractorIDs) > 0,
+		Bidders:     bidders,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if !declaration.HasConflict {
+		return declaration, nil
+	}
+
+	// Any appointed evaluator who declares a conflict leaves the committee,
+	// including members of the owner's organization.
+	if _, err := s.repo.GetEvaluator(tenderID, userID); err != nil {
+		if err.Error() == "evaluator not found" {
+			return declaration, nil
+		}
+		return nil, err
+	}
+	if err := s.repo.RemoveEvaluator(tenderID, userID); err != nil {
+		return nil, err
+	}
+	message := "An evaluator declared a conflict of interest and was removed from tender: " + tender.Title
+	utils.SendNotification(*s.bidRepo, tender.ClientID, message, strconv.Itoa(tenderID), "evaluator_conflict")
+
+	return declaration, nil
+}
+
+// ListDeclarations returns every declaration signed for the tender, for the
+// owner's compliance records.
+func (s *EvaluationService) ListDeclarations(clientID, tenderID int) ([]model.COIDeclaration, error) {
+	tender, err := s.tenderRepo.GetTenderByID(tenderID)
+	if err != nil || !managesTender(s.tenderRepo, tender, clientID) {
+		return nil, errors.New("tender not found")
+	}
+
+	return s.declarationRepo.GetDeclarationsByTenderID(tenderID)
+}
+
+// CheckBidAccess allows the tender owner and the evaluators to view bids once
+// they have declared no conflict with any of the current bidders.
+func (s *EvaluationService) CheckBidAccess(userID, tenderID int) error {
+	if _, err := s.checkBidViewer(userID, tenderID); err != nil {
+		return err
+	}
+
+	return s.checkDeclaration(userID, tenderID)
+}
+
+func (s *EvaluationService) checkBidViewer(userID, tenderID int) (*model.Tender, error) {
+	tender, err := s.tenderRepo.GetTenderByID(tenderID)
+	if err != nil {
+		return nil, errors.New("tender not found")
+	}
+	if !managesTender(s.tenderRepo, tender, userID) {
+		if _, err := s.repo.GetEvaluator(tenderID, userID); err != nil {
+			return nil, errors.New("tender not found")
+		}
+	}
+
+	return tender, nil
+}
+
+func isDeclarationError(err error) bool {
+	return errors.Is(err, ErrDeclarationRequired) || errors.Is(err, ErrDeclaredConflict)
+}
+
+// checkDeclaration requires a declaration without conflicts that lists every
+// contractor currently bidding. New bidders call for a new declaration.
+func (s *EvaluationService) checkDeclaration(userID, tenderID int) error {
+	declaration, err := s.declarationRepo.GetLatestDeclaration(tenderID, userID)
+	if err != nil {
+		if err.Error() == "declaration not found" {
+			return ErrDeclarationRequired
+		}
+		return err
+	}
+	if declaration.HasConflict {
+		return ErrDeclaredConflict
+	}
+
+	declared := make(map[int]bool, len(declaration.Bidders))
+	for _, bidder := range declaration.Bidders {
+		declared[bidder.ContractorID] = true
+	}
+	bidders, err := s.declarationRepo.GetBidders(tenderID)
+	if err != nil {
+		return err
+	}
+	for _, bidder := range bidders {
+		if !declared[bidder.ContractorID] {
+			return ErrDeclarationRequired
+		}
+	}
+
+	return nil
+}
+
+func (s *EvaluationService) checkCommitteeEditable(clientID, tenderID int) error {
+	tender, err := s.tenderRepo.GetTenderByID(tenderID)
+	if err != nil || !managesTender(s.tenderRepo, tender, clientID) {
+		return errors.New("tender not found")
+	}
+	if tender.Status == model.TenderStatusAwarded {
+		return errors.New("evaluation committee can no longer be changed")
+	}
+
+	evaluators, err := s.repo.GetEvaluators(tenderID)
+	if err != nil {
+		return err
+	}
+	for _, evaluator := range evaluators {
+		if evaluator.SignedOffAt != nil {
+			return errors.New("evaluation committee can no longer be changed")
+		}
+	}
+
+	return nil
+}
+
+// scorableBids returns the priced bids of the tender; expressions of interest
+// are not scored.
+func (s *EvaluationService) scorableBids(tenderID int) ([]model.Bid, error) {
+	return s.bidRepo.GetBidsByTenderIDWithFilters(tenderID, model.BidTypeBid, 0, "", "price")
+}
+
+func summarizeScores(bidID, contractorID int, scores []model.BidScore) model.BidScoreSummary {
+	summary := model.BidScoreSummary{BidID: bidID, ContractorID: contractorID, Scores: scores}
+	if len(scores) == 0 {
+		return summary
+	}
+
+	summary.Min, summary.Max = scores[0].Score, scores[0].Score
+	var sum float64
+	for _, score := range scores {
+		sum += score.Score
+		summary.Min = math.Min(summary.Min, score.Score)
+		summary.Max = math.Max(summary.Max, score.Score)
+	}
+	summary.Average = sum / float64(len(scores))
+
+	var squares float64
+	for _, score := range scores {
+		squares += (score.Score - summary.Average) * (score.Score - summary.Average)
+	}
+	summary.StdDev = math.Sqrt(squares / float64(len(scores)))
+	summary.LargeVariance = summary.Max-summary.Min > largeVarianceSpread
+
+	summary.Average = math.Round(summary.Average*100) / 100
+	summary.StdDev = math.Round(summary.StdDev*100) / 100
+
+	return summary
+}
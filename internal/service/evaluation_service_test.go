@@ -0,0 +1,59 @@
+package service
+
+import (
+	"tender-managment/internal/model"
+	"testing"
+)
+
+func scoresOf(values ...float64) []model.BidScore {
+	scores := make([]model.BidScore, len(values))
+	for i, v := range values {
+		scores[i] = model.BidScore{BidID: 1, EvaluatorID: i + 1, Score: v}
+	}
+	return scores
+}
+
+func TestSummarizeScores(t *testing.T) {
+	tests := []struct {
+		name          string
+		scores        []float64
+		average       float64
+		min, max      float64
+		stdDev        float64
+		largeVariance bool
+	}{
+		{"single evaluator", []float64{72}, 72, 72, 72, 0, false},
+		{"agreement", []float64{80, 80, 80}, 80, 80, 80, 0, false},
+		{"rounded average", []float64{70, 75, 76}, 73.67, 70, 76, 2.62, false},
+		{"spread at the threshold", []float64{60, 80}, 70, 60, 80, 10, false},
+		{"spread above the threshold", []float64{60, 80.5}, 70.25, 60, 80.5, 10.25, true},
+		{"outlier", []float64{90, 88, 40}, 72.67, 40, 90, 23.11, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			summary := summarizeScores(7, 3, scoresOf(tt.scores...))
+			if summary.BidID != 7 || summary.ContractorID != 3 || len(summary.Scores) != len(tt.scores) {
+				t.Fatalf("summary = %+v, want bid 7, contractor 3 and %d scores", summary, len(tt.scores))
+			}
+			if summary.Average != tt.average {
+				t.Errorf("Average = %v, want %v", summary.Average, tt.average)
+			}
+			if summary.Min != tt.min || summary.Max != tt.max {
+				t.Errorf("Min, Max = %v, %v, want %v, %v", summary.Min, summary.Max, tt.min, tt.max)
+			}
+			if summary.StdDev != tt.stdDev {
+				t.Errorf("StdDev = %v, want %v", summary.StdDev, tt.stdDev)
+			}
+			if summary.LargeVariance != tt.largeVariance {
+				t.Errorf("LargeVariance = %v, want %v", summary.LargeVariance, tt.largeVariance)
+			}
+		})
+	}
+}
+
+func TestSummarizeScoresWithoutScores(t *testing.T) {
+	summary := summarizeScores(7, 3, nil)
+	if summary.Average != 0 || summary.Min != 0 || summary.Max != 0 || summary.StdDev != 0 || summary.LargeVariance {
+		t.Errorf("summary of no scores = %+v, want zero values", summary)
+	}
+}
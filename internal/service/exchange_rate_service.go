@@ -0,0 +1,91 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	repository "tender-managment/internal/db/repo"
+	"tender-managment/internal/model"
+)
+
+type ExchangeRateService struct {
+	repo *repository.ExchangeRateRepository
+}
+
+func NewExchangeRateService(repo *repository.ExchangeRateRepository) *ExchangeRateService {
+	return &ExchangeRateService{repo: repo}
+}
+
+// Rate returns the factor that converts an amount in base into quote. Rates
+// are looked up in both directions, so each pair only needs one row.
+func (s *ExchangeRateService) Rate(base, quote string) (*big.Rat, error) {
+	if base == quote {
+		return big.NewRat(1, 1), nil
+	}
+	if !model.ValidCurrency(quote) {
+		return nil, errors.New("invalid currency")
+	}
+
+	value, err := s.repo.GetRate(base, quote)
+	if err == nil {
+		return parseRate(value)
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+
+	value, err = s.repo.GetRate(quote, base)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("no exchange rate from %s to %s", base, quote)
+	}
+	if err != nil {
+		return nil, err
+	}
+	rate, err := parseRate(value)
+	if err != nil {
+		return nil, err
+	}
+	return rate.Inv(rate), nil
+}
+
+func (s *ExchangeRateService) ListRates() ([]model.ExchangeRate, error) {
+	return s.repo.ListRates()
+}
+
+func parseRate(value string) (*big.Rat, error) {
+	rate, ok := new(big.Rat).SetString(value)
+	if !ok || rate.Sign() <= 0 {
+		return nil, fmt.Errorf("invalid exchange rate %q", value)
+	}
+	return rate, nil
+}
+
+// SetRate stores an exchange rate. When only the inverse pair is stored, that
+// row is updated instead so each pair keeps a single rate.
+func (s *ExchangeRateService) SetRate(base, quote, value string) (*model.ExchangeRate, error) {
+	base, quote = strings.ToUpper(strings.TrimSpace(base)), strings.ToUpper(strings.TrimSpace(quote))
+	if !model.ValidCurrency(base) || !model.ValidCurrency(quote) || base == quote {
+		return nil, errors.New("invalid currency")
+	}
+	rate, err := parseRate(value)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.repo.GetRate(base, quote)
+	if errors.Is(err, sql.ErrNoRows) {
+		_, err = s.repo.GetRate(quote, base)
+		if err == nil {
+			return s.repo.SetRate(quote, base, new(big.Rat).Inv(rate).FloatString(8))
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			err = nil
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.SetRate(base, quote, rate.FloatString(8))
+}
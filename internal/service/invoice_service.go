@@ -0,0 +1,347 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	repository "tender-managment/internal/db/repo"
+	"tender-managment/internal/model"
+	"tender-managment/internal/pdf"
+	"tender-managment/internal/utils"
+	"time"
+)
+
+type InvoiceService struct {
+	repo          *repository.InvoiceRepository
+	contractRepo  *repository.ContractRepository
+	milestoneRepo *repository.MilestoneRepository
+	userRepo      *repository.UserRepository
+	bidRepo       *repository.BidRepository
+}
+
+func NewInvoiceService(repo *repository.InvoiceRepository, contractRepo *repository.ContractRepository, milestoneRepo *repository.MilestoneRepository, userRepo *repository.UserRepository, bidRepo *repository.BidRepository) *InvoiceService {
+	return &InvoiceService{
+		repo:          repo,
+		contractRepo:  contractRepo,
+		milestoneRepo: milestoneRepo,
+		userRepo:      userRepo,
+		bidRepo:       bidRepo,
+	}
+}
+
+// CreateInvoice raises an invoice against an active or completed contract,
+// or against one of its accepted milestones. Line amounts and taxes are
+// calculated server-side. Invoicing beyond the contract price or a
+// milestone's share is allowed but flagged in the returned warnings.
+func (s *InvoiceService) CreateInvoice(contractorID, contractID int, payload model.CreateInvoice) (*model.Invoice, error) {
+	contract, err := s.contractRepo.GetContractByID(contractID)
+	if err != nil || contract.ContractorID != contractorID {
+		return nil, errors.New("contract not found")
+	}
+	if contract.Status != model.ContractStatusActive && contract.Status != model.ContractStatusCompleted {
+		return nil, errors.New("invoices can only be raised on active or completed contracts")
+	}
+
+	var milestone *model.Milestone
+	if payload.MilestoneID != nil {
+		milestone, err = s.milestoneRepo.GetMilestoneByID(*payload.MilestoneID)
+		if err != nil || milestone.ContractID != contractID {
+			return nil, errors.New("milestone not found")
+		}
+		if milestone.Status != model.MilestoneStatusAccepted {
+			return nil, errors.New("only accepted milestones can be invoiced")
+		}
+	}
+
+	invoice := &model.Invoice{
+		ContractID:   contractID,
+		MilestoneID:  payload.MilestoneID,
+		ContractorID: contractorID,
+		ClientID:     contract.ClientID,
+		Currency:     contract.Price.Currency,
+		Notes:        strings.TrimSpace(payload.Notes),
+	}
+	if err := priceInvoice(invoice, payload.Lines, payload.Taxes); err != nil {
+		return nil, err
+	}
+
+	warnings, err := s.overInvoicing(contract, milestone, invoice.Subtotal)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := s.repo.CreateInvoice(invoice)
+	if err != nil {
+		return nil, err
+	}
+	created.Warnings = warnings
+
+	message := fmt.Sprintf("Invoice %s for %s %s has been submitted for contract #%d", created.Number, created.Total, created.Currency, contractID)
+	if len(warnings) > 0 {
+		message += ". Warning: " + strings.Join(warnings, "; ")
+	}
+	utils.SendNotification(*s.bidRepo, contract.ClientID, message, strconv.Itoa(created.ID), "invoice_submitted")
+
+	return created, nil
+}
+
+// priceInvoice validates the lines and taxes and fills in their amounts and
+// the invoice totals.
+func priceInvoice(invoice *model.Invoice, lines []model.InvoiceLine, taxes []model.InvoiceTax) error {
+	if len(lines) == 0 {
+		return errors.New("an invoice needs at least one line")
+	}
+
+	invoice.Lines = make([]model.InvoiceLine, 0, len(lines))
+	for i, line := range lines {
+		line.Description = strings.TrimSpace(line.Description)
+		if line.Description == "" {
+			return fmt.Errorf("line %d needs a description", i+1)
+		}
+		if line.Quantity <= 0 {
+			return fmt.Errorf("line %d needs a positive quantity", i+1)
+		}
+		if line.UnitPrice < 0 {
+			return fmt.Errorf("line %d has a negative unit price", i+1)
+		}
+		line.Amount = line.UnitPrice.MulQuantity(line.Quantity)
+		invoice.Subtotal += line.Amount
+		invoice.Lines = append(invoice.Lines, line)
+	}
+	if invoice.Subtotal <= 0 {
+		return errors.New("invoice total must be positive")
+	}
+
+	invoice.Taxes = make([]model.InvoiceTax, 0, len(taxes))
+	for i, tax := range taxes {
+		tax.Name = strings.TrimSpace(tax.Name)
+		if tax.Name == "" {
+			return fmt.Errorf("tax %d needs a name", i+1)
+		}
+		if tax.Rate < 0 || tax.Rate > 10000 {
+			return fmt.Errorf("tax %d must have a rate between 0 and 100", i+1)
+		}
+		tax.Amount = invoice.Subtotal.Percent(tax.Rate)
+		invoice.TaxTotal += tax.Amount
+		invoice.Taxes = append(invoice.Taxes, tax)
+	}
+
+	invoice.Total = invoice.Subtotal + invoice.TaxTotal
+	return nil
+}
+
+// overInvoicing reports where a new invoice of subtotal would take the
+// undisputed invoices beyond the contract price or the milestone's share.
+func (s *InvoiceService) overInvoicing(contract *model.Contract, milestone *model.Milestone, subtotal model.Amount) ([]string, error) {
+	var warnings []string
+
+	reconciliation, err := s.reconcile(contract)
+	if err != nil {
+		return nil, err
+	}
+	if invoiced := reconciliation.Invoiced + subtotal; invoiced > contract.Price.Amount {
+		warnings = append(warnings, fmt.Sprintf("invoiced total of %s %s exceeds the contract price of %s %s",
+			invoiced, contract.Price.Currency, contract.Price.Amount, contract.Price.Currency))
+	}
+
+	if milestone != nil {
+		invoiced, err := s.repo.MilestoneInvoiced(milestone.ID)
+		if err != nil {
+			return nil, err
+		}
+		if invoiced+subtotal > milestone.Amount {
+			warnings = append(warnings, fmt.Sprintf("milestone %q is invoiced beyond its share of %s %s",
+				milestone.Title, milestone.Amount, contract.Price.Currency))
+		}
+	}
+
+	return warnings, nil
+}
+
+func (s *InvoiceService) ListInvoices(userID, contractID int) ([]model.Invoice, error) {
+	if _, err := s.getContract(userID, contractID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetInvoicesByContractID(contractID)
+}
+
+func (s *InvoiceService) GetInvoice(userID, invoiceID int) (*model.Invoice, error) {
+	invoice, err := s.repo.GetInvoiceByID(invoiceID)
+	if err != nil || (invoice.ClientID != userID && invoice.ContractorID != userID) {
+		return nil, errors.New("invoice not found")
+	}
+	return invoice, nil
+}
+
+// Reconcile compares the contract's invoices with its awarded price.
+func (s *InvoiceService) Reconcile(userID, contractID int) (*model.InvoiceReconciliation, error) {
+	contract, err := s.getContract(userID, contractID)
+	if err != nil {
+		return nil, err
+	}
+	return s.reconcile(contract)
+}
+
+func (s *InvoiceService) reconcile(contract *model.Contract) (*model.InvoiceReconciliation, error) {
+	totals, err := s.repo.InvoicedTotals(contract.ID)
+	if err != nil {
+		return nil, err
+	}
+	return reconcileTotals(contract, totals), nil
+}
+
+// reconcileTotals sums the invoice subtotals per status. Disputed invoices
+// do not count towards what has been invoiced.
+func reconcileTotals(contract *model.Contract, totals map[string]model.Amount) *model.InvoiceReconciliation {
+	r := &model.InvoiceReconciliation{
+		ContractID: contract.ID,
+		Price:      contract.Price,
+		Paid:       totals[model.InvoiceStatusPaid],
+		Disputed:   totals[model.InvoiceStatusDisputed],
+	}
+	r.Approved = totals[model.InvoiceStatusApproved] + r.Paid
+	r.Invoiced = totals[model.InvoiceStatusSubmitted] + r.Approved
+	r.Remaining = contract.Price.Amount - r.Invoiced
+	r.OverInvoiced = r.Remaining < 0
+
+	return r
+}
+
+func (s *InvoiceService) ApproveInvoice(clientID, invoiceID int) error {
+	invoice, err := s.clientInvoice(clientID, invoiceID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.Approve(invoiceID); err != nil {
+		return err
+	}
+
+	message := fmt.Sprintf("Invoice %s has been approved", invoice.Number)
+	utils.SendNotification(*s.bidRepo, invoice.ContractorID, message, strconv.Itoa(invoiceID), "invoice_approved")
+	return nil
+}
+
+func (s *InvoiceService) DisputeInvoice(clientID, invoiceID int, reason string) error {
+	invoice, err := s.clientInvoice(clientID, invoiceID)
+	if err != nil {
+		return err
+	}
+
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return errors.New("a reason is required to dispute an invoice")
+	}
+
+	if err := s.repo.Dispute(invoiceID, reason); err != nil {
+		return err
+	}
+
+	message := fmt.Sprintf("Invoice %s has been disputed: %s", invoice.Number, reason)
+	utils.SendNotification(*s.bidRepo, invoice.ContractorID, message, strconv.Itoa(invoiceID), "invoice_disputed")
+	return nil
+}
+
+// RecordPayment marks an approved invoice as paid. Without a date the payment
+// is recorded as made now.
+func (s *InvoiceService) RecordPayment(clientID, invoiceID int, reference string, paidAt *time.Time) error {
+	invoice, err := s.clientInvoice(clientID, invoiceID)
+	if err != nil {
+		return err
+	}
+
+	reference = strings.TrimSpace(reference)
+	if reference == "" {
+		return errors.New("a payment reference is required")
+	}
+	when := time.Now()
+	if paidAt != nil {
+		if paidAt.After(when) {
+			return errors.New("payment date cannot be in the future")
+		}
+		when = *paidAt
+	}
+
+	if err := s.repo.MarkPaid(invoiceID, reference, when); err != nil {
+		return err
+	}
+
+	message := fmt.Sprintf("Payment for invoice %s has been recorded (reference %s)", invoice.Number, reference)
+	utils.SendNotification(*s.bidRepo, invoice.ContractorID, message, strconv.Itoa(invoiceID), "invoice_paid")
+	return nil
+}
+
+// RenderPDF writes the invoice document to w.
+func (s *InvoiceService) RenderPDF(userID, invoiceID int, w io.Writer) (*model.Invoice, error) {
+	invoice, err := s.GetInvoice(userID, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := pdf.New()
+	doc.Heading("Invoice "+invoice.Number, 18)
+	doc.Gap()
+	doc.Line("Issued: " + invoice.CreatedAt.Format("2006-01-02"))
+	doc.Line("Status: " + invoice.Status)
+	doc.Line(fmt.Sprintf("Contract: #%d", invoice.ContractID))
+	if invoice.MilestoneID != nil {
+		if milestone, err := s.milestoneRepo.GetMilestoneByID(*invoice.MilestoneID); err == nil {
+			doc.Line(fmt.Sprintf("Milestone: %d. %s", milestone.Sequence, milestone.Title))
+		}
+	}
+	doc.Line("From: " + s.partyName(invoice.ContractorID))
+	doc.Line("To: " + s.partyName(invoice.ClientID))
+	doc.Gap()
+
+	columns := []float64{0, 260, 330, 420}
+	doc.Row(true, columns, "Description", "Quantity", "Unit price", "Amount")
+	for _, line := range invoice.Lines {
+		doc.Row(false, columns, line.Description, strconv.FormatFloat(line.Quantity, 'f', -1, 64), line.UnitPrice.String(), line.Amount.String())
+	}
+	doc.Gap()
+	doc.Row(false, columns, "Subtotal", "", "", invoice.Subtotal.String())
+	for _, tax := range invoice.Taxes {
+		doc.Row(false, columns, fmt.Sprintf("%s (%s%%)", tax.Name, tax.Rate), "", "", tax.Amount.String())
+	}
+	doc.Row(true, columns, "Total "+invoice.Currency, "", "", invoice.Total.String())
+
+	if invoice.Notes != "" {
+		doc.Gap()
+		doc.Line("Notes: " + invoice.Notes)
+	}
+	if invoice.PaidAt != nil {
+		doc.Gap()
+		doc.Line(fmt.Sprintf("Paid on %s, reference %s", invoice.PaidAt.Format("2006-01-02"), invoice.PaymentReference))
+	}
+
+	if _, err := doc.WriteTo(w); err != nil {
+		return nil, err
+	}
+	return invoice, nil
+}
+
+func (s *InvoiceService) partyName(userID int) string {
+	user, err := s.userRepo.GetUserByID(userID)
+	if err != nil {
+		return fmt.Sprintf("User #%d", userID)
+	}
+	return user.Username
+}
+
+func (s *InvoiceService) getContract(userID, contractID int) (*model.Contract, error) {
+	contract, err := s.contractRepo.GetContractByID(contractID)
+	if err != nil || (contract.ClientID != userID && contract.ContractorID != userID) {
+		return nil, errors.New("contract not found")
+	}
+	return contract, nil
+}
+
+func (s *InvoiceService) clientInvoice(clientID, invoiceID int) (*model.Invoice, error) {
+	invoice, err := s.repo.GetInvoiceByID(invoiceID)
+	if err != nil || invoice.ClientID != clientID {
+		return nil, errors.New("invoice not found")
+	}
+	return invoice, nil
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"reflect"
+	"tender-managment/internal/model"
+	"testing"
+)
+
+func TestPriceInvoice(t *testing.T) {
+	lines := []model.InvoiceLine{
+		{Description: " Design ", Quantity: 2, UnitPrice: 150000, Amount: 1},
+		{Description: "Site visits", Quantity: 1.5, UnitPrice: 33333},
+	}
+	taxes := []model.InvoiceTax{
+		{Name: "VAT ", Rate: 2000, Amount: 1},
+		{Name: "Levy", Rate: 150},
+	}
+
+	var invoice model.Invoice
+	if err := priceInvoice(&invoice, lines, taxes); err != nil {
+		t.Fatalf("priceInvoice returned error: %v", err)
+	}
+
+	wantLines := []model.InvoiceLine{
+		{Description: "Design", Quantity: 2, UnitPrice: 150000, Amount: 300000},
+		{Description: "Site visits", Quantity: 1.5, UnitPrice: 33333, Amount: 50000},
+	}
+	if !reflect.DeepEqual(invoice.Lines, wantLines) {
+		t.Errorf("Lines = %+v, want %+v", invoice.Lines, wantLines)
+	}
+	wantTaxes := []model.InvoiceTax{
+		{Name: "VAT", Rate: 2000, Amount: 70000},
+		{Name: "Levy", Rate: 150, Amount: 5250},
+	}
+	if !reflect.DeepEqual(invoice.Taxes, wantTaxes) {
+		t.Errorf("Taxes = %+v, want %+v", invoice.Taxes, wantTaxes)
+	}
+	if invoice.Subtotal != 350000 || invoice.TaxTotal != 75250 || invoice.Total != 425250 {
+		t.Errorf("Subtotal, TaxTotal, Total = %s, %s, %s, want 3500.00, 752.50, 4252.50",
+			invoice.Subtotal, invoice.TaxTotal, invoice.Total)
+	}
+}
+
+func TestPriceInvoiceWithoutTaxes(t *testing.T) {
+	var invoice model.Invoice
+	lines := []model.InvoiceLine{{Description: "Retainer", Quantity: 1, UnitPrice: 99999}}
+	if err := priceInvoice(&invoice, lines, nil); err != nil {
+		t.Fatalf("priceInvoice returned error: %v", err)
+	}
+	if invoice.Subtotal != 99999 || invoice.TaxTotal != 0 || invoice.Total != 99999 || len(invoice.Taxes) != 0 {
+		t.Errorf("invoice = %+v, want a total of 999.99 without taxes", invoice)
+	}
+}
+
+func TestPriceInvoiceRejects(t *testing.T) {
+	line := model.InvoiceLine{Description: "Work", Quantity: 1, UnitPrice: 1000}
+	tax := model.InvoiceTax{Name: "VAT", Rate: 2000}
+
+	tests := []struct {
+		name  string
+		lines []model.InvoiceLine
+		taxes []model.InvoiceTax
+		want  string
+	}{
+		{"no lines", nil, nil, "an invoice needs at least one line"},
+		{"blank description", []model.InvoiceLine{line, {Description: " ", Quantity: 1, UnitPrice: 1000}}, nil,
+			"line 2 needs a description"},
+		{"zero quantity", []model.InvoiceLine{{Description: "Work", Quantity: 0, UnitPrice: 1000}}, nil,
+			"line 1 needs a positive quantity"},
+		{"negative quantity", []model.InvoiceLine{{Description: "Work", Quantity: -1, UnitPrice: 1000}}, nil,
+			"line 1 needs a positive quantity"},
+		{"negative unit price", []model.InvoiceLine{line, {Description: "Credit", Quantity: 1, UnitPrice: -500}}, nil,
+			"line 2 has a negative unit price"},
+		{"zero total", []model.InvoiceLine{{Description: "Free", Quantity: 1, UnitPrice: 0}}, nil,
+			"invoice total must be positive"},
+		{"unnamed tax", []model.InvoiceLine{line}, []model.InvoiceTax{tax, {Name: " ", Rate: 500}},
+			"tax 2 needs a name"},
+		{"negative tax rate", []model.InvoiceLine{line}, []model.InvoiceTax{{Name: "VAT", Rate: -1}},
+			"tax 1 must have a rate between 0 and 100"},
+		{"tax rate above 100", []model.InvoiceLine{line}, []model.InvoiceTax{{Name: "VAT", Rate: 10001}},
+			"tax 1 must have a rate between 0 and 100"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var invoice model.Invoice
+			err := priceInvoice(&invoice, tt.lines, tt.taxes)
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("priceInvoice error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestReconcileTotals(t *testing.T) {
+	contract := &model.Contract{ID: 4, Price: model.Money{Amount: 1000000, Currency: "EUR"}}
+
+	tests := []struct {
+		name   string
+		totals map[string]model.Amount
+		want   model.InvoiceReconciliation
+	}{
+		{
+			name:   "nothing invoiced",
+			totals: map[string]model.Amount{},
+			want:   model.InvoiceReconciliation{Remaining: 1000000},
+		},
+		{
+			name: "every status",
+			totals: map[string]model.Amount{
+				model.InvoiceStatusSubmitted: 100000,
+				model.InvoiceStatusApproved:  200000,
+				model.InvoiceStatusPaid:      300000,
+				model.InvoiceStatusDisputed:  250000,
+			},
+			want: model.InvoiceReconciliation{
+				Invoiced: 600000, Approved: 500000, Paid: 300000, Disputed: 250000, Remaining: 400000,
+			},
+		},
+		{
+			name:   "fully invoiced",
+			totals: map[string]model.Amount{model.InvoiceStatusPaid: 1000000},
+			want: model.InvoiceReconciliation{
+				Invoiced: 1000000, Approved: 1000000, Paid: 1000000,
+			},
+		},
+		{
+			name: "over-invoiced",
+			totals: map[string]model.Amount{
+				model.InvoiceStatusSubmitted: 1,
+				model.InvoiceStatusPaid:      1000000,
+			},
+			want: model.InvoiceReconciliation{
+				Invoiced: 1000001, Approved: 1000000, Paid: 1000000, Remaining: -1, OverInvoiced: true,
+			},
+		},
+		{
+			name:   "disputed invoices do not count",
+			totals: map[string]model.Amount{model.InvoiceStatusDisputed: 2000000},
+			want:   model.InvoiceReconciliation{Disputed: 2000000, Remaining: 1000000},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.want.ContractID = contract.ID
+			tt.want.Price = contract.Price
+
+			got := reconcileTotals(contract, tt.totals)
+			if !reflect.DeepEqual(*got, tt.want) {
+				t.Errorf("reconcileTotals = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"tender-managment/internal/db"
+	"tender-managment/internal/utils"
+	"time"
+)
+
+const (
+	loginFailuresKey = "login_failures:%s:%s"
+	loginBlockedKey  = "login_blocked:%s:%s"
+
+	// freeLoginAttempts is how many failures an account may have before
+	// every further attempt is delayed.
+	freeLoginAttempts = 3
+	maxLoginDelay     = time.Minute
+)
+
+// LoginThrottle counts failed logins per account and per client IP in Redis.
+// Each failure past the free attempts makes the account wait twice as long
+// before its next attempt; reaching a limit locks the account or the IP out
+// for a while. When Redis is unavailable logins are not throttled.
+type LoginThrottle struct {
+	store        *db.Redis
+	window       time.Duration
+	lockout      time.Duration
+	accountLimit int64
+	ipLimit      int64
+}
+
+// NewLoginThrottle creates the throttle. Zero settings fall back to a
+// 15-minute window and lockout, 10 failures per account and 50 per IP.
+func NewLoginThrottle(store *db.Redis, window, lockout time.Duration, accountLimit, ipLimit int) *LoginThrottle {
+	if window <= 0 {
+		window = 15 * time.Minute
+	}
+	if lockout <= 0 {
+		lockout = 15 * time.Minute
+	}
+	if accountLimit <= 0 {
+		accountLimit = 10
+	}
+	if ipLimit <= 0 {
+		ipLimit = 50
+	}
+	return &LoginThrottle{
+		store:        store,
+		window:       window,
+		lockout:      lockout,
+		accountLimit: int64(accountLimit),
+		ipLimit:      int64(ipLimit),
+	}
+}
+
+// Check returns how long the caller has to wait before the next attempt for
+// the account from the IP.
+func (t *LoginThrottle) Check(ctx context.Context, account, ip string) time.Duration {
+	var wait time.Duration
+	for _, key := range []string{t.key(loginBlockedKey, "account", account), t.key(loginBlockedKey, "ip", ip)} {
+		ttl, err := t.store.TTL(ctx, key)
+		if err != nil {
+			log.Println("Error checking login throttle:", err)
+			continue
+		}
+		if ttl > wait {
+			wait = ttl
+		}
+	}
+	if wait > 0 {
+		utils.LogSecurityEvent("login_throttled", "account", account, "ip", ip, "wait_seconds", int(wait.Seconds()))
+	}
+	return wait
+}
+
+// Fail records a failed attempt and delays or locks out further attempts.
+func (t *LoginThrottle) Fail(ctx context.Context, account, ip, reason string) {
+	failures, err := t.store.Incr(ctx, t.key(loginFailuresKey, "account", account), t.window)
+	if err != nil {
+		log.Println("Error counting failed login:", err)
+	}
+	utils.LogSecurityEvent("login_failed", "account", account, "ip", ip, "reason", reason, "account_failures", failures)
+
+	switch {
+	case failures >= t.accountLimit:
+		t.block(ctx, "account", account, t.lockout)
+		if failures == t.accountLimit {
+			utils.LogSecurityEvent("account_locked", "account", account, "ip", ip, "lockout_minutes", int(t.lockout.Minutes()))
+		}
+	case failures > freeLoginAttempts:
+		delay := time.Second << (failures - freeLoginAttempts - 1)
+		if delay > maxLoginDelay {
+			delay = maxLoginDelay
+		}
+		t.block(ctx, "account", account, delay)
+	}
+
+	ipFailures, err := t.store.Incr(ctx, t.key(loginFailuresKey, "ip", ip), t.window)
+	if err != nil {
+		log.Println("Error counting failed login:", err)
+		return
+	}
+	switch {
+	case ipFailures >= t.ipLimit:
+		t.block(ctx, "ip", ip, t.lockout)
+		if ipFailures == t.ipLimit {
+			utils.LogSecurityEvent("ip_locked", "ip", ip, "ip_failures", ipFailures, "lockout_minutes", int(t.lockout.Minutes()))
+		}
+	case ipFailures == t.ipLimit/2:
+		// Many failures from one address, usually spread over several
+		// accounts, points at password spraying.
+		utils.LogSecurityEvent("suspicious_login_activity", "ip", ip, "ip_failures", ipFailures)
+	}
+}
+
+// Succeed clears the account's failures after a complete login.
+func (t *LoginThrottle) Succeed(ctx context.Context, account, ip string) {
+	failuresKey := t.key(loginFailuresKey, "account", account)
+	value, err := t.store.Get(ctx, failuresKey)
+	if err != nil {
+		log.Println("Error reading failed logins:", err)
+	}
+	if failures, _ := strconv.Atoi(value); failures > freeLoginAttempts {
+		utils.LogSecurityEvent("login_after_failures", "account", account, "ip", ip, "account_failures", failures)
+	}
+
+	if err := t.store.Del(ctx, failuresKey); err != nil {
+		log.Println("Error clearing failed logins:", err)
+	}
+	if err := t.store.Del(ctx, t.key(loginBlockedKey, "account", account)); err != nil {
+		log.Println("Error clearing failed logins:", err)
+	}
+}
+
+func (t *LoginThrottle) block(ctx context.Context, kind, id string, d time.Duration) {
+	if err := t.store.Set(ctx, t.key(loginBlockedKey, kind, id), "1", d); err != nil {
+		log.Println("Error blocking login:", err)
+	}
+}
+
+// key names the Redis key for an account or IP. Account names are compared
+// case-insensitively so "Alice" and "alice" share one counter.
+func (t *LoginThrottle) key(format, kind, id string) string {
+	if kind == "account" {
+		id = strings.ToLower(id)
+	}
+	return fmt.Sprintf(format, kind, id)
+}
@@ -0,0 +1,298 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	repository "tender-managment/internal/db/repo"
+	"tender-managment/internal/model"
+	"tender-managment/internal/storage"
+	"tender-managment/internal/utils"
+	"time"
+)
+
+type MilestoneService struct {
+	repo         *repository.MilestoneRepository
+	contractRepo *repository.ContractRepository
+	bidRepo      *repository.BidRepository
+	storage      storage.Storage
+	maxFileSize  int64
+	allowedTypes []string
+}
+
+func NewMilestoneService(repo *repository.MilestoneRepository, contractRepo *repository.ContractRepository, bidRepo *repository.BidRepository, store storage.Storage, maxFileSize int64, allowedTypes []string) *MilestoneService {
+	return &MilestoneService{
+		repo:         repo,
+		contractRepo: contractRepo,
+		bidRepo:      bidRepo,
+		storage:      store,
+		maxFileSize:  maxFileSize,
+		allowedTypes: allowedTypes,
+	}
+}
+
+// SeedSchedule gives a new contract its default schedule: a single milestone
+// for the whole price, due after the winning bid's delivery time.
+func (s *MilestoneService) SeedSchedule(contract *model.Contract) error {
+	_, err := s.repo.ReplaceMilestones(contract.ID, []model.Milestone{{
+		Title:   "Delivery",
+		DueDate: contract.StartDate.AddDate(0, 0, contract.DeliveryTime),
+		Amount:  contract.Price.Amount,
+	}})
+	return err
+}
+
+// SetSchedule replaces the contract's milestones. The schedule can change
+// until the first milestone is delivered, and its amounts must add up to the
+// contract price.
+func (s *MilestoneService) SetSchedule(clientID, contractID int, items []model.SetMilestone) ([]model.Milestone, error) {
+	contract, err := s.contractRepo.GetContractByID(contractID)
+	if err != nil || contract.ClientID != clientID {
+		return nil, errors.New("contract not found")
+	}
+	if contract.Status != model.ContractStatusPending && contract.Status != model.ContractStatusActive {
+		return nil, errors.New("contract is closed")
+	}
+	if len(items) == 0 {
+		return nil, errors.New("at least one milestone is required")
+	}
+
+	milestones := make([]model.Milestone, 0, len(items))
+	var total model.Amount
+	for i, item := range items {
+		title := strings.TrimSpace(item.Title)
+		if title == "" {
+			return nil, fmt.Errorf("milestone %d needs a title", i+1)
+		}
+		dueDate, err := time.Parse(time.RFC3339, item.DueDate)
+		if err != nil {
+			return nil, fmt.Errorf("milestone %d has an invalid due_date", i+1)
+		}
+		if dueDate.Before(contract.StartDate) {
+			return nil, fmt.Errorf("milestone %d is due before the contract starts", i+1)
+		}
+		if item.Amount < 0 {
+			return nil, fmt.Errorf("milestone %d has a negative amount", i+1)
+		}
+
+		total += item.Amount
+		milestones = append(milestones, model.Milestone{
+			Title:       title,
+			Description: item.Description,
+			DueDate:     dueDate,
+			Amount:      item.Amount,
+		})
+	}
+	if total != contract.Price.Amount {
+		return nil, fmt.Errorf("milestone amounts must add up to the contract price of %s %s", contract.Price.Amount, contract.Price.Currency)
+	}
+
+	saved, err := s.repo.ReplaceMilestones(contractID, milestones)
+	if err != nil {
+		return nil, err
+	}
+
+	message := fmt.Sprintf("The milestone schedule of contract #%d has been updated", contractID)
+	utils.SendNotification(*s.bidRepo, contract.ContractorID, message, strconv.Itoa(contractID), "milestone_schedule")
+
+	return saved, nil
+}
+
+func (s *MilestoneService) ListMilestones(userID, contractID int) ([]model.Milestone, error) {
+	contract, err := s.contractRepo.GetContractByID(contractID)
+	if err != nil || (contract.ClientID != userID && contract.ContractorID != userID) {
+		return nil, errors.New("contract not found")
+	}
+	return s.repo.GetMilestones(contractID)
+}
+
+// Deliver lets the contractor hand over a milestone of an active contract for
+// the client's review.
+func (s *MilestoneService) Deliver(contractorID, contractID, milestoneID int, note string) (*model.Milestone, error) {
+	contract, _, err := s.getMilestone(contractorID, contractID, milestoneID)
+	if err != nil || contract.ContractorID != contractorID {
+		return nil, errors.New("milestone not found")
+	}
+	if contract.Status != model.ContractStatusActive {
+		return nil, errors.New("contract is not active")
+	}
+
+	delivered, err := s.repo.Deliver(milestoneID, strings.TrimSpace(note))
+	if err != nil {
+		return nil, err
+	}
+
+	message := fmt.Sprintf("Milestone %q of contract #%d has been delivered and awaits your review", delivered.Title, contractID)
+	utils.SendNotification(*s.bidRepo, contract.ClientID, message, strconv.Itoa(milestoneID), "milestone_delivered")
+
+	return delivered, nil
+}
+
+// Review accepts or rejects a delivered milestone. A rejection needs a comment
+// so the contractor knows what to fix before delivering again.
+func (s *MilestoneService) Review(clientID, contractID, milestoneID int, accept bool, comment string) (*model.Milestone, error) {
+	contract, _, err := s.getMilestone(clientID, contractID, milestoneID)
+	if err != nil || contract.ClientID != clientID {
+		return nil, errors.New("milestone not found")
+	}
+
+	comment = strings.TrimSpace(comment)
+	status := model.MilestoneStatusAccepted
+	if !accept {
+		if comment == "" {
+			return nil, errors.New("a comment is required to reject a milestone")
+		}
+		status = model.MilestoneStatusRejected
+	}
+
+	reviewed, err := s.repo.Review(milestoneID, status, comment)
+	if err != nil {
+		return nil, err
+	}
+
+	message := fmt.Sprintf("Milestone %q of contract #%d has been %s", reviewed.Title, contractID, status)
+	if comment != "" {
+		message += ": " + comment
+	}
+	utils.SendNotification(*s.bidRepo, contract.ContractorID, message, strconv.Itoa(milestoneID), "milestone_review")
+
+	return reviewed, nil
+}
+
+// AddDocument attaches proof of delivery to a milestone that has not been
+// accepted yet.
+func (s *MilestoneService) AddDocument(ctx context.Context, contractorID, contractID, milestoneID int, fileName string, r io.Reader) (*model.MilestoneDocument, int, error) {
+	contract, milestone, err := s.getMilestone(contractorID, contractID, milestoneID)
+	if err != nil || contract.ContractorID != contractorID {
+		return nil, http.StatusNotFound, errors.New("milestone not found")
+	}
+	if milestone.Status == model.MilestoneStatusAccepted {
+		return nil, http.StatusBadRequest, errors.New("milestone has already been accepted")
+	}
+
+	doc := &model.MilestoneDocument{MilestoneID: milestoneID, FileName: filepath.Base(fileName)}
+	if doc.FileName == "." || doc.FileName == string(filepath.Separator) {
+		return nil, http.StatusBadRequest, errors.New("invalid file name")
+	}
+
+	key, err := newStorageKey(fmt.Sprintf("contracts/%d", contractID))
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	blob, status, err := saveBlob(ctx, s.storage, key, r, s.maxFileSize, s.allowedTypes)
+	if err != nil {
+		return nil, status, err
+	}
+
+	doc.StorageKey = key
+	doc.MimeType = blob.MimeType
+	doc.Size = blob.Size
+	doc.SHA256 = blob.SHA256
+
+	created, err := s.repo.AddDocument(doc)
+	if err != nil {
+		_ = s.storage.Delete(ctx, key)
+		return nil, http.StatusInternalServerError, err
+	}
+
+	return created, http.StatusCreated, nil
+}
+
+// OpenDocument returns a milestone document to either party of the contract.
+// The caller must close the returned reader.
+func (s *MilestoneService) OpenDocument(ctx context.Context, userID, documentID int) (*model.MilestoneDocument, io.ReadCloser, error) {
+	doc, err := s.repo.GetDocumentByID(documentID)
+	if err != nil {
+		return nil, nil, errors.New("document not found")
+	}
+	milestone, err := s.repo.GetMilestoneByID(doc.MilestoneID)
+	if err != nil {
+		return nil, nil, errors.New("document not found")
+	}
+	if _, _, err := s.getMilestone(userID, milestone.ContractID, milestone.ID); err != nil {
+		return nil, nil, errors.New("document not found")
+	}
+
+	file, err := s.storage.Open(ctx, doc.StorageKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open document: %w", err)
+	}
+
+	return doc, file, nil
+}
+
+// CheckAllAccepted fails while any milestone of the contract is outstanding.
+func (s *MilestoneService) CheckAllAccepted(contractID int) error {
+	accepted, err := s.repo.AllAccepted(contractID)
+	if err != nil {
+		return err
+	}
+	if !accepted {
+		return errors.New("all milestones must be accepted before the contract is completed")
+	}
+	return nil
+}
+
+// RunOverdueScheduler notifies both parties when a milestone of an active
+// contract passes its due date undelivered. It blocks until ctx is cancelled.
+func (s *MilestoneService) RunOverdueScheduler(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			milestones, err := s.repo.GetUnnotifiedOverdue()
+			if err != nil {
+				log.Println("Error checking overdue milestones:", err)
+				continue
+			}
+			for _, milestone := range milestones {
+				if !s.notifyOverdue(milestone) {
+					continue
+				}
+				if err := s.repo.MarkOverdueNotified(milestone.ID); err != nil {
+					log.Println("Error marking overdue milestone:", err)
+				}
+			}
+		}
+	}
+}
+
+// notifyOverdue alerts both parties and reports whether both alerts were
+// stored. Otherwise the milestone is tried again on the next run.
+func (s *MilestoneService) notifyOverdue(milestone model.Milestone) bool {
+	contract, err := s.contractRepo.GetContractByID(milestone.ContractID)
+	if err != nil {
+		log.Println("Error fetching contract for notification:", err)
+		return false
+	}
+
+	message := fmt.Sprintf("Milestone %q of contract #%d was due on %s and is overdue",
+		milestone.Title, contract.ID, milestone.DueDate.Format("2006-01-02"))
+	clientErr := utils.SendNotification(*s.bidRepo, contract.ClientID, message, strconv.Itoa(milestone.ID), "milestone_overdue")
+	contractorErr := utils.SendNotification(*s.bidRepo, contract.ContractorID, message, strconv.Itoa(milestone.ID), "milestone_overdue")
+	return clientErr == nil && contractorErr == nil
+}
+
+// getMilestone loads a milestone of the contract for either of its parties.
+func (s *MilestoneService) getMilestone(userID, contractID, milestoneID int) (*model.Contract, *model.Milestone, error) {
+	contract, err := s.contractRepo.GetContractByID(contractID)
+	if err != nil || (contract.ClientID != userID && contract.ContractorID != userID) {
+		return nil, nil, errors.New("milestone not found")
+	}
+	milestone, err := s.repo.GetMilestoneByID(milestoneID)
+	if err != nil || milestone.ContractID != contractID {
+		return nil, nil, errors.New("milestone not found")
+	}
+	return contract, milestone, nil
+}
@@ -0,0 +1,202 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	repository "tender-managment/internal/db/repo"
+	"tender-managment/internal/model"
+	"tender-managment/internal/utils"
+)
+
+type OrganizationService struct {
+	repo     *repository.OrganizationRepository
+	userRepo *repository.UserRepository
+	bidRepo  *repository.BidRepository
+}
+
+func NewOrganizationService(repo *repository.OrganizationRepository, userRepo *repository.UserRepository, bidRepo *repository.BidRepository) *OrganizationService {
+	return &OrganizationService{
+		repo:     repo,
+		userRepo: userRepo,
+		bidRepo:  bidRepo,
+	}
+}
+
+// CreateOrganization registers the user's company. The user becomes its first
+// member and their tenders and bids move to the organization.
+func (s *OrganizationService) CreateOrganization(userID int, payload model.SaveOrganization) (*model.Organization, error) {
+	user, err := s.userRepo.GetUserByID(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+	if user.Role == "admin" {
+		return nil, errors.New("administrators cannot join organizations")
+	}
+
+	org, err := normalizeOrganization(payload)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.CreateOrganization(org, userID)
+}
+
+func (s *OrganizationService) GetOrganization(orgID int) (*model.Organization, error) {
+	return s.repo.GetOrganizationByID(orgID)
+}
+
+func (s *OrganizationService) UpdateOrganization(userID, orgID int, payload model.SaveOrganization) (*model.Organization, error) {
+	if err := s.checkMember(userID, orgID); err != nil {
+		return nil, err
+	}
+
+	org, err := normalizeOrganization(payload)
+	if err != nil {
+		return nil, err
+	}
+	org.ID = orgID
+	return s.repo.UpdateOrganization(org)
+}
+
+func (s *OrganizationService) ListMembers(userID, orgID int) ([]model.UserProfile, error) {
+	if err := s.checkMember(userID, orgID); err != nil {
+		return nil, err
+	}
+	return s.userRepo.GetProfilesByOrgID(orgID)
+}
+
+// AddMember brings a colleague who has an account but no organization yet
+// into the organization.
+func (s *OrganizationService) AddMember(userID, orgID int, email, role string) (*model.UserProfile, error) {
+	if err := s.checkMember(userID, orgID); err != nil {
+		return nil, err
+	}
+	if !utils.IsOrgRole(role) {
+		return nil, fmt.Errorf("unknown role %q", role)
+	}
+
+	user, err := s.userRepo.GetUserByEmail(strings.TrimSpace(email))
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, errors.New("user not found")
+	}
+	if user.Role == "admin" {
+		return nil, errors.New("administrators cannot join organizations")
+	}
+
+	if err := s.repo.AddMember(orgID, user.ID, role); err != nil {
+		return nil, err
+	}
+
+	org, err := s.repo.GetOrganizationByID(orgID)
+	if err == nil {
+		message := fmt.Sprintf("You have been added to the organization %s as %s", org.LegalName, role)
+		utils.SendNotification(*s.bidRepo, user.ID, message, strconv.Itoa(orgID), "org_member_added")
+	}
+
+	return s.userRepo.GetProfile(user.ID)
+}
+
+// SetMemberRole changes the role of a member.
+func (s *OrganizationService) SetMemberRole(userID, orgID, memberID int, role string) (*model.UserProfile, error) {
+	if err := s.checkMember(userID, orgID); err != nil {
+		return nil, err
+	}
+	if !utils.IsOrgRole(role) {
+		return nil, fmt.Errorf("unknown role %q", role)
+	}
+
+	if err := s.repo.SetMemberRole(orgID, memberID, role); err != nil {
+		return nil, err
+	}
+
+	org, err := s.repo.GetOrganizationByID(orgID)
+	if err == nil {
+		message := fmt.Sprintf("Your role in the organization %s is now %s", org.LegalName, role)
+		utils.SendNotification(*s.bidRepo, memberID, message, strconv.Itoa(orgID), "org_role_changed")
+	}
+
+	return s.userRepo.GetProfile(memberID)
+}
+
+// RemoveMember takes a member out of the organization. Managing members needs
+// the org:manage permission, but anyone may remove themselves to leave.
+func (s *OrganizationService) RemoveMember(userID, orgID, memberID int) error {
+	if err := s.checkMember(userID, orgID); err != nil {
+		return err
+	}
+	if memberID != userID {
+		user, err := s.userRepo.GetUserByID(userID)
+		if err != nil {
+			return err
+		}
+		if !utils.HasPermission(user.Role, user.OrgRole, true, utils.PermOrgManage) {
+			return errors.New("only organization managers can remove other members")
+		}
+	}
+	return s.repo.RemoveMember(orgID, memberID)
+}
+
+func (s *OrganizationService) checkMember(userID, orgID int) error {
+	user, err := s.userRepo.GetUserByID(userID)
+	if err != nil || user.OrgID != orgID {
+		return errors.New("organization not found")
+	}
+	return nil
+}
+
+func normalizeOrganization(payload model.SaveOrganization) (*model.Organization, error) {
+	org := &model.Organization{
+		LegalName:                 strings.TrimSpace(payload.LegalName),
+		TaxID:                     strings.TrimSpace(payload.TaxID),
+		Address:                   strings.TrimSpace(payload.Address),
+		Contacts:                  make([]model.OrganizationContact, 0, len(payload.Contacts)),
+		RequireTwoFactorForAwards: payload.RequireTwoFactorForAwards,
+	}
+	if org.LegalName == "" || org.TaxID == "" {
+		return nil, errors.New("legal name and tax ID are required")
+	}
+
+	for i, c := range payload.Contacts {
+		c.Name = strings.TrimSpace(c.Name)
+		if c.Name == "" {
+			return nil, fmt.Errorf("contact %d needs a name", i+1)
+		}
+		c.Email = strings.TrimSpace(c.Email)
+		c.Phone = strings.TrimSpace(c.Phone)
+		c.Position = strings.TrimSpace(c.Position)
+		org.Contacts = append(org.Contacts, c)
+	}
+	return org, nil
+}
+
+// managesTender reports whether the user may manage the tender. Tenders of an
+// organization are shared by all of its members; other tenders only by the
+// client who created them.
+func managesTender(repo *repository.TenderRepository, tender *model.Tender, userID int) bool {
+	if tender.OrgID == 0 {
+		return tender.ClientID == userID
+	}
+	managed, err := repo.IsManagedBy(tender.ID, userID)
+	if err != nil {
+		log.Println("Error checking tender ownership:", err)
+	}
+	return managed
+}
+
+// managesBid is the counterpart of managesTender for bids.
+func managesBid(repo *repository.BidRepository, bid *model.Bid, userID int) bool {
+	if bid.OrgID == 0 {
+		return bid.ContractorID == userID
+	}
+	bidID, _ := strconv.Atoi(bid.ID)
+	managed, err := repo.IsManagedBy(bidID, userID)
+	if err != nil {
+		log.Println("Error checking bid ownership:", err)
+	}
+	return managed
+}
@@ -0,0 +1,347 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	repository "tender-managment/internal/db/repo"
+	"tender-managment/internal/model"
+	"tender-managment/internal/storage"
+	"tender-managment/internal/utils"
+	"time"
+)
+
+type ProtestService struct {
+	repo         *repository.ProtestRepository
+	tenderRepo   *repository.TenderRepository
+	bidRepo      *repository.BidRepository
+	storage      storage.Storage
+	standstill   time.Duration
+	maxFileSize  int64
+	allowedTypes []string
+}
+
+func NewProtestService(repo *repository.ProtestRepository, tenderRepo *repository.TenderRepository, bidRepo *repository.BidRepository, store storage.Storage, standstill time.Duration, maxFileSize int64, allowedTypes []string) *ProtestService {
+	return &ProtestService{
+		repo:         repo,
+		tenderRepo:   tenderRepo,
+		bidRepo:      bidRepo,
+		storage:      store,
+		standstill:   standstill,
+		maxFileSize:  maxFileSize,
+		allowedTypes: allowedTypes,
+	}
+}
+
+// StartStandstill makes a fresh award provisional and tells the losing
+// bidders how long they have to protest. Without a standstill period the
+// award becomes final straight away.
+func (s *ProtestService) StartStandstill(tender *model.Tender) error {
+	endsAt := time.Now().Add(s.standstill)
+	if err := s.tenderRepo.StartStandstill(tender.ID, endsAt); err != nil {
+		return fmt.Errorf("failed to start standstill period: %w", err)
+	}
+
+	if s.standstill <= 0 {
+		s.finalize(tender.ID)
+		return nil
+	}
+
+	bids, err := s.bidRepo.GetBidsByTenderID(tender.ID)
+	if err != nil {
+		log.Println("Error fetching bids for standstill notification:", err)
+		return nil
+	}
+	message := fmt.Sprintf("Tender %s has been provisionally awarded. You may file a protest until %s",
+		tender.Title, endsAt.Format(time.RFC3339))
+	notified := make(map[int]bool)
+	for _, bid := range bids {
+		if bid.Type != model.BidTypeBid || bid.Status == model.BidStatusAwarded || notified[bid.ContractorID] {
+			continue
+		}
+		notified[bid.ContractorID] = true
+		utils.SendNotification(*s.bidRepo, bid.ContractorID, message, strconv.Itoa(tender.ID), "award_standstill")
+	}
+
+	return nil
+}
+
+// CheckAwardFinal fails while the tender's award is still provisional.
+func (s *ProtestService) CheckAwardFinal(tenderID int) error {
+	tender, err := s.tenderRepo.GetTenderByID(tenderID)
+	if err != nil {
+		return errors.New("tender not found")
+	}
+	if tender.Status != model.TenderStatusAwarded || tender.AwardFinalizedAt == nil {
+		return errors.New("award is not final yet")
+	}
+	return nil
+}
+
+// FileProtest lets a losing bidder challenge a provisional award while the
+// standstill period runs.
+func (s *ProtestService) FileProtest(contractorID, tenderID int, reasons string) (*model.Protest, int, error) {
+	tender, err := s.tenderRepo.GetTenderByID(tenderID)
+	if err != nil {
+		return nil, http.StatusNotFound, errors.New("tender not found")
+	}
+
+	bids, err := s.bidRepo.GetBidsByTenderID(tenderID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	bidder, winner := false, false
+	for _, bid := range bids {
+		if bid.ContractorID != contractorID || bid.Type != model.BidTypeBid {
+			continue
+		}
+		bidder = true
+		winner = winner || bid.Status == model.BidStatusAwarded
+	}
+	if !bidder {
+		return nil, http.StatusNotFound, errors.New("tender not found")
+	}
+	if winner {
+		return nil, http.StatusBadRequest, errors.New("only losing bidders can protest an award")
+	}
+
+	if tender.Status != model.TenderStatusAwarded || tender.AwardFinalizedAt != nil ||
+		tender.StandstillEndsAt == nil || !time.Now().Before(*tender.StandstillEndsAt) {
+		return nil, http.StatusBadRequest, errors.New("the standstill period for this award is not running")
+	}
+
+	reasons = strings.TrimSpace(reasons)
+	if reasons == "" {
+		return nil, http.StatusBadRequest, errors.New("reasons are required")
+	}
+
+	pending, err := s.repo.HasPendingProtest(tenderID, contractorID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if pending {
+		return nil, http.StatusBadRequest, errors.New("you already have a pending protest against this award")
+	}
+
+	protest, err := s.repo.CreateProtest(&model.Protest{TenderID: tenderID, ContractorID: contractorID, Reasons: reasons})
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	message := "A protest has been filed against the award of tender: " + tender.Title
+	utils.SendNotification(*s.bidRepo, tender.ClientID, message, strconv.Itoa(protest.ID), "award_protest")
+
+	return protest, http.StatusCreated, nil
+}
+
+// AddDocument attaches supporting evidence to the contractor's pending
+// protest.
+func (s *ProtestService) AddDocument(ctx context.Context, contractorID, protestID int, fileName string, r io.Reader) (*model.ProtestDocument, int, error) {
+	protest, err := s.repo.GetProtestByID(protestID)
+	if err != nil || protest.ContractorID != contractorID {
+		return nil, http.StatusNotFound, errors.New("protest not found")
+	}
+	if protest.Status != model.ProtestStatusPending {
+		return nil, http.StatusBadRequest, errors.New("protest already resolved")
+	}
+
+	doc := &model.ProtestDocument{ProtestID: protestID, FileName: filepath.Base(fileName)}
+	if doc.FileName == "." || doc.FileName == string(filepath.Separator) {
+		return nil, http.StatusBadRequest, errors.New("invalid file name")
+	}
+
+	key, err := newStorageKey(fmt.Sprintf("protests/%d", protestID))
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	blob, status, err := saveBlob(ctx, s.storage, key, r, s.maxFileSize, s.allowedTypes)
+	if err != nil {
+		return nil, status, err
+	}
+
+	doc.StorageKey = key
+	doc.MimeType = blob.MimeType
+	doc.Size = blob.Size
+	doc.SHA256 = blob.SHA256
+
+	created, err := s.repo.AddDocument(doc)
+	if err != nil {
+		_ = s.storage.Delete(ctx, key)
+		return nil, http.StatusInternalServerError, err
+	}
+
+	return created, http.StatusCreated, nil
+}
+
+// OpenDocument returns a protest document to the protester or the tender
+// owner. The caller must close the returned reader.
+func (s *ProtestService) OpenDocument(ctx context.Context, userID, documentID int) (*model.ProtestDocument, io.ReadCloser, error) {
+	doc, err := s.repo.GetDocumentByID(documentID)
+	if err != nil {
+		return nil, nil, errors.New("document not found")
+	}
+	if _, _, err := s.getProtest(userID, doc.ProtestID); err != nil {
+		return nil, nil, errors.New("document not found")
+	}
+
+	file, err := s.storage.Open(ctx, doc.StorageKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open document: %w", err)
+	}
+
+	return doc, file, nil
+}
+
+func (s *ProtestService) ListProtests(clientID, tenderID int) ([]model.Protest, error) {
+	tender, err := s.tenderRepo.GetTenderByID(tenderID)
+	if err != nil || !managesTender(s.tenderRepo, tender, clientID) {
+		return nil, errors.New("tender not found")
+	}
+	return s.repo.GetProtestsByTenderID(tenderID)
+}
+
+func (s *ProtestService) ListOwnProtests(contractorID int) ([]model.Protest, error) {
+	return s.repo.GetProtestsByContractorID(contractorID)
+}
+
+// ResolveProtest records the client's decision. Upholding keeps the award and
+// lets it become final once the standstill ends and no other protest is
+// pending. Reversing withdraws the award and resolves every pending protest
+// against it, leaving the tender closed for a new award. It returns the
+// tender the protest was filed against.
+func (s *ProtestService) ResolveProtest(clientID, protestID int, payload model.ResolveProtest) (int, error) {
+	protest, tender, err := s.getProtest(clientID, protestID)
+	if err != nil || !managesTender(s.tenderRepo, tender, clientID) {
+		return 0, errors.New("protest not found")
+	}
+	if protest.Status != model.ProtestStatusPending {
+		return 0, errors.New("protest already resolved")
+	}
+
+	note := strings.TrimSpace(payload.Note)
+	if note == "" {
+		return 0, errors.New("a note explaining the decision is required")
+	}
+
+	switch payload.Decision {
+	case model.ProtestDecisionUphold:
+		if err := s.repo.UpholdAward(protestID, note); err != nil {
+			return 0, err
+		}
+		message := "Your protest was dismissed and the award of tender " + tender.Title + " stands: " + note
+		utils.SendNotification(*s.bidRepo, protest.ContractorID, message, strconv.Itoa(protestID), "protest_resolved")
+		s.finalize(tender.ID)
+	case model.ProtestDecisionReverse:
+		bids, err := s.bidRepo.GetBidsByTenderID(tender.ID)
+		if err != nil {
+			return 0, err
+		}
+		protests, err := s.repo.GetProtestsByTenderID(tender.ID)
+		if err != nil {
+			return 0, err
+		}
+		if err := s.repo.ReverseAward(tender.ID, note); err != nil {
+			return 0, err
+		}
+
+		for _, p := range protests {
+			if p.Status != model.ProtestStatusPending {
+				continue
+			}
+			message := "Your protest was upheld and the award of tender " + tender.Title + " has been reversed: " + note
+			utils.SendNotification(*s.bidRepo, p.ContractorID, message, strconv.Itoa(p.ID), "protest_resolved")
+		}
+		for _, bid := range bids {
+			if bid.Status != model.BidStatusAwarded {
+				continue
+			}
+			message := "The award of tender " + tender.Title + " has been reversed following a protest"
+			utils.SendNotification(*s.bidRepo, bid.ContractorID, message, bid.ID, "award_reversed")
+		}
+	default:
+		return 0, fmt.Errorf("unknown decision %q", payload.Decision)
+	}
+
+	return tender.ID, nil
+}
+
+// RunFinalizeScheduler makes awards final once their standstill period has
+// passed without pending protests. It blocks until ctx is cancelled.
+func (s *ProtestService) RunFinalizeScheduler(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			tenders, err := s.tenderRepo.FinalizeDueAwards()
+			if err != nil {
+				log.Println("Error finalizing awards:", err)
+				continue
+			}
+			for _, tender := range tenders {
+				s.notifyFinal(tender)
+			}
+		}
+	}
+}
+
+// finalize makes the award final if nothing holds it back any more.
+func (s *ProtestService) finalize(tenderID int) {
+	finalized, err := s.tenderRepo.FinalizeAward(tenderID)
+	if err != nil {
+		log.Println("Error finalizing award:", err)
+		return
+	}
+	if !finalized {
+		return
+	}
+
+	tender, err := s.tenderRepo.GetTenderByID(tenderID)
+	if err != nil {
+		log.Println("Error fetching tender for notification:", err)
+		return
+	}
+	s.notifyFinal(*tender)
+}
+
+func (s *ProtestService) notifyFinal(tender model.Tender) {
+	message := "The award of tender " + tender.Title + " is now final"
+	utils.SendNotification(*s.bidRepo, tender.ClientID, message, strconv.Itoa(tender.ID), "award_final")
+
+	bids, err := s.bidRepo.GetBidsByTenderID(tender.ID)
+	if err != nil {
+		log.Println("Error fetching bids for notification:", err)
+		return
+	}
+	for _, bid := range bids {
+		if bid.Status == model.BidStatusAwarded {
+			utils.SendNotification(*s.bidRepo, bid.ContractorID, message, bid.ID, "award_final")
+		}
+	}
+}
+
+// getProtest loads a protest for its author or the owner of the tender.
+func (s *ProtestService) getProtest(userID, protestID int) (*model.Protest, *model.Tender, error) {
+	protest, err := s.repo.GetProtestByID(protestID)
+	if err != nil {
+		return nil, nil, errors.New("protest not found")
+	}
+	tender, err := s.tenderRepo.GetTenderByID(protest.TenderID)
+	if err != nil {
+		return nil, nil, errors.New("protest not found")
+	}
+	if protest.ContractorID != userID && !managesTender(s.tenderRepo, tender, userID) {
+		return nil, nil, errors.New("protest not found")
+	}
+	return protest, tender, nil
+}
@@ -0,0 +1,300 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	repository "tender-managment/internal/db/repo"
+	"tender-managment/internal/model"
+	"tender-managment/internal/storage"
+	"tender-managment/internal/utils"
+	"time"
+)
+
+type QualificationService struct {
+	repo         *repository.QualificationRepository
+	tenderRepo   *repository.TenderRepository
+	bidRepo      *repository.BidRepository
+	rateService  *ExchangeRateService
+	storage      storage.Storage
+	maxFileSize  int64
+	allowedTypes []string
+}
+
+func NewQualificationService(repo *repository.QualificationRepository, tenderRepo *repository.TenderRepository, bidRepo *repository.BidRepository, rateService *ExchangeRateService, store storage.Storage, maxFileSize int64, allowedTypes []string) *QualificationService {
+	return &QualificationService{
+		repo:         repo,
+		tenderRepo:   tenderRepo,
+		bidRepo:      bidRepo,
+		rateService:  rateService,
+		storage:      store,
+		maxFileSize:  maxFileSize,
+		allowedTypes: allowedTypes,
+	}
+}
+
+// IneligibleError is returned when a contractor lacks qualifications a tender
+// requires. Missing lists every unmet requirement.
+type IneligibleError struct {
+	Missing []string
+}
+
+func (e *IneligibleError) Error() string {
+	return "contractor does not meet the tender requirements"
+}
+
+// SubmitQualification stores the supporting document and queues the
+// qualification for review.
+func (s *QualificationService) SubmitQualification(ctx context.Context, q *model.Qualification, fileName string, r io.Reader) (*model.Qualification, int, error) {
+	q.Code = strings.TrimSpace(q.Code)
+	switch q.Type {
+	case model.QualificationTypeLicense, model.QualificationTypeCertification:
+		if q.Code == "" {
+			return nil, http.StatusBadRequest, fmt.Errorf("%s needs a code", q.Type)
+		}
+		q.Turnover = nil
+	case model.QualificationTypeTurnover:
+		if q.Turnover == nil || q.Turnover.Amount <= 0 {
+			return nil, http.StatusBadRequest, errors.New("turnover needs a positive amount")
+		}
+		if err := normalizeBudget(q.Turnover); err != nil {
+			return nil, http.StatusBadRequest, err
+		}
+		q.Code = model.TurnoverCode
+	default:
+		return nil, http.StatusBadRequest, fmt.Errorf("unknown qualification type %q", q.Type)
+	}
+	if q.ExpiresAt != nil && !q.ExpiresAt.After(time.Now()) {
+		return nil, http.StatusBadRequest, errors.New("document has already expired")
+	}
+
+	q.FileName = filepath.Base(fileName)
+	if q.FileName == "." || q.FileName == string(filepath.Separator) {
+		return nil, http.StatusBadRequest, errors.New("invalid file name")
+	}
+
+	key, err := newStorageKey(fmt.Sprintf("qualifications/%d", q.ContractorID))
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	blob, status, err := saveBlob(ctx, s.storage, key, r, s.maxFileSize, s.allowedTypes)
+	if err != nil {
+		return nil, status, err
+	}
+
+	q.StorageKey = key
+	q.MimeType = blob.MimeType
+	q.Size = blob.Size
+	q.SHA256 = blob.SHA256
+
+	created, err := s.repo.CreateQualification(q)
+	if err != nil {
+		_ = s.storage.Delete(ctx, key)
+		return nil, http.StatusInternalServerError, err
+	}
+
+	return created, http.StatusCreated, nil
+}
+
+func (s *QualificationService) ListQualifications(contractorID int) ([]model.Qualification, error) {
+	return s.repo.GetQualificationsByContractorID(contractorID)
+}
+
+func (s *QualificationService) DeleteQualification(ctx context.Context, contractorID, id int) error {
+	q, err := s.repo.GetQualificationByID(id)
+	if err != nil || q.ContractorID != contractorID {
+		return errors.New("qualification not found")
+	}
+
+	if err := s.repo.DeleteQualification(id); err != nil {
+		return err
+	}
+	_ = s.storage.Delete(ctx, q.StorageKey)
+
+	return nil
+}
+
+// ReviewQueue lists pending qualifications that one of the client's tenders
+// asks for.
+func (s *QualificationService) ReviewQueue(clientID int) ([]model.Qualification, error) {
+	return s.repo.GetReviewQueue(clientID)
+}
+
+// ReviewQualification approves or rejects a pending qualification and tells
+// the contractor about the decision. Rejections need a reason.
+func (s *QualificationService) ReviewQualification(clientID, id int, approve bool, note string) error {
+	if err := s.checkReviewer(clientID, id); err != nil {
+		return err
+	}
+
+	status := model.QualificationStatusApproved
+	if !approve {
+		if strings.TrimSpace(note) == "" {
+			return errors.New("a reason is required to reject a qualification")
+		}
+		status = model.QualificationStatusRejected
+	}
+
+	if err := s.repo.ReviewQualification(id, clientID, status, note); err != nil {
+		return err
+	}
+
+	q, err := s.repo.GetQualificationByID(id)
+	if err == nil {
+		message := fmt.Sprintf("Your %s %s was %s", q.Type, q.Code, status)
+		utils.SendNotification(*s.bidRepo, q.ContractorID, message, strconv.Itoa(id), "qualification_review")
+	}
+
+	return nil
+}
+
+// OpenDocument returns the qualification's document to its owner or to a
+// client who may review it. The caller must close the returned reader.
+func (s *QualificationService) OpenDocument(ctx context.Context, userID int, role string, id int) (*model.Qualification, io.ReadCloser, error) {
+	q, err := s.repo.GetQualificationByID(id)
+	if err != nil {
+		return nil, nil, errors.New("qualification not found")
+	}
+	if q.ContractorID != userID {
+		if role != "client" {
+			return nil, nil, errors.New("qualification not found")
+		}
+		if err := s.checkReviewer(userID, id); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	file, err := s.storage.Open(ctx, q.StorageKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open document: %w", err)
+	}
+
+	return q, file, nil
+}
+
+// TenderEligibility reports whether the contractor may bid on the tender.
+func (s *QualificationService) TenderEligibility(contractorID, tenderID int) (*model.Eligibility, error) {
+	tender, err := s.tenderRepo.GetTenderByID(tenderID)
+	if err != nil {
+		return nil, errors.New("tender not found")
+	}
+	if err := checkTenderVisible(s.tenderRepo, tender, contractorID); err != nil {
+		return nil, err
+	}
+
+	missing, err := s.missingRequirements(contractorID, tender)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.Eligibility{Eligible: len(missing) == 0, Missing: missing}, nil
+}
+
+// CheckEligibility returns an *IneligibleError when the contractor does not
+// hold every qualification the tender requires.
+func (s *QualificationService) CheckEligibility(contractorID int, tender *model.Tender) error {
+	missing, err := s.missingRequirements(contractorID, tender)
+	if err != nil {
+		return err
+	}
+	if len(missing) > 0 {
+		return &IneligibleError{Missing: missing}
+	}
+	return nil
+}
+
+// missingRequirements describes each requirement the contractor does not
+// meet with an approved, unexpired qualification.
+func (s *QualificationService) missingRequirements(contractorID int, tender *model.Tender) ([]string, error) {
+	requirements, err := s.repo.GetRequirements(tender.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(requirements) == 0 {
+		return []string{}, nil
+	}
+
+	qualifications, err := s.repo.GetQualificationsByContractorID(contractorID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	missing := []string{}
+	for _, requirement := range requirements {
+		label := requirement.Type + " " + requirement.Code
+		if requirement.Type == model.QualificationTypeTurnover {
+			label = fmt.Sprintf("annual turnover of at least %s %s", requirement.MinAmount, tender.Budget.Currency)
+		}
+
+		reason := "not submitted"
+		satisfied := false
+		for _, q := range qualifications {
+			if q.Type != requirement.Type || q.Code != requirement.Code {
+				continue
+			}
+
+			switch {
+			case q.Status == model.QualificationStatusRejected:
+				reason = pickReason(reason, "rejected")
+			case q.Status == model.QualificationStatusPending:
+				reason = pickReason(reason, "awaiting review")
+			case q.ExpiresAt != nil && !q.ExpiresAt.After(now):
+				reason = pickReason(reason, "expired on "+q.ExpiresAt.Format("2006-01-02"))
+			case requirement.Type == model.QualificationTypeTurnover:
+				rate, err := s.rateService.Rate(q.Turnover.Currency, tender.Budget.Currency)
+				if err != nil {
+					if !strings.HasPrefix(err.Error(), "no exchange rate") && err.Error() != "invalid currency" {
+						return nil, err
+					}
+					reason = pickReason(reason, err.Error())
+					continue
+				}
+				if q.Turnover.Amount.Convert(rate) < *requirement.MinAmount {
+					reason = pickReason(reason, "declared turnover is too low")
+					continue
+				}
+				satisfied = true
+			default:
+				satisfied = true
+			}
+
+			if satisfied {
+				break
+			}
+		}
+
+		if !satisfied {
+			missing = append(missing, label+": "+reason)
+		}
+	}
+
+	return missing, nil
+}
+
+// pickReason keeps the most useful explanation when a contractor has several
+// documents for the same requirement: anything beats "not submitted" and a
+// document still in review beats an older rejected or expired one.
+func pickReason(current, candidate string) string {
+	if current == "not submitted" || candidate == "awaiting review" {
+		return candidate
+	}
+	return current
+}
+
+func (s *QualificationService) checkReviewer(clientID, id int) error {
+	reviewable, err := s.repo.IsReviewableBy(id, clientID)
+	if err != nil {
+		return err
+	}
+	if !reviewable {
+		return errors.New("qualification not found")
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	repository "tender-managment/internal/db/repo"
+	"tender-managment/internal/model"
+	"tender-managment/internal/utils"
+	"time"
+)
+
+// reviewEditWindow is how long after submitting a review its author may still
+// change it.
+const reviewEditWindow = 14 * 24 * time.Hour
+
+type ReviewService struct {
+	repo         *repository.ReviewRepository
+	contractRepo *repository.ContractRepository
+	bidRepo      *repository.BidRepository
+}
+
+func NewReviewService(repo *repository.ReviewRepository, contractRepo *repository.ContractRepository, bidRepo *repository.BidRepository) *ReviewService {
+	return &ReviewService{
+		repo:         repo,
+		contractRepo: contractRepo,
+		bidRepo:      bidRepo,
+	}
+}
+
+// SubmitReview lets a party of a completed contract rate the other party.
+func (s *ReviewService) SubmitReview(userID, contractID int, payload model.SubmitReview) (*model.Review, error) {
+	contract, err := s.contractRepo.GetContractByID(contractID)
+	if err != nil || (contract.ClientID != userID && contract.ContractorID != userID) {
+		return nil, errors.New("contract not found")
+	}
+	if contract.Status != model.ContractStatusCompleted {
+		return nil, errors.New("only completed contracts can be reviewed")
+	}
+	if err := validateScores(payload); err != nil {
+		return nil, err
+	}
+
+	review, err := s.repo.CreateReview(&model.Review{
+		ContractID:    contractID,
+		ReviewerID:    userID,
+		RevieweeID:    counterparty(contract, userID),
+		Quality:       payload.Quality,
+		Timeliness:    payload.Timeliness,
+		Communication: payload.Communication,
+		Comment:       strings.TrimSpace(payload.Comment),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	message := fmt.Sprintf("You have received a review for contract #%d", contractID)
+	utils.SendNotification(*s.bidRepo, review.RevieweeID, message, strconv.Itoa(review.ID), "review_received")
+
+	return review, nil
+}
+
+// UpdateReview lets the author change a review within reviewEditWindow of
+// submitting it.
+func (s *ReviewService) UpdateReview(userID, reviewID int, payload model.SubmitReview) (*model.Review, error) {
+	review, err := s.repo.GetReviewByID(reviewID)
+	if err != nil || review.ReviewerID != userID {
+		return nil, errors.New("review not found")
+	}
+	if time.Since(review.CreatedAt) > reviewEditWindow {
+		return nil, errors.New("the review can no longer be edited")
+	}
+	if err := validateScores(payload); err != nil {
+		return nil, err
+	}
+
+	review.Quality = payload.Quality
+	review.Timeliness = payload.Timeliness
+	review.Communication = payload.Communication
+	review.Comment = strings.TrimSpace(payload.Comment)
+	return s.repo.UpdateReview(review)
+}
+
+func (s *ReviewService) ListReviews(userID int) ([]model.Review, error) {
+	return s.repo.GetReviewsByRevieweeID(userID)
+}
+
+func (s *ReviewService) GetReputation(userID int) (*model.Reputation, error) {
+	return s.repo.GetReputation(userID)
+}
+
+// AttachReputation fills in the reputation of each bid's contractor.
+func (s *ReviewService) AttachReputation(bids []model.Bid) {
+	reputations := make(map[int]*model.Reputation)
+	for i := range bids {
+		contractorID := bids[i].ContractorID
+		rep, ok := reputations[contractorID]
+		if !ok {
+			var err error
+			rep, err = s.repo.GetReputation(contractorID)
+			if err != nil {
+				log.Println("Error fetching contractor reputation:", err)
+			}
+			reputations[contractorID] = rep
+		}
+		bids[i].ContractorReputation = rep
+	}
+}
+
+func validateScores(payload model.SubmitReview) error {
+	for name, score := range map[string]int{
+		"quality":       payload.Quality,
+		"timeliness":    payload.Timeliness,
+		"communication": payload.Communication,
+	} {
+		if score < 1 || score > 5 {
+			return fmt.Errorf("%s must be between 1 and 5", name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	repository "tender-managment/internal/db/repo"
+	"tender-managment/internal/model"
+	"tender-managment/internal/storage"
+)
+
+type TemplateService struct {
+	repo           *repository.TemplateRepository
+	tenderRepo     *repository.TenderRepository
+	attachmentRepo *repository.AttachmentRepository
+	userRepo       *repository.UserRepository
+	storage        storage.Storage
+}
+
+func NewTemplateService(repo *repository.TemplateRepository, tenderRepo *repository.TenderRepository, attachmentRepo *repository.AttachmentRepository, userRepo *repository.UserRepository, store storage.Storage) *TemplateService {
+	return &TemplateService{
+		repo:           repo,
+		tenderRepo:     tenderRepo,
+		attachmentRepo: attachmentRepo,
+		userRepo:       userRepo,
+		storage:        store,
+	}
+}
+
+func (s *TemplateService) SaveAsTemplate(clientID, tenderID int, name string) (*model.TenderTemplate, error) {
+	tender, err := s.tenderRepo.GetTenderByID(tenderID)
+	if err != nil || !managesTender(s.tenderRepo, tender, clientID) {
+		return nil, errors.New("tender not found")
+	}
+
+	return s.repo.CreateTemplateFromTender(tenderID, name)
+}
+
+func (s *TemplateService) ListTemplates(clientID int) ([]model.TenderTemplate, error) {
+	return s.repo.GetTemplatesByClientID(clientID)
+}
+
+func (s *TemplateService) DeleteTemplate(ctx context.Context, clientID, templateID int) error {
+	template, err := s.repo.GetTemplateByID(templateID)
+	if err != nil || template.ClientID != clientID {
+		return errors.New("template not found")
+	}
+
+	keys, err := s.repo.DeleteTemplate(templateID)
+	if err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		count, err := s.attachmentRepo.CountByStorageKey(key)
+		if err == nil && count == 0 {
+			_ = s.storage.Delete(ctx, key)
+		}
+	}
+
+	return nil
+}
+
+// CreateTenderFromTemplate starts a new draft from a template. Title, budget
+// and publish time in overrides replace the template values when set.
+func (s *TemplateService) CreateTenderFromTemplate(clientID, templateID int, overrides model.Tender) (*model.Tender, error) {
+	template, err := s.repo.GetTemplateByID(templateID)
+	if err != nil || template.ClientID != clientID {
+		return nil, errors.New("template not found")
+	}
+	owner, err := s.userRepo.GetUserByID(clientID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	tender := model.Tender{
+		ClientID:           clientID,
+		OrgID:              owner.OrgID,
+		Title:              template.Title,
+		Description:        template.Description,
+		EvaluationCriteria: template.EvaluationCriteria,
+		Budget:             template.Budget,
+	}
+	if err := prepareDraft(&tender, overrides); err != nil {
+		return nil, err
+	}
+
+	return s.tenderRepo.CreateTenderFromTemplate(templateID, &tender)
+}
+
+// CloneTender copies an existing tender, including its lots, bill of
+// quantities, invitations, requirements and tender-level attachments, into a
+// new draft owned by the caller and the source tender's organization.
+func (s *TemplateService) CloneTender(clientID, tenderID int, overrides model.Tender) (*model.Tender, error) {
+	source, err := s.tenderRepo.GetTenderByID(tenderID)
+	if err != nil || !managesTender(s.tenderRepo, source, clientID) {
+		return nil, errors.New("tender not found")
+	}
+
+	tender := model.Tender{
+		ClientID:           clientID,
+		OrgID:              source.OrgID,
+		Title:              source.Title,
+		Description:        source.Description,
+		EvaluationCriteria: source.EvaluationCriteria,
+		Budget:             source.Budget,
+		Visibility:         source.Visibility,
+		Stage:              model.TenderStageRFP,
+	}
+	if source.Stage == model.TenderStageEOI || source.EOIDeadline != nil {
+		tender.Stage = model.TenderStageEOI
+	}
+	if err := prepareDraft(&tender, overrides); err != nil {
+		return nil, err
+	}
+
+	return s.tenderRepo.CloneTender(tenderID, &tender)
+}
+
+func prepareDraft(tender *model.Tender, overrides model.Tender) error {
+	if overrides.Title != "" {
+		tender.Title = overrides.Title
+	}
+	if overrides.Budget.Amount > 0 {
+		tender.Budget.Amount = overrides.Budget.Amount
+	}
+	tender.Deadline = overrides.Deadline
+	tender.PublishAt = overrides.PublishAt
+	tender.Status = model.TenderStatusDraft
+	if tender.Visibility == "" {
+		tender.Visibility = model.TenderVisibilityPublic
+	}
+	if tender.Stage == "" {
+		tender.Stage = model.TenderStageRFP
+	}
+
+	if tender.PublishAt != nil && !tender.PublishAt.Before(tender.Deadline) {
+		return errors.New("publish time must be before the deadline")
+	}
+
+	return nil
+}
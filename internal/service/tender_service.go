@@ -1,33 +1,133 @@
 package service
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
 	repository "tender-managment/internal/db/repo"
 	"tender-managment/internal/model"
+	"tender-managment/internal/utils"
+	"time"
 )
 
 type TenderService struct {
-	repo *repository.TenderRepository
+	repo              *repository.TenderRepository
+	bidRepo           *repository.BidRepository
+	userRepo          *repository.UserRepository
+	qualificationRepo *repository.QualificationRepository
 }
 
-func NewTenderService(repo *repository.TenderRepository) *TenderService {
-	return &TenderService{repo: repo}
+func NewTenderService(repo *repository.TenderRepository, bidRepo *repository.BidRepository, userRepo *repository.UserRepository, qualificationRepo *repository.QualificationRepository) *TenderService {
+	return &TenderService{repo: repo, bidRepo: bidRepo, userRepo: userRepo, qualificationRepo: qualificationRepo}
 }
 
-func (s *TenderService) CreateTender(tender *model.Tender) (*model.Tender, error) {
-	return s.repo.CreateTender(tender)
+// CreateTender creates a tender. Contractors listed in invitedIDs are invited
+// right away; they are only accepted on invite-only tenders.
+func (s *TenderService) CreateTender(tender *model.Tender, invitedIDs []int) (*model.Tender, error) {
+	if err := normalizeBudget(&tender.Budget); err != nil {
+		return nil, err
+	}
+	if err := normalizeVisibility(&tender.Visibility); err != nil {
+		return nil, err
+	}
+	if tender.Stage == "" {
+		tender.Stage = model.TenderStageRFP
+	}
+	if tender.PublishAt != nil && !tender.PublishAt.Before(tender.Deadline) {
+		return nil, errors.New("publish time must be before the deadline")
+	}
+	if len(invitedIDs) > 0 && tender.Visibility != model.TenderVisibilityInviteOnly {
+		return nil, errors.New("only invite-only tenders accept invitations")
+	}
+	if err := s.checkContractors(invitedIDs); err != nil {
+		return nil, err
+	}
+
+	owner, err := s.userRepo.GetUserByID(tender.ClientID)
+	if err != nil {
+		return nil, err
+	}
+	tender.OrgID = owner.OrgID
+
+	created, err := s.repo.CreateTender(tender)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.repo.AddInvitations(created.ID, invitedIDs); err != nil {
+		return nil, err
+	}
+
+	if created.Status == model.TenderStatusOpen {
+		s.notifyNewTender(*created)
+	}
+
+	return created, nil
 }
 
 func (s *TenderService) GetTendersByClient(clientID int) ([]model.GetTender, error) {
 	return s.repo.GetTendersByClientID(clientID)
 }
 
-func (s *TenderService) ListTenders(clientID int) ([]model.Tender, error) {
-	return s.repo.ListTendersByClientID(clientID)
+// ListTenders returns the client's tenders. Drafts and invite-only tenders are
+// only included when the client is looking at their own list.
+func (s *TenderService) ListTenders(viewerID, clientID int) ([]model.Tender, error) {
+	return s.repo.ListTendersByClientID(clientID, viewerID == clientID)
+}
+
+func (s *TenderService) UpdateDraftTender(clientID int, tender *model.Tender) error {
+	existing, err := s.repo.GetTenderByID(tender.ID)
+	if err != nil || !managesTender(s.repo, existing, clientID) {
+		return errors.New("tender not found")
+	}
+
+	if existing.Status != model.TenderStatusDraft {
+		return errors.New("only draft tenders can be edited")
+	}
+
+	if err := normalizeBudget(&tender.Budget); err != nil {
+		return err
+	}
+	if err := normalizeVisibility(&tender.Visibility); err != nil {
+		return err
+	}
+
+	if tender.PublishAt != nil && !tender.PublishAt.Before(tender.Deadline) {
+		return errors.New("publish time must be before the deadline")
+	}
+
+	return s.repo.UpdateDraftTender(tender)
+}
+
+func (s *TenderService) PublishTender(clientID, tenderID int) error {
+	tender, err := s.repo.GetTenderByID(tenderID)
+	if err != nil || !managesTender(s.repo, tender, clientID) {
+		return errors.New("tender not found")
+	}
+
+	if tender.Status != model.TenderStatusDraft {
+		return errors.New("only draft tenders can be published")
+	}
+
+	published, err := s.repo.PublishTender(tenderID)
+	if err != nil {
+		return err
+	}
+	if published {
+		s.notifyNewTender(*tender)
+	}
+
+	return nil
 }
 
+// UpdateTenderStatus moves a tender between open and closed. Awarding goes
+// through the award endpoints, and an award can only be reversed by
+// resolving a protest, so neither direction is allowed here.
 func (s *TenderService) UpdateTenderStatus(clientID int, tenderID int, status string) error {
-	if status != "open" && status != "closed" && status != "awarded" {
+	if status != model.TenderStatusOpen && status != model.TenderStatusClosed {
 		return errors.New("invalid status")
 	}
 
@@ -36,10 +136,20 @@ func (s *TenderService) UpdateTenderStatus(clientID int, tenderID int, status st
 		return errors.New("tender not found")
 	}
 
-	if tender.ClientID != clientID {
+	if !managesTender(s.repo, tender, clientID) {
 		return errors.New("tender not found")
 	}
 
+	if tender.Status == model.TenderStatusDraft {
+		if status != model.TenderStatusOpen {
+			return errors.New("only draft tenders can be published")
+		}
+		return s.PublishTender(clientID, tenderID)
+	}
+	if tender.Status == model.TenderStatusAwarded {
+		return errors.New("awarded tenders cannot change status")
+	}
+
 	return s.repo.UpdateTenderStatus(tenderID, status)
 }
 
@@ -49,9 +159,401 @@ func (s *TenderService) DeleteTender(clientID, tenderID int) error {
 		return errors.New("tender not found")
 	}
 
-	if tender.ClientID != clientID {
+	if !managesTender(s.repo, tender, clientID) {
 		return errors.New("tender not found")
 	}
 
 	return s.repo.DeleteTender(tenderID)
 }
+
+func (s *TenderService) AddLot(clientID int, lot *model.Lot) (*model.Lot, error) {
+	if err := s.checkStructureEditable(clientID, lot.TenderID); err != nil {
+		return nil, err
+	}
+	if lot.Budget <= 0 || lot.LotNumber <= 0 {
+		return nil, errors.New("invalid lot data")
+	}
+
+	return s.repo.CreateLot(lot)
+}
+
+func (s *TenderService) DeleteLot(clientID, tenderID, lotID int) error {
+	if err := s.checkStructureEditable(clientID, tenderID); err != nil {
+		return err
+	}
+
+	return s.repo.DeleteLot(tenderID, lotID)
+}
+
+// ListLots returns the lots of a tender. Contractors can only see lots of
+// published tenders they are allowed to bid on.
+func (s *TenderService) ListLots(userID int, tenderID int) ([]model.Lot, error) {
+	if err := s.checkVisible(userID, tenderID); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetLotsByTenderID(tenderID)
+}
+
+// SetBoQ replaces the bill of quantities of a tender. On tenders split into
+// lots every line has to belong to one of the lots.
+func (s *TenderService) SetBoQ(clientID, tenderID int, items []model.BoQItem) ([]model.BoQItem, error) {
+	if err := s.checkStructureEditable(clientID, tenderID); err != nil {
+		return nil, err
+	}
+
+	lots, err := s.repo.GetLotsByTenderID(tenderID)
+	if err != nil {
+		return nil, err
+	}
+	lotIDs := make(map[int]bool, len(lots))
+	for _, lot := range lots {
+		lotIDs[lot.ID] = true
+	}
+
+	numbers := make(map[int]bool, len(items))
+	for _, item := range items {
+		if item.ItemNumber <= 0 || item.Quantity <= 0 || item.Description == "" || item.Unit == "" {
+			return nil, errors.New("invalid bill of quantities item")
+		}
+		if numbers[item.ItemNumber] {
+			return nil, fmt.Errorf("item number %d is used more than once", item.ItemNumber)
+		}
+		numbers[item.ItemNumber] = true
+
+		if item.LotID == nil {
+			if len(lots) > 0 {
+				return nil, fmt.Errorf("item %d must belong to a lot", item.ItemNumber)
+			}
+		} else if !lotIDs[*item.LotID] {
+			return nil, fmt.Errorf("item %d references an unknown lot", item.ItemNumber)
+		}
+	}
+
+	return s.repo.ReplaceBoQItems(tenderID, items)
+}
+
+// GetBoQ returns the bill of quantities with the same visibility as lots.
+func (s *TenderService) GetBoQ(userID, tenderID int) ([]model.BoQItem, error) {
+	if err := s.checkVisible(userID, tenderID); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetBoQItems(tenderID)
+}
+
+// Shortlist marks expressions of interest as shortlisted. Only shortlisted
+// contractors may bid once the RFP stage opens.
+func (s *TenderService) Shortlist(clientID, tenderID int, bidIDs []int) error {
+	tender, err := s.repo.GetTenderByID(tenderID)
+	if err != nil || !managesTender(s.repo, tender, clientID) {
+		return errors.New("tender not found")
+	}
+	if tender.Stage != model.TenderStageEOI {
+		return errors.New("tender is not collecting expressions of interest")
+	}
+	if len(bidIDs) == 0 {
+		return errors.New("no expressions of interest to shortlist")
+	}
+
+	for _, bidID := range bidIDs {
+		if err := s.bidRepo.ShortlistBid(tenderID, bidID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// OpenRFP starts the second stage of a two-stage tender with its own
+// deadline. Shortlisted contractors are invited to bid and the remaining
+// expressions of interest are rejected.
+func (s *TenderService) OpenRFP(clientID, tenderID int, deadline time.Time) error {
+	tender, err := s.repo.GetTenderByID(tenderID)
+	if err != nil || !managesTender(s.repo, tender, clientID) {
+		return errors.New("tender not found")
+	}
+	if tender.Stage != model.TenderStageEOI {
+		return errors.New("tender is not collecting expressions of interest")
+	}
+	if !deadline.After(time.Now()) {
+		return errors.New("deadline must be in the future")
+	}
+
+	responses, err := s.bidRepo.GetBidsByTenderID(tenderID)
+	if err != nil {
+		return err
+	}
+	var shortlisted, rejected []int
+	for _, bid := range responses {
+		switch bid.Status {
+		case model.BidStatusShortlisted:
+			shortlisted = append(shortlisted, bid.ContractorID)
+		case model.BidStatusPending:
+			rejected = append(rejected, bid.ContractorID)
+		}
+	}
+	if len(shortlisted) == 0 {
+		return errors.New("shortlist at least one contractor first")
+	}
+
+	if err := s.repo.OpenRFPStage(tenderID, deadline); err != nil {
+		return err
+	}
+	if err := s.bidRepo.RejectPendingEOIs(tenderID); err != nil {
+		return err
+	}
+
+	relationID := strconv.Itoa(tenderID)
+	for _, contractorID := range shortlisted {
+		message := "You have been shortlisted and can now bid on tender: " + tender.Title
+		utils.SendNotification(*s.bidRepo, contractorID, message, relationID, "tender_rfp")
+	}
+	for _, contractorID := range rejected {
+		message := "Your expression of interest was not shortlisted for tender: " + tender.Title
+		utils.SendNotification(*s.bidRepo, contractorID, message, relationID, "eoi_rejected")
+	}
+
+	return nil
+}
+
+// SetRequirements replaces the qualifications contractors need to bid. Like
+// lots, requirements are fixed once the first bid arrives.
+func (s *TenderService) SetRequirements(clientID, tenderID int, requirements []model.Requirement) ([]model.Requirement, error) {
+	if err := s.checkStructureEditable(clientID, tenderID); err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(requirements))
+	for i := range requirements {
+		requirement := &requirements[i]
+		requirement.Code = strings.TrimSpace(requirement.Code)
+
+		switch requirement.Type {
+		case model.QualificationTypeLicense, model.QualificationTypeCertification:
+			if requirement.Code == "" {
+				return nil, fmt.Errorf("%s requirement needs a code", requirement.Type)
+			}
+			requirement.MinAmount = nil
+		case model.QualificationTypeTurnover:
+			if requirement.MinAmount == nil || *requirement.MinAmount <= 0 {
+				return nil, errors.New("turnover requirement needs a positive min_amount")
+			}
+			requirement.Code = model.TurnoverCode
+		default:
+			return nil, fmt.Errorf("unknown requirement type %q", requirement.Type)
+		}
+
+		key := requirement.Type + "/" + requirement.Code
+		if seen[key] {
+			return nil, fmt.Errorf("%s requirement %s is listed more than once", requirement.Type, requirement.Code)
+		}
+		seen[key] = true
+	}
+
+	return s.qualificationRepo.ReplaceRequirements(tenderID, requirements)
+}
+
+// GetRequirements returns the tender's requirements with the same visibility
+// as lots.
+func (s *TenderService) GetRequirements(userID, tenderID int) ([]model.Requirement, error) {
+	if err := s.checkVisible(userID, tenderID); err != nil {
+		return nil, err
+	}
+
+	return s.qualificationRepo.GetRequirements(tenderID)
+}
+
+// ListMarketplace returns the open tenders a contractor can bid on: every
+// public tender and the invite-only ones they were invited to.
+func (s *TenderService) ListMarketplace(contractorID int) ([]model.Tender, error) {
+	return s.repo.ListOpenTendersForContractor(contractorID)
+}
+
+// InviteContractors adds contractors to an invite-only tender. Invitations can
+// be sent while the tender is a draft or open; on open tenders the new
+// invitees are notified immediately, otherwise on publication.
+func (s *TenderService) InviteContractors(clientID, tenderID int, contractorIDs []int) ([]model.TenderInvitation, error) {
+	tender, err := s.repo.GetTenderByID(tenderID)
+	if err != nil || !managesTender(s.repo, tender, clientID) {
+		return nil, errors.New("tender not found")
+	}
+	if tender.Visibility != model.TenderVisibilityInviteOnly {
+		return nil, errors.New("only invite-only tenders accept invitations")
+	}
+	if tender.Status != model.TenderStatusDraft && tender.Status != model.TenderStatusOpen {
+		return nil, errors.New("invitations can only be sent before the tender closes")
+	}
+	if len(contractorIDs) == 0 {
+		return nil, errors.New("no contractors to invite")
+	}
+	if err := s.checkContractors(contractorIDs); err != nil {
+		return nil, err
+	}
+
+	added, err := s.repo.AddInvitations(tenderID, contractorIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	if tender.Status == model.TenderStatusOpen {
+		s.notifyInvitees(*tender, added)
+	}
+
+	return s.repo.GetInvitations(tenderID)
+}
+
+func (s *TenderService) ListInvitations(clientID, tenderID int) ([]model.TenderInvitation, error) {
+	tender, err := s.repo.GetTenderByID(tenderID)
+	if err != nil || !managesTender(s.repo, tender, clientID) {
+		return nil, errors.New("tender not found")
+	}
+
+	return s.repo.GetInvitations(tenderID)
+}
+
+// checkVisible hides drafts from everyone but the owner and invite-only
+// tenders from contractors that were not invited.
+func (s *TenderService) checkVisible(userID, tenderID int) error {
+	tender, err := s.repo.GetTenderByID(tenderID)
+	if err != nil {
+		return errors.New("tender not found")
+	}
+
+	return checkTenderVisible(s.repo, tender, userID)
+}
+
+func checkTenderVisible(repo *repository.TenderRepository, tender *model.Tender, userID int) error {
+	if managesTender(repo, tender, userID) {
+		return nil
+	}
+	if tender.Status == model.TenderStatusDraft {
+		return errors.New("tender not found")
+	}
+	if tender.Visibility == model.TenderVisibilityInviteOnly {
+		invited, err := repo.IsInvited(tender.ID, userID)
+		if err != nil {
+			return err
+		}
+		if !invited {
+			return errors.New("tender not found")
+		}
+	}
+
+	return nil
+}
+
+func (s *TenderService) checkContractors(contractorIDs []int) error {
+	for _, id := range contractorIDs {
+		user, err := s.userRepo.GetUserByID(id)
+		if err != nil || user.Role != "contractor" {
+			return fmt.Errorf("user %d is not a contractor", id)
+		}
+	}
+	return nil
+}
+
+// checkStructureEditable only allows changing lots and the bill of quantities
+// before any bid references them.
+func (s *TenderService) checkStructureEditable(clientID, tenderID int) error {
+	tender, err := s.repo.GetTenderByID(tenderID)
+	if err != nil || !managesTender(s.repo, tender, clientID) {
+		return errors.New("tender not found")
+	}
+
+	switch tender.Status {
+	case model.TenderStatusDraft:
+		return nil
+	case model.TenderStatusOpen:
+		count, err := s.bidRepo.CountBidsByTenderID(tenderID)
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			return nil
+		}
+	}
+
+	return errors.New("tender structure can only be changed before bids are submitted")
+}
+
+// normalizeBudget defaults the currency and rejects codes that are not ISO
+// 4217 shaped.
+func normalizeBudget(budget *model.Money) error {
+	if budget.Currency == "" {
+		budget.Currency = model.DefaultCurrency
+	}
+	if !model.ValidCurrency(budget.Currency) {
+		return errors.New("invalid currency")
+	}
+	return nil
+}
+
+func normalizeVisibility(visibility *string) error {
+	if *visibility == "" {
+		*visibility = model.TenderVisibilityPublic
+	}
+	if *visibility != model.TenderVisibilityPublic && *visibility != model.TenderVisibilityInviteOnly {
+		return errors.New("invalid visibility")
+	}
+	return nil
+}
+
+// RunPublishScheduler opens scheduled drafts once their publish time has
+// passed. It blocks until ctx is cancelled.
+func (s *TenderService) RunPublishScheduler(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			tenders, err := s.repo.PublishDueTenders()
+			if err != nil {
+				log.Println("Error publishing scheduled tenders:", err)
+				continue
+			}
+			for _, tender := range tenders {
+				s.notifyNewTender(tender)
+			}
+		}
+	}
+}
+
+// notifyNewTender tells contractors about a published tender. Invite-only
+// tenders are announced to their invitees only.
+func (s *TenderService) notifyNewTender(tender model.Tender) {
+	if tender.Visibility == model.TenderVisibilityInviteOnly {
+		invitations, err := s.repo.GetInvitations(tender.ID)
+		if err != nil {
+			log.Println("Error fetching invitations for notification:", err)
+			return
+		}
+		contractorIDs := make([]int, 0, len(invitations))
+		for _, invitation := range invitations {
+			contractorIDs = append(contractorIDs, invitation.ContractorID)
+		}
+		s.notifyInvitees(tender, contractorIDs)
+		return
+	}
+
+	contractorIDs, err := s.userRepo.GetUserIDsByRole("contractor")
+	if err != nil {
+		log.Println("Error fetching contractors for notification:", err)
+		return
+	}
+
+	message := "A new tender has been published: " + tender.Title
+	for _, contractorID := range contractorIDs {
+		utils.SendNotification(*s.bidRepo, contractorID, message, strconv.Itoa(tender.ID), "tender_publish")
+	}
+}
+
+func (s *TenderService) notifyInvitees(tender model.Tender, contractorIDs []int) {
+	message := "You have been invited to bid on tender: " + tender.Title
+	for _, contractorID := range contractorIDs {
+		utils.SendNotification(*s.bidRepo, contractorID, message, strconv.Itoa(tender.ID), "tender_invite")
+	}
+}
@@ -0,0 +1,160 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	repository "tender-managment/internal/db/repo"
+	"tender-managment/internal/model"
+	"tender-managment/internal/utils"
+	"time"
+)
+
+const recoveryCodeCount = 10
+
+type TwoFactorService struct {
+	repo     *repository.TwoFactorRepository
+	userRepo *repository.UserRepository
+	issuer   string
+}
+
+func NewTwoFactorService(repo *repository.TwoFactorRepository, userRepo *repository.UserRepository, issuer string) *TwoFactorService {
+	return &TwoFactorService{repo: repo, userRepo: userRepo, issuer: issuer}
+}
+
+func (s *TwoFactorService) Status(userID int) (*model.TwoFactorStatus, error) {
+	user, err := s.userRepo.GetUserByID(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	status := &model.TwoFactorStatus{Enabled: user.TOTPEnabledAt != nil, EnabledAt: user.TOTPEnabledAt}
+	if status.Enabled {
+		status.RecoveryCodesLeft, err = s.repo.CountRecoveryCodes(userID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return status, nil
+}
+
+// Setup starts enrollment with a fresh secret. Two-factor authentication is
+// only switched on once a code from the authenticator app is confirmed.
+func (s *TwoFactorService) Setup(userID int) (*model.TwoFactorSetup, error) {
+	user, err := s.userRepo.GetUserByID(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	secret, err := utils.NewTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.SetPendingSecret(userID, secret); err != nil {
+		return nil, err
+	}
+
+	return &model.TwoFactorSetup{
+		Secret:          secret,
+		ProvisioningURI: utils.TOTPProvisioningURI(secret, s.issuer, user.Email),
+	}, nil
+}
+
+// Enable confirms enrollment with a code from the authenticator app and
+// returns the recovery codes.
+func (s *TwoFactorService) Enable(userID int, code string) (*model.RecoveryCodes, error) {
+	secret, enabledAt, lastStep, err := s.repo.GetSecret(userID)
+	if err != nil {
+		return nil, err
+	}
+	if enabledAt != nil {
+		return nil, errors.New("two-factor authentication is already enabled")
+	}
+
+	step, ok := utils.VerifyTOTP(secret, strings.TrimSpace(code), lastStep, time.Now())
+	if !ok {
+		return nil, errors.New("invalid code")
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.Enable(userID, step, hashes); err != nil {
+		return nil, err
+	}
+
+	return &model.RecoveryCodes{Codes: codes}, nil
+}
+
+// Disable switches two-factor authentication off after checking a current
+// code or a recovery code.
+func (s *TwoFactorService) Disable(userID int, code string) error {
+	if err := s.Verify(userID, code); err != nil {
+		return err
+	}
+	return s.repo.Disable(userID)
+}
+
+// RegenerateRecoveryCodes replaces the recovery codes after checking a
+// current code. The old codes stop working.
+func (s *TwoFactorService) RegenerateRecoveryCodes(userID int, code string) (*model.RecoveryCodes, error) {
+	if err := s.Verify(userID, code); err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.ReplaceRecoveryCodes(userID, hashes); err != nil {
+		return nil, err
+	}
+
+	return &model.RecoveryCodes{Codes: codes}, nil
+}
+
+// Verify checks a code from the authenticator app or a recovery code. Each
+// code is accepted only once.
+func (s *TwoFactorService) Verify(userID int, code string) error {
+	secret, enabledAt, lastStep, err := s.repo.GetSecret(userID)
+	if err != nil || enabledAt == nil {
+		return errors.New("two-factor authentication is not enabled")
+	}
+
+	code = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), " ", ""))
+	if step, ok := utils.VerifyTOTP(secret, code, lastStep, time.Now()); ok {
+		used, err := s.repo.UseStep(userID, step)
+		if err != nil {
+			return err
+		}
+		if !used {
+			return errors.New("invalid code")
+		}
+		return nil
+	}
+
+	used, err := s.repo.UseRecoveryCode(userID, hashToken(code))
+	if err != nil {
+		return err
+	}
+	if !used {
+		return errors.New("invalid code")
+	}
+	return nil
+}
+
+// newRecoveryCodes returns readable recovery codes and the hashes to store.
+func newRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		token, err := utils.NewRandomToken(5)
+		if err != nil {
+			return nil, nil, err
+		}
+		code := token[:5] + "-" + token[5:]
+		codes = append(codes, code)
+		hashes = append(hashes, hashToken(code))
+	}
+	return codes, hashes, nil
+}
@@ -1,6 +1,10 @@
 package service
 
-import repository "tender-managment/internal/db/repo"
+import (
+	"strings"
+	repository "tender-managment/internal/db/repo"
+	"tender-managment/internal/model"
+)
 
 type UserService struct {
 	repo *repository.UserRepository
@@ -9,3 +13,20 @@ type UserService struct {
 func NewUserService(repo *repository.UserRepository) *UserService {
 	return &UserService{repo: repo}
 }
+
+func (s *UserService) GetProfile(userID int) (*model.UserProfile, error) {
+	return s.repo.GetProfile(userID)
+}
+
+func (s *UserService) UpdateProfile(userID int, payload model.UpdateProfile) (*model.UserProfile, error) {
+	payload.FullName = strings.TrimSpace(payload.FullName)
+	payload.Phone = strings.TrimSpace(payload.Phone)
+	payload.JobTitle = strings.TrimSpace(payload.JobTitle)
+	return s.repo.UpdateProfile(userID, payload)
+}
+
+// ColleagueIDs returns the user and the other members of their organization,
+// whose cached lists share the organization's tenders and bids.
+func (s *UserService) ColleagueIDs(userID int) ([]int, error) {
+	return s.repo.GetColleagueIDs(userID)
+}
@@ -1,29 +1,151 @@
 package utils
 
 import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
 	"github.com/dgrijalva/jwt-go"
 	"time"
 )
 
-var JWTSECRET = "secret"
+// AccessTokenTTL is how long an access token stays valid. Clients renew it
+// with their refresh token.
+var AccessTokenTTL = 15 * time.Minute
 
-func GenerateToken(userId int, role string) (string, error) {
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"user_id": userId,
-		"role":    role,
-		"exp":     time.Now().Add(24 * time.Hour).Unix(),
+// GenerateToken issues an access token. The returned jti identifies the token
+// on the revocation list. twoFactor records that the session was opened with
+// a second factor.
+func GenerateToken(userId int, role string, twoFactor bool) (string, string, error) {
+	jti, err := NewRandomToken(16)
+	if err != nil {
+		return "", "", err
+	}
+
+	now := time.Now()
+	token := jwt.NewWithClaims(keySet.signing.method, jwt.MapClaims{
+		"user_id":    userId,
+		"role":       role,
+		"jti":        jti,
+		"two_factor": twoFactor,
+		"iss":        keySet.issuer,
+		"aud":        keySet.audience,
+		"iat":        now.Unix(),
+		"exp":        now.Add(AccessTokenTTL).Unix(),
 	})
+	token.Header["kid"] = keySet.signing.id
 
-	signedToken, err := token.SignedString([]byte(JWTSECRET))
+	signedToken, err := token.SignedString(keySet.signing.signKey)
 	if err != nil {
+		return "", "", err
+	}
+
+	return signedToken, jti, nil
+}
+
+// NewRandomToken returns n random bytes, hex encoded.
+func NewRandomToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
 		return "", err
 	}
+	return hex.EncodeToString(b), nil
+}
+
+// ChallengeTTL is how long a user has to enter their second factor after
+// their password.
+const ChallengeTTL = 5 * time.Minute
+
+// GenerateChallengeToken issues the token that stands for a correct password
+// during a two-step login. It has its own audience, so it is never accepted
+// as an access token.
+func GenerateChallengeToken(userId int) (string, error) {
+	now := time.Now()
+	token := jwt.NewWithClaims(keySet.signing.method, jwt.MapClaims{
+		"user_id": userId,
+		"iss":     keySet.issuer,
+		"aud":     challengeAudience(),
+		"iat":     now.Unix(),
+		"exp":     now.Add(ChallengeTTL).Unix(),
+	})
+	token.Header["kid"] = keySet.signing.id
+
+	return token.SignedString(keySet.signing.signKey)
+}
+
+// ParseChallengeToken returns the user a challenge token was issued to.
+func ParseChallengeToken(tokenString string) (int, error) {
+	token, err := parseToken(tokenString, challengeAudience())
+	if err != nil || !token.Valid {
+		return 0, errors.New("invalid challenge token")
+	}
+
+	userID, ok := token.Claims.(jwt.MapClaims)["user_id"].(float64)
+	if !ok {
+		return 0, errors.New("invalid challenge token")
+	}
+	return int(userID), nil
+}
 
-	return signedToken, nil
+func challengeAudience() string {
+	return keySet.audience + "/login-challenge"
 }
 
+// jwtParser verifies an access token against the configured keys and checks
+// that it was issued by and for this service.
 func jwtParser(tokenString string) (*jwt.Token, error) {
-	return jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		return []byte(JWTSECRET), nil
-	})
+	return parseToken(tokenString, keySet.audience)
+}
+
+func parseToken(tokenString, audience string) (*jwt.Token, error) {
+	token, err := jwt.Parse(tokenString, keySet.keyFor)
+	if err != nil {
+		return nil, err
+	}
+
+	claims := token.Claims.(jwt.MapClaims)
+	if !claims.VerifyIssuer(keySet.issuer, true) || !claims.VerifyAudience(audience, true) {
+		return nil, errors.New("token has the wrong issuer or audience")
+	}
+	return token, nil
+}
+
+// AccessClaims are the claims of a valid access token.
+type AccessClaims struct {
+	UserID    int
+	JTI       string
+	ExpiresAt time.Time
+	// TwoFactor is set when the session was opened with a second factor.
+	TwoFactor bool
+}
+
+// parseAccessToken checks the token's signature and expiry and that it has
+// not been revoked.
+func parseAccessToken(ctx context.Context, tokenString string) (*AccessClaims, error) {
+	token, err := jwtParser(tokenString)
+	if err != nil || !token.Valid {
+		return nil, errors.New("invalid token")
+	}
+
+	claims := token.Claims.(jwt.MapClaims)
+	userID, ok := claims["user_id"].(float64)
+	if !ok {
+		return nil, errors.New("invalid token")
+	}
+	jti, ok := claims["jti"].(string)
+	if !ok || jti == "" {
+		return nil, errors.New("invalid token")
+	}
+	exp, ok := claims["exp"].(float64)
+	if !ok {
+		return nil, errors.New("invalid token")
+	}
+
+	if IsTokenRevoked(ctx, jti) {
+		return nil, errors.New("invalid token")
+	}
+
+	twoFactor, _ := claims["two_factor"].(bool)
+
+	return &AccessClaims{UserID: int(userID), JTI: jti, ExpiresAt: time.Unix(int64(exp), 0), TwoFactor: twoFactor}, nil
 }
@@ -0,0 +1,166 @@
+package utils
+
+import (
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"github.com/dgrijalva/jwt-go"
+	"os"
+	"tender-managment/internal/config"
+)
+
+// jwtKey is a key tokens are verified with. signKey is nil for keys that are
+// only kept to verify tokens signed before a rotation.
+type jwtKey struct {
+	id        string
+	method    jwt.SigningMethod
+	signKey   interface{}
+	verifyKey interface{}
+}
+
+// KeySet holds the keys access tokens are signed and verified with, and the
+// issuer and audience they are issued for.
+type KeySet struct {
+	signing  *jwtKey
+	keys     map[string]*jwtKey
+	ordered  []*jwtKey
+	issuer   string
+	audience string
+}
+
+var keySet *KeySet
+
+func SetKeySet(keys *KeySet) {
+	keySet = keys
+}
+
+// LoadKeySet reads the keys listed in the config. The signing key must be
+// one of them and must have its private part.
+func LoadKeySet(cfg config.AuthConfig) (*KeySet, error) {
+	if cfg.Issuer == "" || cfg.Audience == "" {
+		return nil, errors.New("auth issuer and audience are required")
+	}
+
+	ks := &KeySet{keys: make(map[string]*jwtKey), issuer: cfg.Issuer, audience: cfg.Audience}
+	for _, k := range cfg.Keys {
+		if k.ID == "" {
+			return nil, errors.New("every auth key needs an id")
+		}
+		if _, ok := ks.keys[k.ID]; ok {
+			return nil, fmt.Errorf("duplicate auth key id %q", k.ID)
+		}
+
+		key, err := loadKey(k)
+		if err != nil {
+			return nil, fmt.Errorf("auth key %q: %w", k.ID, err)
+		}
+		ks.keys[k.ID] = key
+		ks.ordered = append(ks.ordered, key)
+	}
+
+	signing, ok := ks.keys[cfg.SigningKey]
+	if !ok {
+		return nil, fmt.Errorf("signing key %q is not configured", cfg.SigningKey)
+	}
+	if signing.signKey == nil {
+		return nil, fmt.Errorf("signing key %q has no private key", cfg.SigningKey)
+	}
+	ks.signing = signing
+
+	return ks, nil
+}
+
+func loadKey(k config.JWTKey) (*jwtKey, error) {
+	key := &jwtKey{id: k.ID}
+
+	switch k.Algorithm {
+	case "HS256":
+		if len(k.Secret) < 8 {
+			return nil, errors.New("HS256 secret is missing or too short")
+		}
+		key.method = jwt.SigningMethodHS256
+		key.signKey = []byte(k.Secret)
+		key.verifyKey = []byte(k.Secret)
+	case "RS256":
+		key.method = jwt.SigningMethodRS256
+		if k.PrivateKeyFile != "" {
+			data, err := os.ReadFile(k.PrivateKeyFile)
+			if err != nil {
+				return nil, err
+			}
+			private, err := jwt.ParseRSAPrivateKeyFromPEM(data)
+			if err != nil {
+				return nil, err
+			}
+			key.signKey = private
+			key.verifyKey = &private.PublicKey
+		} else {
+			data, err := os.ReadFile(k.PublicKeyFile)
+			if err != nil {
+				return nil, err
+			}
+			public, err := jwt.ParseRSAPublicKeyFromPEM(data)
+			if err != nil {
+				return nil, err
+			}
+			key.verifyKey = public
+		}
+	case "EdDSA":
+		key.method = SigningMethodEdDSA
+		if k.PrivateKeyFile != "" {
+			parsed, err := readPEMKey(k.PrivateKeyFile, x509.ParsePKCS8PrivateKey)
+			if err != nil {
+				return nil, err
+			}
+			private, ok := parsed.(ed25519.PrivateKey)
+			if !ok {
+				return nil, errors.New("private key is not an Ed25519 key")
+			}
+			key.signKey = private
+			key.verifyKey = private.Public()
+		} else {
+			parsed, err := readPEMKey(k.PublicKeyFile, x509.ParsePKIXPublicKey)
+			if err != nil {
+				return nil, err
+			}
+			public, ok := parsed.(ed25519.PublicKey)
+			if !ok {
+				return nil, errors.New("public key is not an Ed25519 key")
+			}
+			key.verifyKey = public
+		}
+	default:
+		return nil, fmt.Errorf("unsupported algorithm %q", k.Algorithm)
+	}
+
+	return key, nil
+}
+
+func readPEMKey(path string, parse func([]byte) (interface{}, error)) (interface{}, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("key file is not PEM encoded")
+	}
+	return parse(block.Bytes)
+}
+
+// keyFor picks the key a token claims to be signed with and makes sure the
+// token uses that key's algorithm, so a public key can never be used as an
+// HMAC secret.
+func (ks *KeySet) keyFor(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	key, ok := ks.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+	if token.Method.Alg() != key.method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method %q", token.Method.Alg())
+	}
+	return key.verifyKey, nil
+}
@@ -0,0 +1,100 @@
+package utils
+
+import (
+	"bufio"
+	_ "embed"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+//go:embed common_passwords.txt
+var commonPasswordList string
+
+// commonPasswords holds the bundled list of common and breached passwords,
+// lower-cased.
+var commonPasswords = loadCommonPasswords(commonPasswordList)
+
+// PasswordPolicy is what new passwords must satisfy. Passwords containing the
+// username or the local part of the email, or found on the bundled list of
+// common passwords, are always rejected.
+type PasswordPolicy struct {
+	MinLength     int
+	RequireUpper  bool
+	RequireLower  bool
+	RequireDigit  bool
+	RequireSymbol bool
+}
+
+var passwordPolicy = PasswordPolicy{MinLength: 10}
+
+func SetPasswordPolicy(policy PasswordPolicy) {
+	if policy.MinLength < 8 {
+		policy.MinLength = 8
+	}
+	passwordPolicy = policy
+}
+
+// ValidatePassword checks a new password against the policy.
+func ValidatePassword(password, username, email string) error {
+	policy := passwordPolicy
+
+	if len([]rune(password)) < policy.MinLength {
+		return fmt.Errorf("password must be at least %d characters long", policy.MinLength)
+	}
+	if len(password) > 72 {
+		// bcrypt ignores everything past 72 bytes.
+		return errors.New("password must be at most 72 bytes long")
+	}
+
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			symbol = true
+		}
+	}
+	switch {
+	case policy.RequireUpper && !upper:
+		return errors.New("password must contain an uppercase letter")
+	case policy.RequireLower && !lower:
+		return errors.New("password must contain a lowercase letter")
+	case policy.RequireDigit && !digit:
+		return errors.New("password must contain a digit")
+	case policy.RequireSymbol && !symbol:
+		return errors.New("password must contain a symbol")
+	}
+
+	lowered := strings.ToLower(password)
+	if len(username) >= 3 && strings.Contains(lowered, strings.ToLower(username)) {
+		return errors.New("password must not contain the username")
+	}
+	if local, _, _ := strings.Cut(strings.ToLower(email), "@"); len(local) >= 3 && strings.Contains(lowered, local) {
+		return errors.New("password must not contain the email address")
+	}
+	if commonPasswords[lowered] {
+		return errors.New("password is too common, choose another one")
+	}
+
+	return nil
+}
+
+func loadCommonPasswords(list string) map[string]bool {
+	passwords := make(map[string]bool)
+	scanner := bufio.NewScanner(strings.NewReader(list))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		passwords[strings.ToLower(line)] = true
+	}
+	return passwords
+}
@@ -0,0 +1,141 @@
+package utils
+
+import repository "tender-managment/internal/db/repo"
+
+// Permission names an action a route requires.
+type Permission string
+
+const (
+	// AnyUser only requires a valid token.
+	AnyUser Permission = ""
+
+	PermTenderView          Permission = "tender:view"
+	PermTenderBrowse        Permission = "tender:browse"
+	PermTenderCreate        Permission = "tender:create"
+	PermTenderEdit          Permission = "tender:edit"
+	PermBidView             Permission = "bid:view"
+	PermBidCreate           Permission = "bid:create"
+	PermBidManage           Permission = "bid:manage"
+	PermEvaluationManage    Permission = "evaluation:manage"
+	PermEvaluationScore     Permission = "evaluation:score"
+	PermAwardApprove        Permission = "award:approve"
+	PermContractView        Permission = "contract:view"
+	PermContractManage      Permission = "contract:manage"
+	PermInvoiceCreate       Permission = "invoice:create"
+	PermInvoiceApprove      Permission = "invoice:approve"
+	PermQualificationReview Permission = "qualification:review"
+	PermOrgManage           Permission = "org:manage"
+	PermPlatformAdmin       Permission = "platform:admin"
+)
+
+// Organization roles a member can hold.
+const (
+	OrgRoleOwner              = "owner"
+	OrgRoleProcurementOfficer = "procurement_officer"
+	OrgRoleEvaluator          = "evaluator"
+	OrgRoleViewer             = "viewer"
+	OrgRoleBidManager         = "bid_manager"
+)
+
+// accountPermissions is what each account type may do at most. Users outside
+// an organization get all of it.
+var accountPermissions = map[string][]Permission{
+	"client": {
+		PermTenderView, PermTenderCreate, PermTenderEdit, PermBidView,
+		PermEvaluationManage, PermEvaluationScore, PermAwardApprove,
+		PermContractView, PermContractManage, PermInvoiceApprove,
+		PermQualificationReview, PermOrgManage,
+	},
+	"contractor": {
+		PermTenderView, PermTenderBrowse, PermBidView, PermBidCreate, PermBidManage,
+		PermContractView, PermContractManage, PermInvoiceCreate, PermOrgManage,
+	},
+	// Administrators moderate the platform but take no part in procurement.
+	"admin": {
+		PermPlatformAdmin,
+	},
+}
+
+// orgRolePermissions narrows down what members of an organization may do.
+// A member gets the permissions both their account type and their role
+// grant.
+var orgRolePermissions = map[string][]Permission{
+	OrgRoleOwner: {
+		PermTenderView, PermTenderBrowse, PermTenderCreate, PermTenderEdit, PermBidView, PermBidCreate, PermBidManage,
+		PermEvaluationManage, PermEvaluationScore, PermAwardApprove,
+		PermContractView, PermContractManage, PermInvoiceCreate, PermInvoiceApprove,
+		PermQualificationReview, PermOrgManage,
+	},
+	OrgRoleProcurementOfficer: {
+		PermTenderView, PermTenderCreate, PermTenderEdit, PermBidView,
+		PermEvaluationManage, PermEvaluationScore,
+		PermContractView, PermContractManage, PermInvoiceApprove, PermQualificationReview,
+	},
+	OrgRoleEvaluator: {
+		PermTenderView, PermBidView, PermEvaluationScore, PermContractView,
+	},
+	OrgRoleViewer: {
+		PermTenderView, PermTenderBrowse, PermBidView, PermContractView,
+	},
+	OrgRoleBidManager: {
+		PermTenderView, PermTenderBrowse, PermBidView, PermBidCreate, PermBidManage,
+		PermContractView, PermContractManage, PermInvoiceCreate,
+	},
+}
+
+// verifiedEmailPermissions are only granted once the user has confirmed their
+// email address, so nobody can tender or bid under an address they do not own.
+var verifiedEmailPermissions = []Permission{PermTenderCreate, PermBidCreate}
+
+// RequiresVerifiedEmail reports whether the permission needs a verified email.
+func RequiresVerifiedEmail(permission Permission) bool {
+	return grants(verifiedEmailPermissions, permission)
+}
+
+// twoFactorPermissions are withheld from members of organizations that
+// require two-factor authentication until they have switched it on.
+var twoFactorPermissions = []Permission{PermAwardApprove}
+
+// RequiresTwoFactor reports whether the permission can be made to need
+// two-factor authentication.
+func RequiresTwoFactor(permission Permission) bool {
+	return grants(twoFactorPermissions, permission)
+}
+
+// IsOrgRole reports whether role is a known organization role.
+func IsOrgRole(role string) bool {
+	_, ok := orgRolePermissions[role]
+	return ok
+}
+
+// HasPermission applies the policy to a user's account type and, when they
+// belong to an organization, their role in it.
+func HasPermission(accountRole, orgRole string, inOrg bool, permission Permission) bool {
+	if permission == AnyUser {
+		return true
+	}
+	if !grants(accountPermissions[accountRole], permission) {
+		return false
+	}
+	if !inOrg {
+		return true
+	}
+	return grants(orgRolePermissions[orgRole], permission)
+}
+
+func grants(permissions []Permission, permission Permission) bool {
+	for _, p := range permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+var userRepo *repository.UserRepository
+
+// SetUserRepository gives AuthMiddleware access to the users' current
+// organization membership.
+func SetUserRepository(repo *repository.UserRepository) {
+	userRepo = repo
+}
@@ -0,0 +1,154 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+var (
+	testAccountRoles = []string{"client", "contractor", "admin", "unknown"}
+	testOrgRoles     = []string{
+		OrgRoleOwner, OrgRoleProcurementOfficer, OrgRoleEvaluator, OrgRoleViewer, OrgRoleBidManager,
+		"", "unknown",
+	}
+)
+
+const allOrgRoles = "owner procurement_officer evaluator viewer bid_manager"
+
+// permissionMatrix spells out the expected policy. solo lists the account
+// roles allowed outside an organization; members lists, per account role,
+// the organization roles allowed. Every combination not listed is denied.
+var permissionMatrix = []struct {
+	permission Permission
+	solo       []string
+	members    map[string]string
+}{
+	{PermTenderView, []string{"client", "contractor"}, map[string]string{
+		"client": allOrgRoles, "contractor": allOrgRoles,
+	}},
+	{PermTenderBrowse, []string{"contractor"}, map[string]string{
+		"contractor": "owner viewer bid_manager",
+	}},
+	{PermTenderCreate, []string{"client"}, map[string]string{
+		"client": "owner procurement_officer",
+	}},
+	{PermTenderEdit, []string{"client"}, map[string]string{
+		"client": "owner procurement_officer",
+	}},
+	{PermBidView, []string{"client", "contractor"}, map[string]string{
+		"client": allOrgRoles, "contractor": allOrgRoles,
+	}},
+	{PermBidCreate, []string{"contractor"}, map[string]string{
+		"contractor": "owner bid_manager",
+	}},
+	{PermBidManage, []string{"contractor"}, map[string]string{
+		"contractor": "owner bid_manager",
+	}},
+	{PermEvaluationManage, []string{"client"}, map[string]string{
+		"client": "owner procurement_officer",
+	}},
+	{PermEvaluationScore, []string{"client"}, map[string]string{
+		"client": "owner procurement_officer evaluator",
+	}},
+	{PermAwardApprove, []string{"client"}, map[string]string{
+		"client": "owner",
+	}},
+	{PermContractView, []string{"client", "contractor"}, map[string]string{
+		"client": allOrgRoles, "contractor": allOrgRoles,
+	}},
+	{PermContractManage, []string{"client", "contractor"}, map[string]string{
+		"client": "owner procurement_officer bid_manager", "contractor": "owner procurement_officer bid_manager",
+	}},
+	{PermInvoiceCreate, []string{"contractor"}, map[string]string{
+		"contractor": "owner bid_manager",
+	}},
+	{PermInvoiceApprove, []string{"client"}, map[string]string{
+		"client": "owner procurement_officer",
+	}},
+	{PermQualificationReview, []string{"client"}, map[string]string{
+		"client": "owner procurement_officer",
+	}},
+	{PermOrgManage, []string{"client", "contractor"}, map[string]string{
+		"client": "owner", "contractor": "owner",
+	}},
+	{PermPlatformAdmin, []string{"admin"}, nil},
+}
+
+func TestHasPermission(t *testing.T) {
+	for _, row := range permissionMatrix {
+		for _, account := range testAccountRoles {
+			for _, orgRole := range testOrgRoles {
+				for _, inOrg := range []bool{false, true} {
+					want := contains(row.solo, account)
+					if inOrg {
+						want = containsWord(row.members[account], orgRole)
+					}
+					got := HasPermission(account, orgRole, inOrg, row.permission)
+					if got != want {
+						t.Errorf("HasPermission(%q, %q, %v, %q) = %v, want %v",
+							account, orgRole, inOrg, row.permission, got, want)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestPermissionMatrixIsComplete(t *testing.T) {
+	covered := make(map[Permission]bool, len(permissionMatrix))
+	for _, row := range permissionMatrix {
+		covered[row.permission] = true
+	}
+	for _, table := range []map[string][]Permission{accountPermissions, orgRolePermissions} {
+		for role, permissions := range table {
+			for _, p := range permissions {
+				if !covered[p] {
+					t.Errorf("permission %q granted to %q is missing from the matrix", p, role)
+				}
+			}
+		}
+	}
+}
+
+func TestHasPermissionAnyUser(t *testing.T) {
+	for _, account := range testAccountRoles {
+		for _, orgRole := range testOrgRoles {
+			for _, inOrg := range []bool{false, true} {
+				if !HasPermission(account, orgRole, inOrg, AnyUser) {
+					t.Errorf("HasPermission(%q, %q, %v, AnyUser) = false, want true", account, orgRole, inOrg)
+				}
+			}
+		}
+	}
+}
+
+func TestHasPermissionUnknownPermission(t *testing.T) {
+	if HasPermission("client", OrgRoleOwner, true, Permission("tender:destroy")) {
+		t.Error("unknown permission granted")
+	}
+	if HasPermission("admin", "", false, Permission("tender:destroy")) {
+		t.Error("unknown permission granted to admin")
+	}
+}
+
+func TestIsOrgRole(t *testing.T) {
+	for _, role := range testOrgRoles {
+		want := containsWord(allOrgRoles, role)
+		if got := IsOrgRole(role); got != want {
+			t.Errorf("IsOrgRole(%q) = %v, want %v", role, got, want)
+		}
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func containsWord(words, word string) bool {
+	return word != "" && contains(strings.Fields(words), word)
+}
@@ -0,0 +1,38 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"tender-managment/internal/db"
+	"time"
+)
+
+const revokedTokenKey = "revoked_token:%s"
+
+var revocations *db.Redis
+
+// SetRevocationStore sets where revoked access tokens are recorded.
+func SetRevocationStore(store *db.Redis) {
+	revocations = store
+}
+
+// RevokeToken puts an access token on the revocation list. The entry expires
+// with the token, since an expired token is rejected anyway.
+func RevokeToken(ctx context.Context, jti string, ttl time.Duration) error {
+	if ttl <= 0 {
+		return nil
+	}
+	return revocations.Set(ctx, fmt.Sprintf(revokedTokenKey, jti), "1", ttl)
+}
+
+// IsTokenRevoked reports whether the access token has been revoked. When the
+// list cannot be read the token is treated as revoked.
+func IsTokenRevoked(ctx context.Context, jti string) bool {
+	val, err := revocations.Get(ctx, fmt.Sprintf(revokedTokenKey, jti))
+	if err != nil {
+		log.Println("Error checking token revocation:", err)
+		return true
+	}
+	return val != ""
+}
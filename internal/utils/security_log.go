@@ -0,0 +1,14 @@
+package utils
+
+import (
+	"log/slog"
+	"os"
+)
+
+var securityLog = slog.New(slog.NewJSONHandler(os.Stderr, nil)).With("category", "security")
+
+// LogSecurityEvent writes a structured log line for events worth reviewing,
+// such as failed logins, lockouts and token reuse. attrs are key-value pairs.
+func LogSecurityEvent(event string, attrs ...any) {
+	securityLog.Warn(event, attrs...)
+}
@@ -1,7 +1,7 @@
 package utils
 
 import (
-	"github.com/dgrijalva/jwt-go"
+	"context"
 	"github.com/gin-gonic/gin"
 	"github.com/gorilla/websocket"
 	"log"
@@ -9,6 +9,7 @@ import (
 	"strings"
 	"sync"
 	repository "tender-managment/internal/db/repo"
+	"time"
 )
 
 type WebSocketManager struct {
@@ -18,6 +19,10 @@ type WebSocketManager struct {
 
 var wsManager = WebSocketManager{connections: make(map[int]*websocket.Conn)}
 
+// SocketCheckInterval is how often an open WebSocket is checked against token
+// revocation, token expiry and account suspension.
+var SocketCheckInterval = 30 * time.Second
+
 func WebSocketHandler(c *gin.Context) {
 	authHeader := c.GetHeader("Authorization")
 	if authHeader == "" {
@@ -26,14 +31,22 @@ func WebSocketHandler(c *gin.Context) {
 	}
 
 	tokenString := strings.Replace(authHeader, "Bearer ", "", 1)
-	token, err := jwtParser(tokenString)
-	if err != nil || !token.Valid {
+	claims, err := parseAccessToken(c.Request.Context(), tokenString)
+	if err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"message": "Invalid token"})
 		return
 	}
+	userID := claims.UserID
 
-	claims := token.Claims.(jwt.MapClaims)
-	userID := int(claims["user_id"].(float64))
+	user, err := userRepo.GetUserByID(userID)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": "Invalid token"})
+		return
+	}
+	if user.SuspendedAt != nil {
+		c.JSON(http.StatusForbidden, gin.H{"message": "Account suspended"})
+		return
+	}
 
 	upgrader := websocket.Upgrader{
 		CheckOrigin: func(r *http.Request) bool {
@@ -52,6 +65,10 @@ func WebSocketHandler(c *gin.Context) {
 	wsManager.connections[userID] = conn
 	wsManager.mutex.Unlock()
 
+	done := make(chan struct{})
+	defer close(done)
+	go watchSocket(conn, claims, done)
+
 	for {
 		_, _, err := conn.ReadMessage()
 		if err != nil {
@@ -60,15 +77,65 @@ func WebSocketHandler(c *gin.Context) {
 	}
 
 	wsManager.mutex.Lock()
-	delete(wsManager.connections, userID)
+	if wsManager.connections[userID] == conn {
+		delete(wsManager.connections, userID)
+	}
 	wsManager.mutex.Unlock()
 }
 
-func SendNotification(repo repository.BidRepository, userID int, message string, relationID string, relationType string) {
+// watchSocket closes the connection once its access token is revoked or
+// expires, or the account is suspended. Clients reconnect with a fresh token.
+func watchSocket(conn *websocket.Conn, claims *AccessClaims, done <-chan struct{}) {
+	ticker := time.NewTicker(SocketCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			reason := ""
+			if !time.Now().Before(claims.ExpiresAt) {
+				reason = "Token expired"
+			} else if IsTokenRevoked(context.Background(), claims.JTI) {
+				reason = "Token revoked"
+			} else if user, err := userRepo.GetUserByID(claims.UserID); err != nil || user.SuspendedAt != nil {
+				reason = "Account suspended"
+			}
+			if reason != "" {
+				closeSocket(conn, reason)
+				return
+			}
+		}
+	}
+}
+
+// CloseUserSocket disconnects the user's open WebSocket, if any, without
+// waiting for the next periodic check.
+func CloseUserSocket(userID int, reason string) {
+	wsManager.mutex.Lock()
+	conn, ok := wsManager.connections[userID]
+	wsManager.mutex.Unlock()
+
+	if ok {
+		closeSocket(conn, reason)
+	}
+}
+
+func closeSocket(conn *websocket.Conn, reason string) {
+	message := websocket.FormatCloseMessage(websocket.ClosePolicyViolation, reason)
+	_ = conn.WriteControl(websocket.CloseMessage, message, time.Now().Add(time.Second))
+	_ = conn.Close()
+}
+
+// SendNotification stores the notification and pushes it to the user's open
+// WebSocket. It returns an error only if the notification could not be
+// stored; a failed push is logged.
+func SendNotification(repo repository.BidRepository, userID int, message string, relationID string, relationType string) error {
 	err := repo.CreateNotification(userID, message, relationID, relationType)
 	if err != nil {
 		log.Println("Error inserting notification:", err)
-		return
+		return err
 	}
 
 	wsManager.mutex.Lock()
@@ -80,4 +147,5 @@ func SendNotification(repo repository.BidRepository, userID int, message string,
 			log.Println("Error sending WebSocket notification:", err)
 		}
 	}
+	return nil
 }
@@ -1,7 +1,6 @@
 package utils
 
 import (
-	"github.com/dgrijalva/jwt-go"
 	"github.com/gin-gonic/gin"
 	"golang.org/x/crypto/bcrypt"
 	"net/http"
@@ -20,7 +19,10 @@ func ComparePasswords(hashedPassword, plainPassword string) error {
 	return bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(plainPassword))
 }
 
-func AuthMiddleware(allowedRoles []string) gin.HandlerFunc {
+// AuthMiddleware checks the bearer token, that it has not been revoked and
+// that the user holds the permission. Organization membership is read from
+// the database on every request so role changes apply immediately.
+func AuthMiddleware(permission Permission) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
@@ -30,33 +32,52 @@ func AuthMiddleware(allowedRoles []string) gin.HandlerFunc {
 		}
 
 		tokenString := strings.Replace(authHeader, "Bearer ", "", 1)
-		token, err := jwtParser(tokenString)
+		claims, err := parseAccessToken(c.Request.Context(), tokenString)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"message": "Invalid token"})
+			c.Abort()
+			return
+		}
+		userId := claims.UserID
 
-		if err != nil || !token.Valid {
+		user, err := userRepo.GetUserByID(userId)
+		if err != nil {
 			c.JSON(http.StatusUnauthorized, gin.H{"message": "Invalid token"})
 			c.Abort()
 			return
 		}
 
-		claims := token.Claims.(jwt.MapClaims)
-		userRole := claims["role"].(string)
-		userId := int(claims["user_id"].(float64))
+		if user.SuspendedAt != nil {
+			c.JSON(http.StatusForbidden, gin.H{"message": "Account suspended"})
+			c.Abort()
+			return
+		}
 
-		roleAllowed := false
-		for _, role := range allowedRoles {
-			if role == userRole {
-				roleAllowed = true
-				break
-			}
+		if user.EmailVerifiedAt == nil && RequiresVerifiedEmail(permission) {
+			c.JSON(http.StatusForbidden, gin.H{"message": "Email not verified"})
+			c.Abort()
+			return
+		}
+
+		// The session itself must have passed the second factor; having it
+		// enabled on the account is not enough.
+		if user.OrgRequiresTwoFactor && (user.TOTPEnabledAt == nil || !claims.TwoFactor) && RequiresTwoFactor(permission) {
+			c.JSON(http.StatusForbidden, gin.H{"message": "Two-factor authentication required"})
+			c.Abort()
+			return
 		}
 
-		if !roleAllowed {
-			c.JSON(http.StatusForbidden, gin.H{"message": "Unauthorized role"})
+		if !HasPermission(user.Role, user.OrgRole, user.OrgID != 0, permission) {
+			c.JSON(http.StatusForbidden, gin.H{"message": "Missing permission " + string(permission)})
 			c.Abort()
 			return
 		}
 		c.Set("user_id", userId)
-		c.Set("role", userRole)
+		c.Set("role", user.Role)
+		c.Set("org_id", user.OrgID)
+		c.Set("org_role", user.OrgRole)
+		c.Set("jti", claims.JTI)
+		c.Set("token_expires_at", claims.ExpiresAt)
 		c.Next()
 	}
 }
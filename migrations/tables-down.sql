@@ -0,0 +1,115 @@
+-- Reverts tables-up.sql step by step, newest changes first. Every statement
+-- tolerates missing tables because the Postgres image runs this file before
+-- tables-up.sql when it initialises an empty database.
+
+-- Two-factor authentication
+DROP TABLE IF EXISTS recovery_codes;
+ALTER TABLE IF EXISTS refresh_tokens DROP COLUMN IF EXISTS two_factor;
+ALTER TABLE IF EXISTS users DROP COLUMN IF EXISTS totp_last_step;
+ALTER TABLE IF EXISTS users DROP COLUMN IF EXISTS totp_enabled_at;
+ALTER TABLE IF EXISTS users DROP COLUMN IF EXISTS totp_secret;
+ALTER TABLE IF EXISTS organizations DROP COLUMN IF EXISTS require_2fa_for_awards;
+
+-- Email verification and password reset
+DROP TABLE IF EXISTS email_tokens;
+ALTER TABLE IF EXISTS users DROP COLUMN IF EXISTS email_verified_at;
+
+-- Refresh tokens
+DROP TABLE IF EXISTS refresh_tokens;
+
+-- Platform admins and account suspension. Admin accounts are kept, so the
+-- old role check only applies to new rows.
+DROP TABLE IF EXISTS admin_actions;
+ALTER TABLE IF EXISTS users DROP COLUMN IF EXISTS suspension_reason;
+ALTER TABLE IF EXISTS users DROP COLUMN IF EXISTS suspended_at;
+ALTER TABLE IF EXISTS users DROP CONSTRAINT IF EXISTS users_role_check;
+ALTER TABLE IF EXISTS users ADD CONSTRAINT users_role_check CHECK (role IN ('client', 'contractor')) NOT VALID;
+
+-- Organization roles
+ALTER TABLE IF EXISTS users DROP COLUMN IF EXISTS org_role;
+
+-- User profiles and organizations
+ALTER TABLE IF EXISTS bids DROP COLUMN IF EXISTS org_id;
+ALTER TABLE IF EXISTS tenders DROP COLUMN IF EXISTS org_id;
+ALTER TABLE IF EXISTS users DROP COLUMN IF EXISTS org_id;
+ALTER TABLE IF EXISTS users DROP COLUMN IF EXISTS job_title;
+ALTER TABLE IF EXISTS users DROP COLUMN IF EXISTS phone;
+ALTER TABLE IF EXISTS users DROP COLUMN IF EXISTS full_name;
+DROP TABLE IF EXISTS organization_contacts;
+DROP TABLE IF EXISTS organizations;
+
+-- Contract reviews
+DROP TABLE IF EXISTS reviews;
+
+-- Invoices
+DROP TABLE IF EXISTS invoice_taxes;
+DROP TABLE IF EXISTS invoice_lines;
+DROP TABLE IF EXISTS invoices;
+DROP TABLE IF EXISTS invoice_counters;
+
+-- Contract milestones
+DROP TABLE IF EXISTS milestone_documents;
+DROP TABLE IF EXISTS contract_milestones;
+
+-- Contracts
+DROP TABLE IF EXISTS contracts;
+
+-- Award standstill period and protests
+DROP TABLE IF EXISTS protest_documents;
+DROP TABLE IF EXISTS award_protests;
+ALTER TABLE IF EXISTS tenders DROP COLUMN IF EXISTS award_finalized_at;
+ALTER TABLE IF EXISTS tenders DROP COLUMN IF EXISTS standstill_ends_at;
+
+-- Conflict-of-interest declarations
+DROP TABLE IF EXISTS coi_declaration_bidders;
+DROP TABLE IF EXISTS coi_declarations;
+
+-- Evaluation committees
+DROP TABLE IF EXISTS bid_scores;
+DROP TABLE IF EXISTS tender_evaluators;
+
+-- Two-stage tenders. Shortlisted expressions of interest go back to pending.
+ALTER TABLE IF EXISTS bids DROP CONSTRAINT IF EXISTS bids_status_check;
+ALTER TABLE IF EXISTS bids ALTER COLUMN status TYPE VARCHAR(10) USING CASE WHEN status = 'shortlisted' THEN 'pending' ELSE status END;
+ALTER TABLE IF EXISTS bids ADD CONSTRAINT bids_status_check CHECK (status IN ('pending', 'awarded', 'rejected'));
+ALTER TABLE IF EXISTS bids DROP COLUMN IF EXISTS type;
+ALTER TABLE IF EXISTS tenders DROP COLUMN IF EXISTS eoi_deadline;
+ALTER TABLE IF EXISTS tenders DROP COLUMN IF EXISTS stage;
+
+-- Qualifications and the longer notification types
+DROP TABLE IF EXISTS tender_requirements;
+DROP TABLE IF EXISTS contractor_qualifications;
+ALTER TABLE IF EXISTS notifications ALTER COLUMN type TYPE VARCHAR(15) USING left(type, 15);
+
+-- Invitation-only tenders
+DROP TABLE IF EXISTS tender_invitations;
+ALTER TABLE IF EXISTS tenders DROP COLUMN IF EXISTS visibility;
+
+-- Currencies
+DROP TABLE IF EXISTS exchange_rates;
+ALTER TABLE IF EXISTS tender_templates DROP COLUMN IF EXISTS currency;
+ALTER TABLE IF EXISTS bids DROP COLUMN IF EXISTS currency;
+ALTER TABLE IF EXISTS tenders DROP COLUMN IF EXISTS currency;
+
+-- Bills of quantities
+DROP TABLE IF EXISTS bid_line_items;
+DROP TABLE IF EXISTS tender_boq_items;
+
+-- Tender lots
+DROP TABLE IF EXISTS bid_lots;
+DROP TABLE IF EXISTS tender_lots;
+
+-- Tender templates
+DROP TABLE IF EXISTS tender_template_attachments;
+DROP TABLE IF EXISTS tender_templates;
+ALTER TABLE IF EXISTS tenders DROP COLUMN IF EXISTS evaluation_criteria;
+
+-- Draft tenders with scheduled publication. Existing drafts are kept, so the
+-- old status check only applies to new rows.
+ALTER TABLE IF EXISTS tenders DROP CONSTRAINT IF EXISTS tenders_status_check;
+ALTER TABLE IF EXISTS tenders ADD CONSTRAINT tenders_status_check CHECK (status IN ('open', 'closed', 'awarded')) NOT VALID;
+ALTER TABLE IF EXISTS tenders DROP COLUMN IF EXISTS publish_at;
+
+-- Tender and bid attachments
+DROP TABLE IF EXISTS attachments;
+ALTER TABLE IF EXISTS tenders ADD COLUMN IF NOT EXISTS attachment_path VARCHAR;
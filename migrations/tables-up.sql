@@ -1,10 +1,42 @@
+CREATE TABLE IF NOT EXISTS organizations
+(
+    id         SERIAL PRIMARY KEY,
+    legal_name VARCHAR(255) NOT NULL,
+    tax_id     VARCHAR(50)  NOT NULL UNIQUE,
+    address    TEXT         NOT NULL DEFAULT '',
+    require_2fa_for_awards BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS organization_contacts
+(
+    id       SERIAL PRIMARY KEY,
+    org_id   INT          NOT NULL REFERENCES organizations (id) ON DELETE CASCADE,
+    name     VARCHAR(150) NOT NULL,
+    email    VARCHAR(150) NOT NULL DEFAULT '',
+    phone    VARCHAR(50)  NOT NULL DEFAULT '',
+    position VARCHAR(100) NOT NULL DEFAULT ''
+);
+
 CREATE TABLE IF NOT EXISTS users
 (
     id         SERIAL PRIMARY KEY,
     username   VARCHAR(100)                                         NOT NULL,
     password   VARCHAR(255)                                         NOT NULL,
     email      VARCHAR(150)                                         NOT NULL UNIQUE,
-    role       VARCHAR(10) CHECK (role IN ('client', 'contractor')) NOT NULL,
+    role       VARCHAR(10) CHECK (role IN ('client', 'contractor', 'admin')) NOT NULL,
+    full_name  VARCHAR(150)                                         NOT NULL DEFAULT '',
+    phone      VARCHAR(50)                                          NOT NULL DEFAULT '',
+    job_title  VARCHAR(100)                                         NOT NULL DEFAULT '',
+    org_id     INT REFERENCES organizations (id) ON DELETE SET NULL,
+    org_role   VARCHAR(20) CHECK (org_role IN ('owner', 'procurement_officer', 'evaluator', 'viewer', 'bid_manager')),
+    suspended_at      TIMESTAMP,
+    suspension_reason TEXT,
+    email_verified_at TIMESTAMP,
+    totp_secret       VARCHAR(64),
+    totp_enabled_at   TIMESTAMP,
+    totp_last_step    BIGINT NOT NULL DEFAULT 0,
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 );
@@ -13,12 +45,20 @@ CREATE TABLE IF NOT EXISTS tenders
 (
     id              SERIAL PRIMARY KEY,
     client_id       INT          NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    org_id          INT REFERENCES organizations (id) ON DELETE SET NULL,
     title           VARCHAR(255) NOT NULL,
     description     TEXT,
+    evaluation_criteria TEXT     NOT NULL DEFAULT '',
     deadline        DATE         NOT NULL,
     budget          NUMERIC(15, 2) CHECK (budget > 0),
-    status          VARCHAR(10) CHECK (status IN ('open', 'closed', 'awarded')) DEFAULT 'open',
-    attachment_path varchar,
+    currency        CHAR(3)      NOT NULL DEFAULT 'USD',
+    status          VARCHAR(10) CHECK (status IN ('draft', 'open', 'closed', 'awarded')) DEFAULT 'open',
+    visibility      VARCHAR(11) CHECK (visibility IN ('public', 'invite_only')) DEFAULT 'public',
+    stage           VARCHAR(3) CHECK (stage IN ('eoi', 'rfp')) DEFAULT 'rfp',
+    eoi_deadline    TIMESTAMP,
+    publish_at      TIMESTAMP,
+    standstill_ends_at TIMESTAMP,
+    award_finalized_at TIMESTAMP,
     created_at      TIMESTAMP                                                   DEFAULT CURRENT_TIMESTAMP,
     updated_at      TIMESTAMP                                                   DEFAULT CURRENT_TIMESTAMP
 );
@@ -29,10 +69,13 @@ CREATE TABLE IF NOT EXISTS bids
     id            SERIAL PRIMARY KEY,
     tender_id     INT NOT NULL REFERENCES tenders (id) ON DELETE CASCADE,
     contractor_id INT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    org_id        INT REFERENCES organizations (id) ON DELETE SET NULL,
     price         NUMERIC(15, 2) CHECK (price > 0),
+    currency      CHAR(3) NOT NULL DEFAULT 'USD',
     delivery_time INT CHECK (delivery_time > 0),
     comments      TEXT,
-    status        VARCHAR(10) CHECK (status IN ('pending', 'awarded', 'rejected')) DEFAULT 'pending',
+    type          VARCHAR(3) CHECK (type IN ('bid', 'eoi')) DEFAULT 'bid',
+    status        VARCHAR(11) CHECK (status IN ('pending', 'shortlisted', 'awarded', 'rejected')) DEFAULT 'pending',
     created_at    TIMESTAMP                                                        DEFAULT CURRENT_TIMESTAMP,
     updated_at    TIMESTAMP                                                        DEFAULT CURRENT_TIMESTAMP
 );
@@ -44,6 +87,446 @@ CREATE TABLE IF NOT EXISTS notifications
     user_id     INT  NOT NULL REFERENCES users (id) ON DELETE CASCADE,
     message     TEXT NOT NULL,
     relation_id INT,
-    type        VARCHAR(15),
+    type        VARCHAR(32),
     created_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 );
+
+CREATE TABLE IF NOT EXISTS attachments
+(
+    id          SERIAL PRIMARY KEY,
+    tender_id   INT          NOT NULL REFERENCES tenders (id) ON DELETE CASCADE,
+    bid_id      INT REFERENCES bids (id) ON DELETE CASCADE,
+    uploaded_by INT          NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    file_name   VARCHAR(255) NOT NULL,
+    storage_key VARCHAR(255) NOT NULL,
+    mime_type   VARCHAR(100) NOT NULL,
+    size        BIGINT       NOT NULL CHECK (size > 0),
+    sha256      CHAR(64)     NOT NULL,
+    created_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS tender_templates
+(
+    id                  SERIAL PRIMARY KEY,
+    client_id           INT          NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    name                VARCHAR(255) NOT NULL,
+    title               VARCHAR(255) NOT NULL,
+    description         TEXT         NOT NULL DEFAULT '',
+    evaluation_criteria TEXT         NOT NULL DEFAULT '',
+    budget              NUMERIC(15, 2) CHECK (budget > 0),
+    currency            CHAR(3)      NOT NULL DEFAULT 'USD',
+    created_at          TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (client_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS tender_template_attachments
+(
+    id          SERIAL PRIMARY KEY,
+    template_id INT          NOT NULL REFERENCES tender_templates (id) ON DELETE CASCADE,
+    file_name   VARCHAR(255) NOT NULL,
+    storage_key VARCHAR(255) NOT NULL,
+    mime_type   VARCHAR(100) NOT NULL,
+    size        BIGINT       NOT NULL CHECK (size > 0),
+    sha256      CHAR(64)     NOT NULL,
+    created_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS tender_lots
+(
+    id             SERIAL PRIMARY KEY,
+    tender_id      INT          NOT NULL REFERENCES tenders (id) ON DELETE CASCADE,
+    lot_number     INT          NOT NULL CHECK (lot_number > 0),
+    title          VARCHAR(255) NOT NULL,
+    description    TEXT         NOT NULL DEFAULT '',
+    budget         NUMERIC(15, 2) CHECK (budget > 0),
+    status         VARCHAR(10) CHECK (status IN ('open', 'awarded', 'cancelled')) DEFAULT 'open',
+    awarded_bid_id INT REFERENCES bids (id) ON DELETE SET NULL,
+    created_at     TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at     TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (tender_id, lot_number)
+);
+
+CREATE TABLE IF NOT EXISTS bid_lots
+(
+    bid_id INT NOT NULL REFERENCES bids (id) ON DELETE CASCADE,
+    lot_id INT NOT NULL REFERENCES tender_lots (id) ON DELETE CASCADE,
+    price  NUMERIC(15, 2) CHECK (price > 0),
+    status VARCHAR(10) CHECK (status IN ('pending', 'awarded', 'rejected')) DEFAULT 'pending',
+    PRIMARY KEY (bid_id, lot_id)
+);
+
+CREATE TABLE IF NOT EXISTS tender_boq_items
+(
+    id          SERIAL PRIMARY KEY,
+    tender_id   INT          NOT NULL REFERENCES tenders (id) ON DELETE CASCADE,
+    lot_id      INT REFERENCES tender_lots (id) ON DELETE CASCADE,
+    item_number INT          NOT NULL CHECK (item_number > 0),
+    description VARCHAR(255) NOT NULL,
+    unit        VARCHAR(30)  NOT NULL,
+    quantity    NUMERIC(15, 3) CHECK (quantity > 0),
+    UNIQUE (tender_id, item_number)
+);
+
+CREATE TABLE IF NOT EXISTS bid_line_items
+(
+    bid_id      INT NOT NULL REFERENCES bids (id) ON DELETE CASCADE,
+    boq_item_id INT NOT NULL REFERENCES tender_boq_items (id) ON DELETE CASCADE,
+    unit_price  NUMERIC(15, 2) CHECK (unit_price >= 0),
+    PRIMARY KEY (bid_id, boq_item_id)
+);
+
+CREATE TABLE IF NOT EXISTS exchange_rates
+(
+    base_currency  CHAR(3)        NOT NULL,
+    quote_currency CHAR(3)        NOT NULL,
+    rate           NUMERIC(18, 8) NOT NULL CHECK (rate > 0),
+    updated_at     TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (base_currency, quote_currency)
+);
+
+CREATE TABLE IF NOT EXISTS tender_invitations
+(
+    tender_id     INT NOT NULL REFERENCES tenders (id) ON DELETE CASCADE,
+    contractor_id INT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    invited_at    TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (tender_id, contractor_id)
+);
+
+CREATE TABLE IF NOT EXISTS contractor_qualifications
+(
+    id            SERIAL PRIMARY KEY,
+    contractor_id INT          NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    type          VARCHAR(15)  NOT NULL CHECK (type IN ('license', 'certification', 'turnover')),
+    code          VARCHAR(100) NOT NULL,
+    amount        NUMERIC(15, 2),
+    currency      VARCHAR(3),
+    expires_at    TIMESTAMP,
+    status        VARCHAR(10) CHECK (status IN ('pending', 'approved', 'rejected')) DEFAULT 'pending',
+    review_note   TEXT,
+    reviewed_by   INT REFERENCES users (id) ON DELETE SET NULL,
+    reviewed_at   TIMESTAMP,
+    file_name     VARCHAR(255) NOT NULL,
+    storage_key   VARCHAR(255) NOT NULL,
+    mime_type     VARCHAR(100) NOT NULL,
+    size          BIGINT       NOT NULL CHECK (size > 0),
+    sha256        CHAR(64)     NOT NULL,
+    created_at    TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS tender_requirements
+(
+    id         SERIAL PRIMARY KEY,
+    tender_id  INT          NOT NULL REFERENCES tenders (id) ON DELETE CASCADE,
+    type       VARCHAR(15)  NOT NULL CHECK (type IN ('license', 'certification', 'turnover')),
+    code       VARCHAR(100) NOT NULL,
+    min_amount NUMERIC(15, 2),
+    UNIQUE (tender_id, type, code)
+);
+
+CREATE TABLE IF NOT EXISTS tender_evaluators
+(
+    tender_id     INT NOT NULL REFERENCES tenders (id) ON DELETE CASCADE,
+    evaluator_id  INT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    appointed_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    submitted_at  TIMESTAMP,
+    signed_off_at TIMESTAMP,
+    PRIMARY KEY (tender_id, evaluator_id)
+);
+
+CREATE TABLE IF NOT EXISTS bid_scores
+(
+    tender_id    INT           NOT NULL,
+    evaluator_id INT           NOT NULL,
+    bid_id       INT           NOT NULL REFERENCES bids (id) ON DELETE CASCADE,
+    score        NUMERIC(5, 2) NOT NULL CHECK (score >= 0 AND score <= 100),
+    comment      TEXT,
+    updated_at   TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (bid_id, evaluator_id),
+    FOREIGN KEY (tender_id, evaluator_id) REFERENCES tender_evaluators (tender_id, evaluator_id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS coi_declarations
+(
+    id           SERIAL PRIMARY KEY,
+    tender_id    INT     NOT NULL REFERENCES tenders (id) ON DELETE CASCADE,
+    user_id      INT     NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    statement    TEXT,
+    has_conflict BOOLEAN NOT NULL,
+    signed_at    TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS coi_declaration_bidders
+(
+    declaration_id INT     NOT NULL REFERENCES coi_declarations (id) ON DELETE CASCADE,
+    contractor_id  INT     NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    conflict       BOOLEAN NOT NULL,
+    PRIMARY KEY (declaration_id, contractor_id)
+);
+
+CREATE TABLE IF NOT EXISTS award_protests
+(
+    id              SERIAL PRIMARY KEY,
+    tender_id       INT  NOT NULL REFERENCES tenders (id) ON DELETE CASCADE,
+    contractor_id   INT  NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    reasons         TEXT NOT NULL,
+    status          VARCHAR(14) CHECK (status IN ('pending', 'award_upheld', 'award_reversed')) DEFAULT 'pending',
+    resolution_note TEXT,
+    resolved_at     TIMESTAMP,
+    created_at      TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS protest_documents
+(
+    id          SERIAL PRIMARY KEY,
+    protest_id  INT          NOT NULL REFERENCES award_protests (id) ON DELETE CASCADE,
+    file_name   VARCHAR(255) NOT NULL,
+    storage_key VARCHAR(255) NOT NULL,
+    mime_type   VARCHAR(100) NOT NULL,
+    size        BIGINT       NOT NULL CHECK (size > 0),
+    sha256      CHAR(64)     NOT NULL,
+    created_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS contracts
+(
+    id                     SERIAL PRIMARY KEY,
+    tender_id              INT            NOT NULL REFERENCES tenders (id) ON DELETE CASCADE,
+    bid_id                 INT            NOT NULL UNIQUE REFERENCES bids (id) ON DELETE CASCADE,
+    client_id              INT            NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    contractor_id          INT            NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    price                  NUMERIC(15, 2) NOT NULL CHECK (price > 0),
+    currency               CHAR(3)        NOT NULL,
+    delivery_time          INT            NOT NULL CHECK (delivery_time > 0),
+    terms                  TEXT           NOT NULL DEFAULT '',
+    start_date             DATE           NOT NULL,
+    end_date               DATE           NOT NULL CHECK (end_date >= start_date),
+    status                 VARCHAR(10) CHECK (status IN ('pending', 'active', 'completed', 'terminated')) DEFAULT 'pending',
+    client_accepted_at     TIMESTAMP,
+    contractor_accepted_at TIMESTAMP,
+    termination_reason     TEXT,
+    created_at             TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at             TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS contract_milestones
+(
+    id                  SERIAL PRIMARY KEY,
+    contract_id         INT            NOT NULL REFERENCES contracts (id) ON DELETE CASCADE,
+    sequence            INT            NOT NULL CHECK (sequence > 0),
+    title               VARCHAR(255)   NOT NULL,
+    description         TEXT           NOT NULL DEFAULT '',
+    due_date            DATE           NOT NULL,
+    amount              NUMERIC(15, 2) NOT NULL CHECK (amount >= 0),
+    status              VARCHAR(9) CHECK (status IN ('pending', 'delivered', 'accepted', 'rejected')) DEFAULT 'pending',
+    delivery_note       TEXT,
+    delivered_at        TIMESTAMP,
+    review_comment      TEXT,
+    reviewed_at         TIMESTAMP,
+    overdue_notified_at TIMESTAMP,
+    created_at          TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at          TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (contract_id, sequence)
+);
+
+CREATE TABLE IF NOT EXISTS milestone_documents
+(
+    id           SERIAL PRIMARY KEY,
+    milestone_id INT          NOT NULL REFERENCES contract_milestones (id) ON DELETE CASCADE,
+    file_name    VARCHAR(255) NOT NULL,
+    storage_key  VARCHAR(255) NOT NULL,
+    mime_type    VARCHAR(100) NOT NULL,
+    size         BIGINT       NOT NULL CHECK (size > 0),
+    sha256       CHAR(64)     NOT NULL,
+    created_at   TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS invoice_counters
+(
+    contractor_id INT PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+    last_sequence INT NOT NULL DEFAULT 0
+);
+
+CREATE TABLE IF NOT EXISTS invoices
+(
+    id                SERIAL PRIMARY KEY,
+    contract_id       INT            NOT NULL REFERENCES contracts (id) ON DELETE CASCADE,
+    milestone_id      INT REFERENCES contract_milestones (id) ON DELETE SET NULL,
+    contractor_id     INT            NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    client_id         INT            NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    sequence          INT            NOT NULL CHECK (sequence > 0),
+    number            VARCHAR(30)    NOT NULL UNIQUE,
+    currency          CHAR(3)        NOT NULL,
+    subtotal          NUMERIC(15, 2) NOT NULL CHECK (subtotal > 0),
+    tax_total         NUMERIC(15, 2) NOT NULL DEFAULT 0 CHECK (tax_total >= 0),
+    total             NUMERIC(15, 2) NOT NULL CHECK (total > 0),
+    status            VARCHAR(9) CHECK (status IN ('submitted', 'approved', 'paid', 'disputed')) DEFAULT 'submitted',
+    notes             TEXT           NOT NULL DEFAULT '',
+    dispute_reason    TEXT,
+    payment_reference VARCHAR(255),
+    approved_at       TIMESTAMP,
+    paid_at           TIMESTAMP,
+    created_at        TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at        TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (contractor_id, sequence)
+);
+
+CREATE TABLE IF NOT EXISTS invoice_lines
+(
+    id          SERIAL PRIMARY KEY,
+    invoice_id  INT            NOT NULL REFERENCES invoices (id) ON DELETE CASCADE,
+    description VARCHAR(255)   NOT NULL,
+    quantity    NUMERIC(15, 3) NOT NULL CHECK (quantity > 0),
+    unit_price  NUMERIC(15, 2) NOT NULL CHECK (unit_price >= 0),
+    amount      NUMERIC(15, 2) NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS invoice_taxes
+(
+    id         SERIAL PRIMARY KEY,
+    invoice_id INT            NOT NULL REFERENCES invoices (id) ON DELETE CASCADE,
+    name       VARCHAR(50)    NOT NULL,
+    rate       NUMERIC(5, 2)  NOT NULL CHECK (rate >= 0 AND rate <= 100),
+    amount     NUMERIC(15, 2) NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS reviews
+(
+    id            SERIAL PRIMARY KEY,
+    contract_id   INT      NOT NULL REFERENCES contracts (id) ON DELETE CASCADE,
+    reviewer_id   INT      NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    reviewee_id   INT      NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    quality       SMALLINT NOT NULL CHECK (quality BETWEEN 1 AND 5),
+    timeliness    SMALLINT NOT NULL CHECK (timeliness BETWEEN 1 AND 5),
+    communication SMALLINT NOT NULL CHECK (communication BETWEEN 1 AND 5),
+    comment       TEXT     NOT NULL DEFAULT '',
+    created_at    TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at    TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (contract_id, reviewer_id)
+);
+
+CREATE TABLE IF NOT EXISTS admin_actions
+(
+    id          SERIAL PRIMARY KEY,
+    admin_id    INT         NOT NULL REFERENCES users (id),
+    action      VARCHAR(50) NOT NULL,
+    target_type VARCHAR(20) NOT NULL,
+    target_id   INT         NOT NULL,
+    details     TEXT        NOT NULL DEFAULT '',
+    created_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS refresh_tokens
+(
+    id         SERIAL PRIMARY KEY,
+    user_id    INT         NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    token_hash CHAR(64)    NOT NULL UNIQUE,
+    access_jti VARCHAR(64) NOT NULL,
+    two_factor BOOLEAN     NOT NULL DEFAULT FALSE,
+    expires_at TIMESTAMP   NOT NULL,
+    revoked_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS email_tokens
+(
+    id         SERIAL PRIMARY KEY,
+    user_id    INT         NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    purpose    VARCHAR(20) NOT NULL CHECK (purpose IN ('verify_email', 'reset_password')),
+    token_hash CHAR(64)    NOT NULL UNIQUE,
+    expires_at TIMESTAMP   NOT NULL,
+    used_at    TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS recovery_codes
+(
+    id        SERIAL PRIMARY KEY,
+    user_id   INT      NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    code_hash CHAR(64) NOT NULL,
+    used_at   TIMESTAMP
+);
+
+-- Databases created by an earlier version of this file already have the
+-- tables above, and CREATE TABLE IF NOT EXISTS leaves them as they were. The
+-- statements below bring them up to date and are safe to run repeatedly.
+
+-- Draft tenders with scheduled publication
+ALTER TABLE tenders ADD COLUMN IF NOT EXISTS publish_at TIMESTAMP;
+ALTER TABLE tenders DROP CONSTRAINT IF EXISTS tenders_status_check;
+ALTER TABLE tenders ADD CONSTRAINT tenders_status_check CHECK (status IN ('draft', 'open', 'closed', 'awarded'));
+
+-- Tender templates
+ALTER TABLE tenders ADD COLUMN IF NOT EXISTS evaluation_criteria TEXT NOT NULL DEFAULT '';
+
+-- Currencies
+ALTER TABLE tenders ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'USD';
+ALTER TABLE bids ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'USD';
+ALTER TABLE tender_templates ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'USD';
+
+-- Invitation-only tenders
+ALTER TABLE tenders ADD COLUMN IF NOT EXISTS visibility VARCHAR(11) CHECK (visibility IN ('public', 'invite_only')) DEFAULT 'public';
+
+-- Qualifications and the longer notification types
+ALTER TABLE notifications ALTER COLUMN type TYPE VARCHAR(32);
+
+-- Two-stage tenders
+ALTER TABLE tenders ADD COLUMN IF NOT EXISTS stage VARCHAR(3) CHECK (stage IN ('eoi', 'rfp')) DEFAULT 'rfp';
+ALTER TABLE tenders ADD COLUMN IF NOT EXISTS eoi_deadline TIMESTAMP;
+ALTER TABLE bids ADD COLUMN IF NOT EXISTS type VARCHAR(3) CHECK (type IN ('bid', 'eoi')) DEFAULT 'bid';
+ALTER TABLE bids DROP CONSTRAINT IF EXISTS bids_status_check;
+ALTER TABLE bids ALTER COLUMN status TYPE VARCHAR(11);
+ALTER TABLE bids ADD CONSTRAINT bids_status_check CHECK (status IN ('pending', 'shortlisted', 'awarded', 'rejected'));
+
+-- Award standstill period. Awards made before it existed were already final.
+ALTER TABLE tenders ADD COLUMN IF NOT EXISTS standstill_ends_at TIMESTAMP;
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns
+                   WHERE table_name = 'tenders' AND column_name = 'award_finalized_at') THEN
+        ALTER TABLE tenders ADD COLUMN award_finalized_at TIMESTAMP;
+        UPDATE tenders SET award_finalized_at = updated_at WHERE status = 'awarded';
+    END IF;
+END $$;
+
+-- User profiles and organizations
+ALTER TABLE users ADD COLUMN IF NOT EXISTS full_name VARCHAR(150) NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS phone VARCHAR(50) NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS job_title VARCHAR(100) NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS org_id INT REFERENCES organizations (id) ON DELETE SET NULL;
+ALTER TABLE tenders ADD COLUMN IF NOT EXISTS org_id INT REFERENCES organizations (id) ON DELETE SET NULL;
+ALTER TABLE bids ADD COLUMN IF NOT EXISTS org_id INT REFERENCES organizations (id) ON DELETE SET NULL;
+
+-- Organization roles. Every member could act for the organization before
+-- roles existed, so existing members become owners.
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns
+                   WHERE table_name = 'users' AND column_name = 'org_role') THEN
+        ALTER TABLE users ADD COLUMN org_role VARCHAR(20) CHECK (org_role IN ('owner', 'procurement_officer', 'evaluator', 'viewer', 'bid_manager'));
+        UPDATE users SET org_role = 'owner' WHERE org_id IS NOT NULL;
+    END IF;
+END $$;
+
+-- Platform admins and account suspension
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_role_check;
+ALTER TABLE users ADD CONSTRAINT users_role_check CHECK (role IN ('client', 'contractor', 'admin'));
+ALTER TABLE users ADD COLUMN IF NOT EXISTS suspended_at TIMESTAMP;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS suspension_reason TEXT;
+
+-- Email verification. Accounts created before it existed are treated as
+-- verified so their owners are not locked out.
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns
+                   WHERE table_name = 'users' AND column_name = 'email_verified_at') THEN
+        ALTER TABLE users ADD COLUMN email_verified_at TIMESTAMP;
+        UPDATE users SET email_verified_at = COALESCE(created_at, CURRENT_TIMESTAMP);
+    END IF;
+END $$;
+
+-- Two-factor authentication
+ALTER TABLE organizations ADD COLUMN IF NOT EXISTS require_2fa_for_awards BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS totp_secret VARCHAR(64);
+ALTER TABLE users ADD COLUMN IF NOT EXISTS totp_enabled_at TIMESTAMP;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS totp_last_step BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS two_factor BOOLEAN NOT NULL DEFAULT FALSE;